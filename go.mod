@@ -20,6 +20,7 @@ require (
 	github.com/spf13/cobra v1.8.0
 	github.com/spf13/pflag v1.0.5
 	go.yaml.in/yaml/v3 v3.0.4
+	golang.org/x/sys v0.6.0
 	sigs.k8s.io/yaml v1.4.0
 )
 
@@ -32,5 +33,4 @@ require (
 require (
 	github.com/inconshreveable/mousetrap v1.1.0 // indirect
 	github.com/kr/text v0.2.0 // indirect
-	golang.org/x/sys v0.6.0 // indirect
 )
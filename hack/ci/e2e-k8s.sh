@@ -199,6 +199,7 @@ EOF
     --retain \
     --wait=1m \
     -v=3 \
+    "--kubeconfig=${KUBECONFIG}" \
     "--config=${ARTIFACTS}/kind-config.yaml"
 
   # debug cluster version
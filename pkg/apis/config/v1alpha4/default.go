@@ -48,7 +48,8 @@ func SetDefaultsCluster(obj *Cluster) {
 		}
 	}
 	// default the pod CIDR
-	if obj.Networking.PodSubnet == "" {
+	// multi-cluster subnets are allocated when creating the cluster instead
+	if obj.Networking.PodSubnet == "" && !obj.Networking.MultiCluster {
 		obj.Networking.PodSubnet = "10.244.0.0/16"
 		if obj.Networking.IPFamily == IPv6Family {
 			// node-mask cidr default is /64 so we need a larger subnet, we use /56 following best practices
@@ -63,7 +64,7 @@ func SetDefaultsCluster(obj *Cluster) {
 	// https://github.com/kubernetes/kubernetes/blob/746404f82a28e55e0b76ffa7e40306fb88eb3317/cmd/kubeadm/app/apis/kubeadm/v1beta2/defaults.go#L32
 	// Note: kubeadm is using a /12 subnet, that may allocate a 2^20 bitmap in etcd
 	// we allocate a /16 subnet that allows 65535 services (current Kubernetes tested limit is O(10k) services)
-	if obj.Networking.ServiceSubnet == "" {
+	if obj.Networking.ServiceSubnet == "" && !obj.Networking.MultiCluster {
 		obj.Networking.ServiceSubnet = "10.96.0.0/16"
 		if obj.Networking.IPFamily == IPv6Family {
 			obj.Networking.ServiceSubnet = "fd00:10:96::/112"
@@ -30,6 +30,16 @@ type Cluster struct {
 	// control plane load balancer will be provisioned implicitly
 	Nodes []Node `yaml:"nodes,omitempty" json:"nodes,omitempty"`
 
+	// Labels are metadata for identifying the cluster itself, such as the
+	// owner or a CI job ID. They are stored on every node container and may be
+	// used to select clusters, e.g. `kind get clusters --selector owner=ci`.
+	// These are NOT Kubernetes labels, see Node.Labels for those.
+	Labels map[string]string `yaml:"labels,omitempty" json:"labels,omitempty"`
+
+	// Annotations are additional unselectable metadata for the cluster itself,
+	// stored on every node container
+	Annotations map[string]string `yaml:"annotations,omitempty" json:"annotations,omitempty"`
+
 	/* Advanced fields */
 
 	// Networking contains cluster wide network settings
@@ -112,6 +122,33 @@ type Node struct {
 
 	/* Advanced fields */
 
+	// IPv4Address is a static IPv4 address for the node container on the
+	// cluster's container network, if unset the runtime will assign one.
+	// The address must be within the network's IPv4 subnet, either
+	// Networking.ContainerNetwork.IPv4Subnet or that of an existing network.
+	// Networks kind creates with a configured subnet leave its upper half to
+	// the runtime for dynamically assigned addresses, so the address must be
+	// within the lower half, as it must be outside of the dynamic address
+	// range of existing networks that have one.
+	IPv4Address string `yaml:"ipv4Address,omitempty" json:"ipv4Address,omitempty"`
+
+	// IPv6Address is a static IPv6 address for the node container on the
+	// cluster's container network, if unset the runtime will assign one.
+	// The address must be within the network's IPv6 subnet, either
+	// Networking.ContainerNetwork.IPv6Subnet or that of an existing network.
+	// Networks kind creates with a configured subnet leave its upper half to
+	// the runtime for dynamically assigned addresses, so the address must be
+	// within the lower half, as it must be outside of the dynamic address
+	// range of existing networks that have one.
+	IPv6Address string `yaml:"ipv6Address,omitempty" json:"ipv6Address,omitempty"`
+
+	// ExtraNetworks are additional existing container networks the node
+	// container is connected to after creation.
+	// The cluster's container network remains the primary network, used for
+	// the node's Kubernetes address, the extra interfaces are left for
+	// workloads to use.
+	ExtraNetworks []NodeNetwork `yaml:"extraNetworks,omitempty" json:"extraNetworks,omitempty"`
+
 	// TODO: cri-like types should be inline instead
 	// ExtraMounts describes additional mount points for the node container
 	// These may be used to bind a hostPath
@@ -150,6 +187,16 @@ type Node struct {
 	KubeadmConfigPatchesJSON6902 []PatchJSON6902 `yaml:"kubeadmConfigPatchesJSON6902,omitempty" json:"kubeadmConfigPatchesJSON6902,omitempty"`
 }
 
+// NodeNetwork is an additional container network for a node
+type NodeNetwork struct {
+	// Name is the name of the existing container network
+	Name string `yaml:"name" json:"name"`
+	// IPv4Address is an optional static IPv4 address for the node on this network
+	IPv4Address string `yaml:"ipv4Address,omitempty" json:"ipv4Address,omitempty"`
+	// IPv6Address is an optional static IPv6 address for the node on this network
+	IPv6Address string `yaml:"ipv6Address,omitempty" json:"ipv6Address,omitempty"`
+}
+
 // NodeRole defines possible role for nodes in a Kubernetes cluster managed by `kind`
 type NodeRole string
 
@@ -194,6 +241,41 @@ type Networking struct {
 	KubeProxyMode ProxyMode `yaml:"kubeProxyMode,omitempty" json:"kubeProxyMode,omitempty"`
 	// DNSSearch defines the DNS search domain to use for nodes. If not set, this will be inherited from the host.
 	DNSSearch *[]string `yaml:"dnsSearch,omitempty" json:"dnsSearch,omitempty"`
+	// ContainerNetwork configures the container runtime network the nodes
+	// are attached to
+	ContainerNetwork ContainerNetwork `yaml:"containerNetwork,omitempty" json:"containerNetwork,omitempty"`
+	// MultiCluster makes the cluster aware of the other multi-cluster clusters
+	// sharing its container network.
+	// Unset pod and service subnets are allocated so they do not overlap with
+	// other clusters, and routes are installed between the pod subnets of the
+	// multi-cluster clusters.
+	MultiCluster bool `yaml:"multiCluster,omitempty" json:"multiCluster,omitempty"`
+}
+
+// ContainerNetwork configures the container runtime (docker, podman...)
+// network that nodes are attached to.
+//
+// If a network with this name already exists it will be used as-is, as long
+// as the settings specified here match the existing network.
+type ContainerNetwork struct {
+	// Name is the name of the network
+	// Defaults to "kind"
+	Name string `yaml:"name,omitempty" json:"name,omitempty"`
+	// IPv4Subnet is the CIDR used for node IPv4 addresses
+	// The container runtime will select one if unspecified
+	IPv4Subnet string `yaml:"ipv4Subnet,omitempty" json:"ipv4Subnet,omitempty"`
+	// IPv4Gateway is the IPv4 gateway address within IPv4Subnet
+	// The container runtime will select one if unspecified
+	IPv4Gateway string `yaml:"ipv4Gateway,omitempty" json:"ipv4Gateway,omitempty"`
+	// IPv6Subnet is the CIDR used for node IPv6 addresses
+	// kind will generate a unique local address subnet if unspecified
+	IPv6Subnet string `yaml:"ipv6Subnet,omitempty" json:"ipv6Subnet,omitempty"`
+	// IPv6Gateway is the IPv6 gateway address within IPv6Subnet
+	// The container runtime will select one if unspecified
+	IPv6Gateway string `yaml:"ipv6Gateway,omitempty" json:"ipv6Gateway,omitempty"`
+	// MTU is the network MTU
+	// Defaults to the MTU of the container runtime's default network
+	MTU int32 `yaml:"mtu,omitempty" json:"mtu,omitempty"`
 }
 
 // ClusterIPFamily defines cluster network IP family
@@ -39,7 +39,6 @@ func Build(options ...Option) error {
 		image:     DefaultImage,
 		baseImage: DefaultBaseImage,
 		logger:    log.NoopLogger{},
-		arch:      runtime.GOARCH,
 	}
 
 	// apply user options
@@ -49,9 +48,15 @@ func Build(options ...Option) error {
 		}
 	}
 
-	// verify that we're using a supported arch
-	if !supportedArch(ctx.arch) {
-		ctx.logger.Warnf("unsupported architecture %q", ctx.arch)
+	if len(ctx.archs) == 0 {
+		ctx.archs = []string{runtime.GOARCH}
+	}
+
+	// verify that we're using supported archs
+	for _, arch := range ctx.archs {
+		if !supportedArch(arch) {
+			ctx.logger.Warnf("unsupported architecture %q", arch)
+		}
 	}
 
 	if ctx.buildType == "" {
@@ -61,46 +66,63 @@ func Build(options ...Option) error {
 		}
 	}
 
-	if ctx.buildType == "url" {
-		ctx.logger.V(0).Infof("Building using URL: %q", ctx.kubeParam)
-		builder, err := kube.NewURLBuilder(ctx.logger, ctx.kubeParam)
+	// multiple architectures are assembled into an image index
+	if len(ctx.archs) > 1 || ctx.ociArchive != "" {
+		return buildMultiArch(ctx)
+	}
+
+	ctx.arch = ctx.archs[0]
+	if err := ctx.initBuilder(); err != nil {
+		return err
+	}
+
+	// do the actual build
+	return ctx.Build()
+}
+
+// initBuilder initializes the Kubernetes builder for the build type and
+// architecture of c
+func (c *buildContext) initBuilder() error {
+	if c.buildType == "url" {
+		c.logger.V(0).Infof("Building using URL: %q", c.kubeParam)
+		builder, err := kube.NewURLBuilder(c.logger, c.kubeParam)
 		if err != nil {
 			return err
 		}
-		ctx.builder = builder
+		c.builder = builder
 	}
 
-	if ctx.buildType == "file" {
-		ctx.logger.V(0).Infof("Building using local file: %q", ctx.kubeParam)
-		if info, err := os.Stat(ctx.kubeParam); err == nil && info.Mode().IsRegular() {
-			builder, err := kube.NewTarballBuilder(ctx.logger, ctx.kubeParam)
+	if c.buildType == "file" {
+		c.logger.V(0).Infof("Building using local file: %q", c.kubeParam)
+		if info, err := os.Stat(c.kubeParam); err == nil && info.Mode().IsRegular() {
+			builder, err := kube.NewTarballBuilder(c.logger, c.kubeParam)
 			if err != nil {
 				return err
 			}
-			ctx.builder = builder
+			c.builder = builder
 		}
 	}
 
-	if ctx.buildType == "release" {
-		ctx.logger.V(0).Infof("Building using release %q artifacts", ctx.kubeParam)
-		kubever, err := version.ParseSemantic(ctx.kubeParam)
+	if c.buildType == "release" {
+		c.logger.V(0).Infof("Building using release %q artifacts", c.kubeParam)
+		kubever, err := version.ParseSemantic(c.kubeParam)
 		if err == nil {
-			builder, err := kube.NewReleaseBuilder(ctx.logger, "v"+kubever.String(), ctx.arch)
+			builder, err := kube.NewReleaseBuilder(c.logger, "v"+kubever.String(), c.arch)
 			if err != nil {
 				return err
 			}
-			ctx.builder = builder
+			c.builder = builder
 		} else {
-			if _, err := os.Stat(ctx.kubeParam); err != nil {
-				ctx.logger.V(0).Infof("%s is not a valid kubernetes version", ctx.kubeParam)
-				return fmt.Errorf("%s is not a valid kubernetes version", ctx.kubeParam)
+			if _, err := os.Stat(c.kubeParam); err != nil {
+				c.logger.V(0).Infof("%s is not a valid kubernetes version", c.kubeParam)
+				return fmt.Errorf("%s is not a valid kubernetes version", c.kubeParam)
 			}
 		}
 	}
 
-	if ctx.buildType == "ci" {
-		ctx.logger.V(0).Infof("Building using CI %q artifacts", ctx.kubeParam)
-		marker := ctx.kubeParam
+	if c.buildType == "ci" {
+		c.logger.V(0).Infof("Building using CI %q artifacts", c.kubeParam)
+		marker := c.kubeParam
 		if marker == "" {
 			marker = "latest"
 		}
@@ -111,39 +133,38 @@ func Build(options ...Option) error {
 			marker = marker + ".txt"
 		}
 		markerURL := "https://dl.k8s.io/" + marker
-		resolvedVersion, err := fetchURL(ctx.logger, markerURL)
+		resolvedVersion, err := fetchURL(c.logger, markerURL)
 		if err != nil {
 			return errors.Wrapf(err, "error resolving CI version from %s", markerURL)
 		}
-		ctx.logger.V(0).Infof("Resolved CI version: %s", resolvedVersion)
-		builder, err := kube.NewCIBuilder(ctx.logger, resolvedVersion, ctx.arch)
+		c.logger.V(0).Infof("Resolved CI version: %s", resolvedVersion)
+		builder, err := kube.NewCIBuilder(c.logger, resolvedVersion, c.arch)
 		if err != nil {
 			return err
 		}
-		ctx.builder = builder
+		c.builder = builder
 	}
 
-	if ctx.builder == nil {
+	if c.builder == nil {
 		// locate sources if no kubernetes source was specified
-		if ctx.kubeParam == "" {
+		if c.kubeParam == "" {
 			kubeRoot, err := kube.FindSource()
 			if err != nil {
 				return errors.Wrap(err, "error finding kuberoot")
 			}
-			ctx.kubeParam = kubeRoot
+			c.kubeParam = kubeRoot
 		}
-		ctx.logger.V(0).Infof("Building using source: %q", ctx.kubeParam)
+		c.logger.V(0).Infof("Building using source: %q", c.kubeParam)
 
 		// initialize bits
-		builder, err := kube.NewDockerBuilder(ctx.logger, ctx.kubeParam, ctx.arch)
+		builder, err := kube.NewDockerBuilder(c.logger, c.kubeParam, c.arch)
 		if err != nil {
 			return err
 		}
-		ctx.builder = builder
+		c.builder = builder
 	}
 
-	// do the actual build
-	return ctx.Build()
+	return nil
 }
 
 // detectBuildType detect the type of build required based on the param passed in the following order
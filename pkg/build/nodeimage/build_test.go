@@ -96,3 +96,43 @@ func TestDetectBuildType(t *testing.T) {
 		})
 	}
 }
+
+func TestArchImage(t *testing.T) {
+	cases := []struct {
+		image    string
+		expected string
+	}{
+		{
+			image:    "kindest/node:latest",
+			expected: "kindest/node:latest-arm64",
+		},
+		{
+			image:    "kindest/node",
+			expected: "kindest/node:arm64",
+		},
+		{
+			image:    "localhost:5001/kindest/node",
+			expected: "localhost:5001/kindest/node:arm64",
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.image, func(t *testing.T) {
+			actual := archImage(tc.image, "arm64")
+			if actual != tc.expected {
+				t.Errorf("archImage(%q) = %q, expected %q", tc.image, actual, tc.expected)
+			}
+		})
+	}
+}
+
+func TestWithArchitectures(t *testing.T) {
+	ctx := &buildContext{}
+	if err := WithArchitectures("amd64", "", "arm64", "amd64").apply(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(ctx.archs) != 2 || ctx.archs[0] != "amd64" || ctx.archs[1] != "arm64" {
+		t.Errorf("archs = %v, expected [amd64 arm64]", ctx.archs)
+	}
+}
@@ -47,13 +47,15 @@ const (
 // build configuration
 type buildContext struct {
 	// option fields
-	image     string
-	baseImage string
-	logger    log.Logger
-	arch      string
-	buildType string
-	kubeParam string
+	image      string
+	baseImage  string
+	logger     log.Logger
+	archs      []string
+	buildType  string
+	kubeParam  string
+	ociArchive string
 	// non-option fields
+	arch    string
 	builder kube.Builder
 }
 
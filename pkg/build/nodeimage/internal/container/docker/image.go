@@ -73,6 +73,11 @@ func ImageInspect(containerNameOrID, format string) ([]string, error) {
 	return exec.OutputLines(cmd)
 }
 
+// RemoveImage removes the image tag, as in `docker rmi`
+func RemoveImage(image string) error {
+	return exec.Command("docker", "rmi", image).Run()
+}
+
 // ImageID return the Id of the container image
 func ImageID(containerNameOrID string) (string, error) {
 	lines, err := ImageInspect(containerNameOrID, "{{ .Id }}")
@@ -0,0 +1,37 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package docker
+
+import (
+	"strings"
+
+	"sigs.k8s.io/kind/pkg/errors"
+	"sigs.k8s.io/kind/pkg/exec"
+	"sigs.k8s.io/kind/pkg/internal/version"
+)
+
+// ServerVersion returns the version of the docker daemon
+func ServerVersion() (*version.Version, error) {
+	lines, err := exec.OutputLines(exec.Command("docker", "version", "--format", "{{.Server.Version}}"))
+	if err != nil {
+		return nil, err
+	}
+	if len(lines) != 1 {
+		return nil, errors.Errorf("docker version should only be one line, got %d lines", len(lines))
+	}
+	return version.ParseGeneric(strings.TrimSpace(lines[0]))
+}
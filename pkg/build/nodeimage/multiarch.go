@@ -0,0 +1,129 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeimage
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"sigs.k8s.io/kind/pkg/errors"
+	"sigs.k8s.io/kind/pkg/fs"
+
+	"sigs.k8s.io/kind/pkg/build/nodeimage/internal/container/docker"
+	"sigs.k8s.io/kind/pkg/internal/imageload"
+	"sigs.k8s.io/kind/pkg/internal/version"
+)
+
+// minMultiArchDockerVersion is the first docker version saving images as
+// OCI image layout archives, which the index is assembled from
+const minMultiArchDockerVersion = "25.0.0"
+
+// buildMultiArch builds a node image for each architecture and assembles
+// them into an OCI image index, which is pushed to the registry of the
+// image or saved to an OCI image layout archive.
+// The image of each architecture is removed from docker once saved.
+func buildMultiArch(ctx *buildContext) error {
+	if len(ctx.archs) > 1 && (ctx.buildType == "url" || ctx.buildType == "file") {
+		return errors.Errorf("%s builds contain a single architecture and cannot build %s", ctx.buildType, strings.Join(ctx.archs, ","))
+	}
+	// fail before the lengthy builds if the index cannot be assembled or pushed
+	v, err := docker.ServerVersion()
+	if err != nil {
+		return errors.Wrap(err, "failed to check docker version")
+	}
+	if !v.AtLeast(version.MustParseGeneric(minMultiArchDockerVersion)) {
+		return errors.Errorf("docker version %q is too old to build multi-architecture images, please upgrade to %q or later", v, minMultiArchDockerVersion)
+	}
+	if ctx.ociArchive == "" {
+		if err := imageload.CheckPushTarget(ctx.image); err != nil {
+			return errors.Wrapf(err, "multi-architecture image %q must be pushed to a local registry, E.G. localhost:5001/kindest/node:latest, or saved with an OCI archive", ctx.image)
+		}
+	}
+
+	dir, err := fs.TempDir("", "kind-node-image-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	archives := make([]imageload.PlatformArchive, 0, len(ctx.archs))
+	for _, arch := range ctx.archs {
+		archCtx := *ctx
+		archCtx.arch = arch
+		archCtx.image = archImage(ctx.image, arch)
+		archCtx.builder = nil
+		ctx.logger.V(0).Infof("Building node image %q for %s ...", archCtx.image, arch)
+		if err := archCtx.initBuilder(); err != nil {
+			return err
+		}
+		if err := archCtx.Build(); err != nil {
+			return errors.Wrapf(err, "failed to build node image for %s", arch)
+		}
+		archive := filepath.Join(dir, arch+".tar")
+		saveErr := docker.Save(archCtx.image, archive)
+		// the image is only needed to save it, only the index is kept
+		if err := docker.RemoveImage(archCtx.image); err != nil {
+			ctx.logger.Warnf("failed to remove image %q: %v", archCtx.image, err)
+		}
+		if saveErr != nil {
+			return errors.Wrapf(saveErr, "failed to save image %q", archCtx.image)
+		}
+		archives = append(archives, imageload.PlatformArchive{
+			Platform: imageload.Platform{OS: "linux", Architecture: arch},
+			Path:     archive,
+		})
+	}
+
+	if ctx.ociArchive != "" {
+		ctx.logger.V(0).Infof("Saving image index %q to %q ...", ctx.image, ctx.ociArchive)
+		if err := writeIndexArchive(ctx.ociArchive, ctx.image, archives); err != nil {
+			return errors.Wrapf(err, "failed to save image index to %q", ctx.ociArchive)
+		}
+	} else {
+		ctx.logger.V(0).Infof("Pushing image index %q ...", ctx.image)
+		if err := imageload.PushIndex(ctx.image, archives); err != nil {
+			return errors.Wrapf(err, "failed to push image index %q", ctx.image)
+		}
+	}
+	ctx.logger.V(0).Infof("Image index %q for %s build completed.", ctx.image, strings.Join(ctx.archs, ", "))
+	return nil
+}
+
+// writeIndexArchive writes the image index of archives to an OCI image
+// layout archive at path
+func writeIndexArchive(path, image string, archives []imageload.PlatformArchive) (err error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+	return imageload.WriteIndexArchive(f, image, archives)
+}
+
+// archImage returns the tag of the architecture image of image, E.G.
+// kindest/node:latest-arm64
+func archImage(image, arch string) string {
+	if strings.LastIndex(image, ":") > strings.LastIndex(image, "/") {
+		return image + "-" + arch
+	}
+	return image + ":" + arch
+}
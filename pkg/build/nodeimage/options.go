@@ -67,12 +67,39 @@ func WithLogger(logger log.Logger) Option {
 func WithArch(arch string) Option {
 	return optionAdapter(func(b *buildContext) error {
 		if arch != "" {
-			b.arch = arch
+			b.archs = []string{arch}
 		}
 		return nil
 	})
 }
 
+// WithArchitectures sets the architectures to build for, building more
+// than one assembles the images into an OCI image index.
+// Empty and duplicate architectures are ignored.
+func WithArchitectures(archs ...string) Option {
+	return optionAdapter(func(b *buildContext) error {
+		b.archs = nil
+		seen := map[string]bool{}
+		for _, arch := range archs {
+			if arch != "" && !seen[arch] {
+				seen[arch] = true
+				b.archs = append(b.archs, arch)
+			}
+		}
+		return nil
+	})
+}
+
+// WithOCIArchive configures a build to save the image index to an OCI
+// image layout archive at path, instead of pushing it to the registry of
+// the image
+func WithOCIArchive(path string) Option {
+	return optionAdapter(func(b *buildContext) error {
+		b.ociArchive = path
+		return nil
+	})
+}
+
 // WithBuildType sets the build type to perform
 func WithBuildType(buildType string) Option {
 	return optionAdapter(func(b *buildContext) error {
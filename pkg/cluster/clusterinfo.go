@@ -0,0 +1,117 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"time"
+
+	"sigs.k8s.io/kind/pkg/cluster/nodeutils"
+	"sigs.k8s.io/kind/pkg/errors"
+
+	"sigs.k8s.io/kind/pkg/cluster/internal/activity"
+)
+
+// ClusterInfo describes an existing cluster, see Provider.ListClustersWithInfo
+type ClusterInfo struct {
+	Name string `json:"name"`
+	// KubernetesVersion is empty if it could not be determined,
+	// E.G. because the nodes are not running
+	KubernetesVersion string `json:"kubernetesVersion,omitempty"`
+	// Nodes is the number of Kubernetes nodes, excluding the load balancer
+	Nodes int `json:"nodes"`
+	// APIServerEndpoint is the host endpoint of the API server, empty if it
+	// could not be determined
+	APIServerEndpoint string            `json:"apiServerEndpoint,omitempty"`
+	CreationTimestamp time.Time         `json:"creationTimestamp"`
+	Labels            map[string]string `json:"labels,omitempty"`
+	Annotations       map[string]string `json:"annotations,omitempty"`
+}
+
+// ListClustersWithInfo returns info about each cluster for which nodes exist.
+// Optional fields that cannot be determined for a cluster are left empty.
+// Clusters whose nodes or metadata cannot be read, E.G. because they were
+// deleted while listing, are omitted rather than failing the entire list.
+func (p *Provider) ListClustersWithInfo() ([]ClusterInfo, error) {
+	clusters, err := p.provider.ListClusters()
+	if err != nil {
+		return nil, err
+	}
+	found := make([]*ClusterInfo, len(clusters))
+	fns := make([]func() error, 0, len(clusters))
+	for i, name := range clusters {
+		i, name := i, name // capture loop variables
+		fns = append(fns, func() error {
+			info, err := p.clusterInfo(name)
+			if err != nil {
+				p.logger.V(1).Infof("Skipping cluster %q: %v", name, err)
+				return nil
+			}
+			found[i] = info
+			return nil
+		})
+	}
+	if err := errors.AggregateConcurrent(fns); err != nil {
+		return nil, err
+	}
+	infos := make([]ClusterInfo, 0, len(found))
+	for _, info := range found {
+		if info != nil {
+			infos = append(infos, *info)
+		}
+	}
+	return infos, nil
+}
+
+func (p *Provider) clusterInfo(name string) (*ClusterInfo, error) {
+	info := &ClusterInfo{Name: name}
+	allNodes, err := p.provider.ListNodes(name)
+	if err != nil {
+		return nil, err
+	}
+	metadata, err := p.provider.GetClusterMetadata(name)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get metadata for cluster %q", name)
+	}
+	info.CreationTimestamp = metadata.CreationTimestamp
+	info.Labels = metadata.Labels
+	info.Annotations = metadata.Annotations
+
+	if kubeNodes, err := nodeutils.InternalNodes(allNodes); err == nil {
+		info.Nodes = len(kubeNodes)
+	}
+	if controlPlanes, err := nodeutils.ControlPlaneNodes(allNodes); err == nil && len(controlPlanes) > 0 {
+		if version, err := nodeutils.KubeVersion(controlPlanes[0]); err == nil {
+			info.KubernetesVersion = version
+		} else {
+			p.logger.V(1).Infof("Failed to get Kubernetes version of cluster %q: %v", name, err)
+		}
+	}
+	if endpoint, err := p.provider.GetAPIServerEndpoint(name); err == nil {
+		info.APIServerEndpoint = endpoint
+	} else {
+		p.logger.V(1).Infof("Failed to get API server endpoint of cluster %q: %v", name, err)
+	}
+	return info, nil
+}
+
+// APIServerClients returns the number of open connections to the cluster's
+// API server from clients outside of the cluster, E.G. kubectl on the host.
+// This is best effort, clients are only observed while they hold a
+// connection open.
+func (p *Provider) APIServerClients(name string) (int, error) {
+	return activity.APIServerClients(p.provider, defaultName(name))
+}
@@ -38,6 +38,12 @@ const (
 	// kubernetes nodes
 	ExternalLoadBalancerNodeRoleValue string = "external-load-balancer"
 
+	// PortForwarderNodeRoleValue identifies a node that forwards a host port
+	// to a port on another node, see `kind expose`.
+	//
+	// Please note that `kind` nodes forwarding ports are not kubernetes nodes
+	PortForwarderNodeRoleValue string = "port-forwarder"
+
 	// ExternalEtcdNodeRoleValue identifies a node that hosts an external-etcd
 	// instance.
 	//
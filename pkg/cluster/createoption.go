@@ -70,6 +70,44 @@ func CreateWithNodeImage(nodeImage string) CreateOption {
 	})
 }
 
+// CreateWithDefaultNodeImage overrides the image of nodes in config using the
+// built-in default node image, unlike CreateWithNodeImage this does not
+// override images set in the config
+func CreateWithDefaultNodeImage(nodeImage string) CreateOption {
+	return createOptionAdapter(func(o *internalcreate.ClusterOptions) error {
+		o.DefaultNodeImage = nodeImage
+		return nil
+	})
+}
+
+// CreateWithLabels adds labels to the cluster, overriding any labels
+// with the same keys in the config
+func CreateWithLabels(labels map[string]string) CreateOption {
+	return createOptionAdapter(func(o *internalcreate.ClusterOptions) error {
+		if o.Labels == nil {
+			o.Labels = map[string]string{}
+		}
+		for k, v := range labels {
+			o.Labels[k] = v
+		}
+		return nil
+	})
+}
+
+// CreateWithAnnotations adds annotations to the cluster, overriding any
+// annotations with the same keys in the config
+func CreateWithAnnotations(annotations map[string]string) CreateOption {
+	return createOptionAdapter(func(o *internalcreate.ClusterOptions) error {
+		if o.Annotations == nil {
+			o.Annotations = map[string]string{}
+		}
+		for k, v := range annotations {
+			o.Annotations[k] = v
+		}
+		return nil
+	})
+}
+
 // CreateWithRetain disables deletion of nodes and any other cleanup
 // that would normally occur after a failure to create
 // This is mainly used for debugging purposes
@@ -89,6 +127,35 @@ func CreateWithWaitForReady(waitTime time.Duration) CreateOption {
 	})
 }
 
+// CreateWithWaitFor selects what CreateWithWaitForReady waits for, one or more
+// of "control-plane", "nodes", "system-pods", "cni", "storage" and "dns".
+// By default only the control plane node(s) are waited for
+func CreateWithWaitFor(gates []string) CreateOption {
+	return createOptionAdapter(func(o *internalcreate.ClusterOptions) error {
+		o.WaitFor = gates
+		return nil
+	})
+}
+
+// CreateWithFailOnWaitTimeout makes create fail if the cluster is not ready
+// within the CreateWithWaitForReady wait time, rather than only warning
+func CreateWithFailOnWaitTimeout(failOnWaitTimeout bool) CreateOption {
+	return createOptionAdapter(func(o *internalcreate.ClusterOptions) error {
+		o.FailOnWaitTimeout = failOnWaitTimeout
+		return nil
+	})
+}
+
+// CreateWithLockTimeout configures how long to wait for another process
+// creating or deleting a cluster with the same name to finish.
+// By default creation fails immediately.
+func CreateWithLockTimeout(timeout time.Duration) CreateOption {
+	return createOptionAdapter(func(o *internalcreate.ClusterOptions) error {
+		o.LockTimeout = timeout
+		return nil
+	})
+}
+
 // CreateWithKubeconfigPath sets the explicit --kubeconfig path
 func CreateWithKubeconfigPath(explicitPath string) CreateOption {
 	return createOptionAdapter(func(o *internalcreate.ClusterOptions) error {
@@ -0,0 +1,52 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"time"
+
+	internaldelete "sigs.k8s.io/kind/pkg/cluster/internal/delete"
+)
+
+// DeleteOption is a Provider.DeleteWithOptions option
+type DeleteOption interface {
+	apply(*internaldelete.Options) error
+}
+
+type deleteOptionAdapter func(*internaldelete.Options) error
+
+func (c deleteOptionAdapter) apply(o *internaldelete.Options) error {
+	return c(o)
+}
+
+// DeleteWithLockTimeout configures how long to wait for another process
+// creating or deleting the cluster to finish, by default five minutes
+func DeleteWithLockTimeout(timeout time.Duration) DeleteOption {
+	return deleteOptionAdapter(func(o *internaldelete.Options) error {
+		o.LockTimeout = timeout
+		return nil
+	})
+}
+
+// DeleteWithForce deletes the cluster even if another process is still
+// creating or deleting it after the lock timeout, making that process fail
+func DeleteWithForce(force bool) DeleteOption {
+	return deleteOptionAdapter(func(o *internaldelete.Options) error {
+		o.Force = force
+		return nil
+	})
+}
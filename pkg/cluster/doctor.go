@@ -0,0 +1,51 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"sigs.k8s.io/kind/pkg/internal/apis/config"
+	internalencoding "sigs.k8s.io/kind/pkg/internal/apis/config/encoding"
+
+	"sigs.k8s.io/kind/pkg/cluster/internal/doctor"
+)
+
+// DoctorResult is the result of a host environment check, see Provider.Doctor
+type DoctorResult = doctor.Result
+
+// DoctorStatus is the outcome of a host environment check
+type DoctorStatus = doctor.Status
+
+const (
+	// DoctorPass means no problem was found
+	DoctorPass = doctor.Pass
+	// DoctorWarn means cluster creation may fail or the cluster may misbehave
+	DoctorWarn = doctor.Warn
+	// DoctorFail means cluster creation is expected to fail
+	DoctorFail = doctor.Fail
+)
+
+// Doctor checks the host environment for common causes of cluster creation
+// failures, configPath is the optional config file the cluster would be
+// created with
+func (p *Provider) Doctor(configPath string) ([]DoctorResult, error) {
+	cfg, err := internalencoding.Load(configPath)
+	if err != nil {
+		return nil, err
+	}
+	config.SetDefaultsCluster(cfg)
+	return doctor.Run(p.provider, cfg), nil
+}
@@ -0,0 +1,181 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package activity contains the logic for detecting whether a cluster's
+// API server is in use by clients outside of the cluster.
+package activity
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"sigs.k8s.io/kind/pkg/cluster/nodes"
+	"sigs.k8s.io/kind/pkg/cluster/nodeutils"
+	"sigs.k8s.io/kind/pkg/errors"
+	"sigs.k8s.io/kind/pkg/exec"
+
+	"sigs.k8s.io/kind/pkg/cluster/internal/providers"
+	"sigs.k8s.io/kind/pkg/cluster/internal/providers/common"
+)
+
+// APIServerClients returns the number of open connections to the cluster's
+// API server from outside of the cluster, E.G. from kubectl on the host.
+// Connections from the nodes and pods of the cluster itself are ignored.
+//
+// This is best effort: clients that do not hold a connection open between
+// requests are only observed while a request is in flight.
+func APIServerClients(p providers.Provider, name string) (int, error) {
+	allNodes, err := p.ListNodes(name)
+	if err != nil {
+		return 0, errors.Wrap(err, "error listing nodes")
+	}
+	controlPlanes, err := nodeutils.ControlPlaneNodes(allNodes)
+	if err != nil {
+		return 0, err
+	}
+	if len(controlPlanes) == 0 {
+		return 0, errors.Errorf("no control plane nodes found for cluster %q", name)
+	}
+	// clients outside the cluster connect via the load balancer, if any
+	target := controlPlanes[0]
+	loadBalancer, err := nodeutils.ExternalLoadBalancerNode(allNodes)
+	if err != nil {
+		return 0, err
+	}
+	if loadBalancer != nil {
+		target = loadBalancer
+	}
+
+	internal, err := internalNetworks(allNodes, controlPlanes[0])
+	if err != nil {
+		return 0, err
+	}
+	lines, err := exec.OutputLines(target.Command("cat", "/proc/net/tcp", "/proc/net/tcp6"))
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to read connections on node %q", target.String())
+	}
+	clients := 0
+	for _, remote := range establishedRemotes(lines, common.APIServerInternalPort) {
+		if !containedIn(remote, internal) {
+			clients++
+		}
+	}
+	return clients, nil
+}
+
+// internalNetworks returns the node addresses and pod subnets of the cluster
+func internalNetworks(allNodes []nodes.Node, controlPlane nodes.Node) ([]*net.IPNet, error) {
+	networks := []*net.IPNet{}
+	for _, n := range allNodes {
+		ipv4, ipv6, err := n.IP()
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get IP for node %q", n.String())
+		}
+		for _, address := range []string{ipv4, ipv6} {
+			if ip := net.ParseIP(address); ip != nil {
+				networks = append(networks, &net.IPNet{IP: ip, Mask: net.CIDRMask(len(ip)*8, len(ip)*8)})
+			}
+		}
+	}
+	kubeadmConfig, err := exec.Output(controlPlane.Command("cat", "/kind/kubeadm.conf"))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read kubeadm config")
+	}
+	return append(networks, parsePodSubnets(string(kubeadmConfig))...), nil
+}
+
+var podSubnetRE = regexp.MustCompile(`(?m)^\s*podSubnet:\s*"?([^"\s]+)"?\s*$`)
+
+// parsePodSubnets returns the pod subnets in a kubeadm config
+func parsePodSubnets(kubeadmConfig string) []*net.IPNet {
+	subnets := []*net.IPNet{}
+	for _, match := range podSubnetRE.FindAllStringSubmatch(kubeadmConfig, -1) {
+		for _, s := range strings.Split(match[1], ",") {
+			if _, subnet, err := net.ParseCIDR(strings.TrimSpace(s)); err == nil {
+				subnets = append(subnets, subnet)
+			}
+		}
+	}
+	return subnets
+}
+
+// tcpEstablished is the connection state of established sockets in /proc/net/tcp
+const tcpEstablished = "01"
+
+// establishedRemotes parses /proc/net/tcp{,6} lines, returning the remote
+// addresses of established connections to the local port
+func establishedRemotes(lines []string, port int) []net.IP {
+	remotes := []net.IP{}
+	for _, line := range lines {
+		fields := strings.Fields(line)
+		if len(fields) < 4 || fields[3] != tcpEstablished {
+			continue
+		}
+		_, localPort, err := parseAddress(fields[1])
+		if err != nil || localPort != port {
+			continue
+		}
+		remote, _, err := parseAddress(fields[2])
+		if err != nil {
+			continue
+		}
+		remotes = append(remotes, remote)
+	}
+	return remotes
+}
+
+// parseAddress parses an address from /proc/net/tcp{,6}, where the IP is
+// hex encoded as little endian 32 bit words and the port as big endian hex
+func parseAddress(s string) (net.IP, int, error) {
+	parts := strings.Split(s, ":")
+	if len(parts) != 2 {
+		return nil, 0, errors.Errorf("invalid address %q", s)
+	}
+	raw, err := hex.DecodeString(parts[0])
+	if err != nil || (len(raw) != net.IPv4len && len(raw) != net.IPv6len) {
+		return nil, 0, errors.Errorf("invalid address %q", s)
+	}
+	ip := make(net.IP, len(raw))
+	for i := 0; i < len(raw); i += 4 {
+		binary.BigEndian.PutUint32(ip[i:], binary.LittleEndian.Uint32(raw[i:]))
+	}
+	port, err := strconv.ParseUint(parts[1], 16, 16)
+	if err != nil {
+		return nil, 0, errors.Errorf("invalid address %q", s)
+	}
+	return ip, int(port), nil
+}
+
+// containedIn returns true if ip is loopback or within any of the networks
+func containedIn(ip net.IP, networks []*net.IPNet) bool {
+	// IPv4 clients of IPv6 sockets are reported as IPv4-mapped addresses
+	if ip4 := ip.To4(); ip4 != nil {
+		ip = ip4
+	}
+	if ip.IsLoopback() {
+		return true
+	}
+	for _, network := range networks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
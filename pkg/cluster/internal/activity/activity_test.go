@@ -0,0 +1,85 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package activity
+
+import (
+	"net"
+	"testing"
+
+	"sigs.k8s.io/kind/pkg/internal/assert"
+)
+
+func TestEstablishedRemotes(t *testing.T) {
+	t.Parallel()
+	lines := []string{
+		"  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode",
+		// listening on 0.0.0.0:6443
+		"   0: 00000000:192B 00000000:0000 0A 00000000:00000000 00:00000000 00000000     0        0 1 1 0000000000000000 100 0 0 10 0",
+		// established 172.18.0.2:6443 <- 172.18.0.1:50000
+		"   1: 020012AC:192B 010012AC:C350 01 00000000:00000000 00:00000000 00000000     0        0 2 1 0000000000000000 20 4 30 10 -1",
+		// established 172.18.0.2:40000 -> 172.18.0.3:6443, not to the local port
+		"   2: 020012AC:9C40 030012AC:192B 01 00000000:00000000 00:00000000 00000000     0        0 3 1 0000000000000000 20 4 30 10 -1",
+		"  sl  local_address                         remote_address                        st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode",
+		// established [::ffff:172.18.0.2]:6443 <- [::ffff:172.18.0.5]:50001
+		"   0: 0000000000000000FFFF0000020012AC:192B 0000000000000000FFFF0000050012AC:C351 01 00000000:00000000 00:00000000 00000000     0        0 4 1 0000000000000000 20 4 30 10 -1",
+		// established [fc00::2]:6443 <- [fc00::1]:50002
+		"   1: 000000FC000000000000000002000000:192B 000000FC000000000000000001000000:C352 01 00000000:00000000 00:00000000 00000000     0        0 5 1 0000000000000000 20 4 30 10 -1",
+	}
+	remotes := establishedRemotes(lines, 6443)
+	expected := []string{"172.18.0.1", "172.18.0.5", "fc00::1"}
+	if len(remotes) != len(expected) {
+		t.Fatalf("expected %d remotes, got %v", len(expected), remotes)
+	}
+	for i := range expected {
+		assert.StringEqual(t, expected[i], remotes[i].String())
+	}
+}
+
+func TestContainedIn(t *testing.T) {
+	t.Parallel()
+	_, podSubnet, _ := net.ParseCIDR("10.244.0.0/16")
+	node := &net.IPNet{IP: net.ParseIP("172.18.0.2").To4(), Mask: net.CIDRMask(32, 32)}
+	networks := []*net.IPNet{podSubnet, node}
+	cases := []struct {
+		ip       string
+		expected bool
+	}{
+		{ip: "127.0.0.1", expected: true},
+		{ip: "::1", expected: true},
+		{ip: "10.244.1.5", expected: true},
+		{ip: "::ffff:172.18.0.2", expected: true},
+		{ip: "172.18.0.1", expected: false},
+		{ip: "fc00::1", expected: false},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.ip, func(t *testing.T) {
+			t.Parallel()
+			assert.BoolEqual(t, tc.expected, containedIn(net.ParseIP(tc.ip), networks))
+		})
+	}
+}
+
+func TestParsePodSubnets(t *testing.T) {
+	t.Parallel()
+	subnets := parsePodSubnets("networking:\n  podSubnet: 10.244.0.0/16,fd00:10:244::/56\n  serviceSubnet: 10.96.0.0/16\n")
+	if len(subnets) != 2 {
+		t.Fatalf("expected 2 subnets, got %v", subnets)
+	}
+	assert.StringEqual(t, "10.244.0.0/16", subnets[0].String())
+	assert.StringEqual(t, "fd00:10:244::/56", subnets[1].String())
+}
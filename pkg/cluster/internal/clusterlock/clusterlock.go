@@ -0,0 +1,211 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package clusterlock implements a per-cluster-name lock held while a
+// cluster is being created or deleted, so that concurrent kind invocations
+// using the same cluster name do not race.
+package clusterlock
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"sigs.k8s.io/kind/pkg/errors"
+)
+
+// Operations recorded in the lock, for reporting to other callers
+const (
+	Creating          = "created"
+	Deleting          = "deleted"
+	AllocatingSubnets = "allocated subnets"
+)
+
+// subnetAllocationLock is the name of the lock file serializing multi-cluster
+// subnet allocation, it cannot collide with valid cluster names
+const subnetAllocationLock = "_subnet-allocation.lock"
+
+// pollInterval is how often a held lock is retried while waiting
+const pollInterval = 500 * time.Millisecond
+
+// HeldError is returned when the lock is held by another process
+type HeldError struct {
+	Cluster   string
+	Operation string
+	PID       int
+	Hostname  string
+}
+
+func (e *HeldError) Error() string {
+	return fmt.Sprintf("cluster %q is being %s by pid %d on %s", e.Cluster, e.Operation, e.PID, e.Hostname)
+}
+
+// owner is the content of a lock file
+type owner struct {
+	Cluster   string `json:"cluster,omitempty"`
+	PID       int    `json:"pid"`
+	Hostname  string `json:"hostname"`
+	Operation string `json:"operation"`
+}
+
+// Lock is an acquired cluster lock
+type Lock struct {
+	file *os.File
+}
+
+// Acquire acquires the lock for the cluster name, waiting up to timeout for
+// another process to release it. The lock is held with an OS file lock, so
+// it is released when the holding process exits, even if it crashed.
+func Acquire(cluster, operation string, timeout time.Duration) (*Lock, error) {
+	dir, err := lockDir()
+	if err != nil {
+		return nil, err
+	}
+	return acquire(filepath.Join(dir, cluster+".lock"), cluster, operation, timeout)
+}
+
+// AcquireSubnetAllocation acquires the host wide lock held by a multi-cluster
+// cluster from allocating its subnets until its nodes record them, waiting up
+// to timeout for another cluster to release it
+func AcquireSubnetAllocation(cluster string, timeout time.Duration) (*Lock, error) {
+	dir, err := lockDir()
+	if err != nil {
+		return nil, err
+	}
+	return acquire(filepath.Join(dir, subnetAllocationLock), cluster, AllocatingSubnets, timeout)
+}
+
+func acquire(path, cluster, operation string, timeout time.Duration) (*Lock, error) {
+	hostname, _ := os.Hostname()
+	content, err := json.Marshal(owner{
+		Cluster:   cluster,
+		PID:       os.Getpid(),
+		Hostname:  hostname,
+		Operation: operation,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for start := time.Now(); ; time.Sleep(pollInterval) {
+		f, err := openLockFile(path)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to lock cluster %q", cluster)
+		}
+		locked, err := tryLockFile(f)
+		if err != nil {
+			f.Close()
+			return nil, errors.Wrapf(err, "failed to lock cluster %q", cluster)
+		}
+		if locked {
+			// the owner is only informational, files created by another
+			// user may not be writable
+			writeOwner(f, content)
+			return &Lock{file: f}, nil
+		}
+		f.Close()
+		if time.Since(start) >= timeout {
+			// the holder may not have written the owner yet
+			current, _ := readOwner(path)
+			holder := current.Cluster
+			if holder == "" {
+				holder = cluster
+			}
+			return nil, errors.WithStack(&HeldError{
+				Cluster:   holder,
+				Operation: current.Operation,
+				PID:       current.PID,
+				Hostname:  current.Hostname,
+			})
+		}
+	}
+}
+
+// Release releases the lock.
+// The lock file is kept, removing it would race with other processes that
+// have opened it and are about to lock it.
+func (l *Lock) Release() error {
+	_ = l.file.Truncate(0)
+	if err := unlockFile(l.file); err != nil {
+		l.file.Close()
+		return err
+	}
+	return l.file.Close()
+}
+
+// openLockFile opens the lock file at path, creating it writable by every
+// user so that any of them can record themselves as the owner
+func openLockFile(path string) (*os.File, error) {
+	for {
+		// open existing files without O_CREATE, which may be refused for
+		// files of other users in sticky directories, see protected_regular
+		f, err := os.OpenFile(path, os.O_RDWR, 0)
+		if os.IsPermission(err) {
+			f, err = os.Open(path)
+		}
+		if !os.IsNotExist(err) {
+			return f, err
+		}
+		f, err = os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_RDWR, 0666)
+		if os.IsExist(err) {
+			// created by another process in the meantime
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		// not subject to the umask
+		_ = f.Chmod(0666)
+		return f, nil
+	}
+}
+
+// writeOwner replaces the content of the locked file f with content
+func writeOwner(f *os.File, content []byte) {
+	if err := f.Truncate(0); err != nil {
+		return
+	}
+	_, _ = f.WriteAt(content, 0)
+}
+
+// readOwner returns the owner recorded in the lock file at path, empty if
+// there is none
+func readOwner(path string) (*owner, error) {
+	o := &owner{}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return o, err
+	}
+	return o, json.Unmarshal(raw, o)
+}
+
+// lockDir returns the directory containing the lock files, creating it.
+// Unlike the rest of the kind state this is the same for every user and
+// ignores $KIND_STATE_DIR: clusters and their subnets are shared by every
+// kind process using the host's container runtime, so their locks must be too.
+func lockDir() (string, error) {
+	dir := filepath.Join(hostTempDir(), "kind-locks")
+	if err := os.Mkdir(dir, 0777); err == nil {
+		// sticky and world writable like /tmp, not subject to the umask
+		if err := os.Chmod(dir, os.ModeSticky|0777); err != nil {
+			return "", errors.Wrap(err, "failed to create lock directory")
+		}
+	} else if !os.IsExist(err) {
+		return "", errors.Wrap(err, "failed to create lock directory")
+	}
+	return dir, nil
+}
@@ -0,0 +1,113 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterlock
+
+import (
+	stderrors "errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"sigs.k8s.io/kind/pkg/internal/assert"
+)
+
+func TestAcquire(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "kind.lock")
+
+	lock, err := acquire(path, "kind", Creating, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// a second caller fails, identifying the owner
+	_, err = acquire(path, "kind", Deleting, 0)
+	var held *HeldError
+	if !stderrors.As(err, &held) {
+		t.Fatalf("expected HeldError, got: %v", err)
+	}
+	assert.StringEqual(t, Creating, held.Operation)
+	if held.PID != os.Getpid() {
+		t.Errorf("expected pid %d, got %d", os.Getpid(), held.PID)
+	}
+
+	// waiting succeeds once the lock is released
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		_ = lock.Release()
+	}()
+	lock, err = acquire(path, "kind", Deleting, 5*time.Second)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := lock.Release(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestAcquireLeftBehind(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "kind.lock")
+	// a lock file left behind by a process that crashed, unlocked by the OS
+	stale := `{"pid":1,"hostname":"other","operation":"created"}`
+	if err := os.WriteFile(path, []byte(stale), 0644); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	lock, err := acquire(path, "kind", Creating, 0)
+	if err != nil {
+		t.Fatalf("expected left behind lock file to be reused: %v", err)
+	}
+
+	// exiting without releasing, as when crashing, releases the OS lock
+	_ = lock.file.Close()
+	lock, err = acquire(path, "kind", Deleting, 0)
+	if err != nil {
+		t.Fatalf("expected lock of closed file to be released: %v", err)
+	}
+	_ = lock.Release()
+}
+
+func TestAcquireReportsHolder(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), subnetAllocationLock)
+
+	lock, err := acquire(path, "first", AllocatingSubnets, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer func() { _ = lock.Release() }()
+
+	// shared locks report the cluster holding them, not the caller
+	_, err = acquire(path, "second", AllocatingSubnets, 0)
+	var held *HeldError
+	if !stderrors.As(err, &held) {
+		t.Fatalf("expected HeldError, got: %v", err)
+	}
+	assert.StringEqual(t, "first", held.Cluster)
+	assert.StringEqual(t, AllocatingSubnets, held.Operation)
+}
+
+func TestLockDirIsHostWide(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Setenv("KIND_STATE_DIR", t.TempDir())
+
+	dir, err := lockDir()
+	assert.ExpectError(t, false, err)
+	assert.StringEqual(t, filepath.Join(hostTempDir(), "kind-locks"), dir)
+}
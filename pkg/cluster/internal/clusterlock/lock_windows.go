@@ -0,0 +1,57 @@
+//go:build windows
+// +build windows
+
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterlock
+
+import (
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// lockOffset is the locked byte of lock files, beyond their content so that
+// the owner stays readable, as windows locks are mandatory
+const lockOffset = 1 << 30
+
+// hostTempDir returns the directory for state shared by every user,
+// as the temporary directory is per user
+func hostTempDir() string {
+	if dir := os.Getenv("ProgramData"); dir != "" {
+		return dir
+	}
+	return os.TempDir()
+}
+
+// tryLockFile locks f without waiting, returning false if another open
+// file holds the lock
+func tryLockFile(f *os.File) (bool, error) {
+	err := windows.LockFileEx(
+		windows.Handle(f.Fd()),
+		windows.LOCKFILE_EXCLUSIVE_LOCK|windows.LOCKFILE_FAIL_IMMEDIATELY,
+		0, 1, 0, &windows.Overlapped{Offset: lockOffset},
+	)
+	if err == windows.ERROR_LOCK_VIOLATION {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func unlockFile(f *os.File) error {
+	return windows.UnlockFileEx(windows.Handle(f.Fd()), 0, 1, 0, &windows.Overlapped{Offset: lockOffset})
+}
@@ -179,10 +179,10 @@ func getKubeadmConfig(cfg *config.Cluster, data kubeadm.ConfigData, node nodes.N
 		return "", errors.Errorf("failed to match node %q to config", node.String())
 	}
 
-	// get the node ip address
-	nodeAddress, nodeAddressIPv6, err := node.IP()
+	// get the node ip address, static addresses are used as configured
+	nodeAddress, nodeAddressIPv6, err := nodeAddresses(cfg, configNode, node)
 	if err != nil {
-		return "", errors.Wrap(err, "failed to get IP for node")
+		return "", err
 	}
 
 	data.NodeAddress = nodeAddress
@@ -241,6 +241,28 @@ func getKubeadmConfig(cfg *config.Cluster, data kubeadm.ConfigData, node nodes.N
 	return removeMetadata(patchedConfig), nil
 }
 
+// nodeAddresses returns the node's IPv4 and IPv6 addresses, preferring the
+// configured static addresses and only asking the node for the rest
+func nodeAddresses(cfg *config.Cluster, configNode *config.Node, node nodes.Node) (ipv4, ipv6 string, err error) {
+	ipv4, ipv6 = configNode.IPv4Address, configNode.IPv6Address
+	needIPv4 := cfg.Networking.IPFamily != config.IPv6Family
+	needIPv6 := cfg.Networking.IPFamily != config.IPv4Family
+	if (ipv4 != "" || !needIPv4) && (ipv6 != "" || !needIPv6) {
+		return ipv4, ipv6, nil
+	}
+	nodeIPv4, nodeIPv6, err := node.IP()
+	if err != nil {
+		return "", "", errors.Wrap(err, "failed to get IP for node")
+	}
+	if ipv4 == "" {
+		ipv4 = nodeIPv4
+	}
+	if ipv6 == "" {
+		ipv6 = nodeIPv6
+	}
+	return ipv4, ipv6, nil
+}
+
 // trims out the metadata.name we put in the config for kustomize matching,
 // kubeadm will complain about this otherwise
 func removeMetadata(kustomized string) string {
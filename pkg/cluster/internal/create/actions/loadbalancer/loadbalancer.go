@@ -90,19 +90,8 @@ func (a *Action) Execute(ctx *actions.ActionContext) error {
 		return errors.Wrap(err, "failed to generate loadbalancer config data")
 	}
 
-	// Atomic Update inside the container
-	tmpLDS := loadbalancer.ProxyConfigPathLDS + ".tmp"
-	tmpCDS := loadbalancer.ProxyConfigPathCDS + ".tmp"
-
-	if err := nodeutils.WriteFile(loadBalancerNode, tmpLDS, ldsConfig); err != nil {
-		return errors.Wrap(err, "failed to copy loadbalancer config to node")
-	}
-	if err := nodeutils.WriteFile(loadBalancerNode, tmpCDS, cdsConfig); err != nil {
-		return errors.Wrap(err, "failed to copy loadbalancer config to node")
-	}
-	cmd := fmt.Sprintf("chmod 666 %s %s && mv %s %s && mv %s %s", tmpCDS, tmpLDS, tmpCDS, loadbalancer.ProxyConfigPathCDS, tmpLDS, loadbalancer.ProxyConfigPathLDS)
-	if err := loadBalancerNode.Command("sh", "-c", cmd).Run(); err != nil {
-		return errors.Wrap(err, "failed to reload envoy config")
+	if err := loadbalancer.WriteDynamicConfig(loadBalancerNode, ldsConfig, cdsConfig); err != nil {
+		return err
 	}
 
 	ctx.Status.End(true)
@@ -0,0 +1,443 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package multicluster implements an action to route pod traffic between
+// a cluster and the other multi-cluster clusters sharing its network
+package multicluster
+
+import (
+	"net"
+	"sort"
+	"strings"
+	"time"
+
+	"sigs.k8s.io/kind/pkg/cluster/nodes"
+	"sigs.k8s.io/kind/pkg/errors"
+	"sigs.k8s.io/kind/pkg/exec"
+	"sigs.k8s.io/kind/pkg/log"
+
+	"sigs.k8s.io/kind/pkg/cluster/internal/create/actions"
+	"sigs.k8s.io/kind/pkg/cluster/internal/providers"
+	"sigs.k8s.io/kind/pkg/cluster/internal/providers/common"
+	"sigs.k8s.io/kind/pkg/cluster/nodeutils"
+	"sigs.k8s.io/kind/pkg/internal/apis/config"
+)
+
+const (
+	// markerPath marks the nodes of multi-cluster clusters
+	markerPath = "/kind/multi-cluster"
+	// routesPath records the routes installed on a node, one "cidr via" per
+	// line, so that routesUnit can restore them when the node restarts
+	routesPath = "/kind/multi-cluster-routes"
+	// routesUnitPath is the systemd unit restoring the routes on boot
+	routesUnitPath = "/etc/systemd/system/kind-multi-cluster-routes.service"
+)
+
+// routesUnit restores the recorded routes, the addresses of the other
+// clusters may have changed as well, which `kind repair cluster` handles
+const routesUnit = `[Unit]
+Description=kind multi-cluster routes
+After=network-online.target
+ConditionPathExists=/kind/multi-cluster-routes
+
+[Service]
+Type=oneshot
+ExecStart=/bin/sh -c 'while read -r cidr via; do ip route replace "$$cidr" via "$$via" || true; done < /kind/multi-cluster-routes'
+
+[Install]
+WantedBy=multi-user.target
+`
+
+type action struct{}
+
+// NewAction returns a new action for routing to other clusters
+func NewAction() actions.Action {
+	return &action{}
+}
+
+// Execute runs the action
+func (a *action) Execute(ctx *actions.ActionContext) error {
+	ctx.Status.Start("Routing to other clusters 🔗")
+	defer ctx.Status.End(false)
+
+	allNodes, err := ctx.Nodes()
+	if err != nil {
+		return err
+	}
+	self, err := getCluster(ctx.Config.Name, allNodes, podCIDRsTimeout)
+	if err != nil {
+		return err
+	}
+
+	// mark the nodes so later clusters route to this one, and restore the
+	// routes when they restart
+	fns := []func() error{}
+	for _, n := range self.nodes {
+		n := n // capture loop variable
+		fns = append(fns, func() error {
+			if err := nodeutils.WriteFile(n, markerPath, ctx.Config.Name); err != nil {
+				return err
+			}
+			if err := nodeutils.WriteFile(n, routesUnitPath, routesUnit); err != nil {
+				return err
+			}
+			return n.Command("systemctl", "enable", "kind-multi-cluster-routes.service").Run()
+		})
+	}
+	if err := errors.UntilErrorConcurrent(fns); err != nil {
+		return errors.Wrap(err, "failed to mark nodes")
+	}
+
+	if err := routeToSiblings(ctx.Logger, ctx.Provider, ctx.Config.Name, self); err != nil {
+		return err
+	}
+
+	// mark success
+	ctx.Status.End(true)
+	return nil
+}
+
+// Reroute reinstalls the routes between the multi-cluster cluster name and
+// the other multi-cluster clusters, E.G. after node addresses changed.
+// It does nothing if the cluster is not a multi-cluster cluster.
+func Reroute(logger log.Logger, p providers.Provider, name string) error {
+	allNodes, err := p.ListNodes(name)
+	if err != nil {
+		return err
+	}
+	if !isMultiCluster(allNodes) {
+		return nil
+	}
+	self, err := getCluster(name, allNodes, 0)
+	if err != nil {
+		return err
+	}
+	return routeToSiblings(logger, p, name, self)
+}
+
+// ValidateNetwork returns an error if any of the other multi-cluster
+// clusters is attached to a different container network than the nodes of
+// cfg would be, the routes between the clusters are via the node addresses
+func ValidateNetwork(logger log.Logger, p providers.Provider, cfg *config.Cluster) error {
+	networks, err := clusterNetworks(logger, p)
+	if err != nil {
+		return err
+	}
+	network := p.NetworkName(cfg)
+	errs := []error{}
+	for _, sibling := range sortedKeys(networks) {
+		if sibling != cfg.Name && networks[sibling] != network {
+			errs = append(errs, errors.Errorf("multi-cluster cluster %q is attached to network %q, not %q", sibling, networks[sibling], network))
+		}
+	}
+	if len(errs) > 0 {
+		return errors.Wrap(errors.NewAggregate(errs), "multi-cluster clusters must share a container network")
+	}
+	return nil
+}
+
+// Unroute removes the routes to the multi-cluster cluster name from the other
+// multi-cluster clusters, it must be called before deleting the nodes.
+// It does nothing if the cluster is not a multi-cluster cluster.
+func Unroute(logger log.Logger, p providers.Provider, name string) error {
+	networks, err := clusterNetworks(logger, p)
+	if err != nil {
+		return err
+	}
+	network, ok := networks[name]
+	if !ok {
+		return nil
+	}
+	allNodes, err := p.ListNodes(name)
+	if err != nil {
+		return err
+	}
+	kubeNodes, err := nodeutils.InternalNodes(allNodes)
+	if err != nil {
+		return err
+	}
+	// the routes to the cluster are via its node addresses
+	vias := []string{}
+	for _, n := range kubeNodes {
+		ipv4, ipv6, err := n.IP()
+		if err != nil {
+			return errors.Wrapf(err, "failed to get IP for node %q", n.String())
+		}
+		for _, ip := range []string{ipv4, ipv6} {
+			if ip != "" {
+				vias = append(vias, ip)
+			}
+		}
+	}
+	if len(vias) == 0 {
+		return nil
+	}
+	for _, sibling := range sortedKeys(networks) {
+		if sibling == name || networks[sibling] != network {
+			continue
+		}
+		siblingNodes, err := p.ListNodes(sibling)
+		if err != nil {
+			return err
+		}
+		targets, err := nodeutils.InternalNodes(siblingNodes)
+		if err != nil {
+			return err
+		}
+		logger.V(1).Infof("Removing routes to cluster %q from cluster %q", name, sibling)
+		if err := removeRoutes(targets, vias); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// routeToSiblings installs routes between self and the other multi-cluster
+// clusters on the same network, in both directions
+func routeToSiblings(logger log.Logger, p providers.Provider, name string, self *cluster) error {
+	networks, err := clusterNetworks(logger, p)
+	if err != nil {
+		return err
+	}
+	for _, sibling := range sortedKeys(networks) {
+		if sibling == name {
+			continue
+		}
+		if networks[sibling] != networks[name] {
+			logger.Warnf("Skipping routes to cluster %q on network %q", sibling, networks[sibling])
+			continue
+		}
+		siblingNodes, err := p.ListNodes(sibling)
+		if err != nil {
+			return err
+		}
+		if !isMultiCluster(siblingNodes) {
+			continue
+		}
+		other, err := getCluster(sibling, siblingNodes, 0)
+		if err != nil {
+			logger.Warnf("Skipping routes to cluster %q: %v", sibling, err)
+			continue
+		}
+		logger.V(1).Infof("Routing to pod subnets of cluster %q", sibling)
+		if err := installRoutes(self.nodes, other.routes); err != nil {
+			return err
+		}
+		if err := installRoutes(other.nodes, self.routes); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// clusterNetworks returns the container network of each multi-cluster
+// cluster by name, from the labels recorded on the node containers
+func clusterNetworks(logger log.Logger, p providers.Provider) (map[string]string, error) {
+	clusters, err := p.ListClusters()
+	if err != nil {
+		return nil, err
+	}
+	networks := map[string]string{}
+	for _, cluster := range clusters {
+		allNodes, err := p.ListNodes(cluster)
+		if err != nil {
+			return nil, err
+		}
+		if len(allNodes) == 0 {
+			// deleted in the meantime
+			continue
+		}
+		containers, err := p.GetNodeContainers(allNodes)
+		if err != nil {
+			logger.Warnf("failed to inspect the nodes of cluster %q, ignoring it: %v", cluster, err)
+			continue
+		}
+		for _, c := range containers {
+			if _, ok := c.Labels[common.PodSubnetLabelKey]; ok {
+				networks[cluster] = c.Labels[common.NetworkLabelKey]
+				break
+			}
+		}
+	}
+	return networks, nil
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// podCIDRsTimeout bounds waiting for the controller manager to allocate
+// pod CIDRs to the nodes of a new cluster
+const podCIDRsTimeout = time.Minute
+
+// route is a route to a node's pod CIDR
+type route struct {
+	cidr string
+	via  string
+}
+
+type cluster struct {
+	// nodes are the kubernetes nodes of the cluster
+	nodes []nodes.Node
+	// routes are the routes to the pod CIDRs of the cluster's nodes
+	routes []route
+}
+
+func isMultiCluster(allNodes []nodes.Node) bool {
+	controlPlanes, err := nodeutils.ControlPlaneNodes(allNodes)
+	if err != nil || len(controlPlanes) == 0 {
+		return false
+	}
+	return controlPlanes[0].Command("test", "-f", markerPath).Run() == nil
+}
+
+// getCluster returns the cluster's nodes and routes, waiting up to timeout
+// for pod CIDRs to be allocated to every node
+func getCluster(name string, allNodes []nodes.Node, timeout time.Duration) (*cluster, error) {
+	kubeNodes, err := nodeutils.InternalNodes(allNodes)
+	if err != nil {
+		return nil, err
+	}
+	controlPlanes, err := nodeutils.ControlPlaneNodes(allNodes)
+	if err != nil {
+		return nil, err
+	}
+	if len(controlPlanes) == 0 {
+		return nil, errors.Errorf("no control plane nodes found for cluster %q", name)
+	}
+
+	var podCIDRs map[string][]string
+	for start := time.Now(); ; time.Sleep(time.Second) {
+		podCIDRs, err = getPodCIDRs(controlPlanes[0])
+		if err == nil && len(podCIDRs) == len(kubeNodes) {
+			break
+		}
+		if time.Since(start) >= timeout {
+			if err == nil {
+				err = errors.Errorf("pod CIDRs are allocated to %d of %d nodes", len(podCIDRs), len(kubeNodes))
+			}
+			return nil, errors.Wrapf(err, "failed to get pod CIDRs of cluster %q", name)
+		}
+	}
+
+	c := &cluster{nodes: kubeNodes}
+	for _, n := range kubeNodes {
+		ipv4, ipv6, err := n.IP()
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get IP for node %q", n.String())
+		}
+		c.routes = append(c.routes, routesForNode(podCIDRs[n.String()], ipv4, ipv6)...)
+	}
+	return c, nil
+}
+
+// getPodCIDRs returns the pod CIDRs of each node that has any, by node name
+func getPodCIDRs(controlPlane nodes.Node) (map[string][]string, error) {
+	lines, err := exec.OutputLines(controlPlane.Command(
+		"kubectl", "--kubeconfig=/etc/kubernetes/admin.conf", "get", "nodes",
+		"-o", `jsonpath={range .items[*]}{.metadata.name}{" "}{.spec.podCIDRs[*]}{"\n"}{end}`,
+	))
+	if err != nil {
+		return nil, err
+	}
+	return parsePodCIDRs(lines), nil
+}
+
+func parsePodCIDRs(lines []string) map[string][]string {
+	podCIDRs := map[string][]string{}
+	for _, line := range lines {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		podCIDRs[fields[0]] = fields[1:]
+	}
+	return podCIDRs
+}
+
+// routesForNode returns routes to the pod CIDRs via the node's address of the
+// same IP family
+func routesForNode(podCIDRs []string, ipv4, ipv6 string) []route {
+	routes := []route{}
+	for _, cidr := range podCIDRs {
+		ip, _, err := net.ParseCIDR(cidr)
+		if err != nil {
+			continue
+		}
+		via := ipv6
+		if ip.To4() != nil {
+			via = ipv4
+		}
+		if via == "" {
+			continue
+		}
+		routes = append(routes, route{cidr: cidr, via: via})
+	}
+	return routes
+}
+
+// installRoutes adds or replaces the routes on all of the nodes, and records
+// them in routesPath
+func installRoutes(targets []nodes.Node, routes []route) error {
+	fns := []func() error{}
+	for _, n := range targets {
+		n := n // capture loop variable
+		fns = append(fns, func() error {
+			for _, r := range routes {
+				if err := n.Command("sh", "-c", recordRouteScript, "--", r.cidr, r.via).Run(); err != nil {
+					return errors.Wrapf(err, "failed to add route to %s via %s on node %q", r.cidr, r.via, n.String())
+				}
+			}
+			return nil
+		})
+	}
+	return errors.UntilErrorConcurrent(fns)
+}
+
+// recordRouteScript replaces the route to $1 via $2, and the recorded route
+// to $1 if any
+const recordRouteScript = `ip route replace "$1" via "$2" &&
+{ awk -v cidr="$1" '$1 != cidr' ` + routesPath + ` 2>/dev/null; echo "$1 $2"; } > ` + routesPath + `.tmp &&
+mv ` + routesPath + `.tmp ` + routesPath
+
+// removeRoutes deletes the routes via any of vias from all of the nodes, and
+// from their recorded routes
+func removeRoutes(targets []nodes.Node, vias []string) error {
+	fns := []func() error{}
+	for _, n := range targets {
+		n := n // capture loop variable
+		fns = append(fns, func() error {
+			if err := n.Command("sh", append([]string{"-c", removeRoutesScript, "--"}, vias...)...).Run(); err != nil {
+				return errors.Wrapf(err, "failed to remove routes on node %q", n.String())
+			}
+			return nil
+		})
+	}
+	return errors.UntilErrorConcurrent(fns)
+}
+
+// removeRoutesScript deletes the recorded routes via any of the arguments
+const removeRoutesScript = `test -f ` + routesPath + ` || exit 0
+for via in "$@"; do
+  awk -v via="$via" '$2 == via { print $1 }' ` + routesPath + ` | while read -r cidr; do ip route del "$cidr" via "$via" || true; done
+done
+awk -v vias="$*" 'BEGIN { split(vias, v, " "); for (i in v) skip[v[i]] = 1 } !($2 in skip)' ` + routesPath + ` > ` + routesPath + `.tmp &&
+mv ` + routesPath + `.tmp ` + routesPath
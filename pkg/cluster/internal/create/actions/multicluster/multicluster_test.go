@@ -0,0 +1,51 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package multicluster
+
+import (
+	"testing"
+
+	"sigs.k8s.io/kind/pkg/internal/assert"
+)
+
+func TestParsePodCIDRs(t *testing.T) {
+	t.Parallel()
+	result := parsePodCIDRs([]string{
+		"kind-control-plane 10.244.0.0/24 fd00:10:244::/64",
+		"kind-worker 10.244.1.0/24",
+		"kind-worker2 ",
+	})
+	assert.DeepEqual(t, map[string][]string{
+		"kind-control-plane": {"10.244.0.0/24", "fd00:10:244::/64"},
+		"kind-worker":        {"10.244.1.0/24"},
+	}, result)
+}
+
+func TestRoutesForNode(t *testing.T) {
+	t.Parallel()
+	result := routesForNode(
+		[]string{"10.244.0.0/24", "fd00:10:244::/64", "bogus"},
+		"172.18.0.2", "fc00:f853:ccd:e793::2",
+	)
+	assert.DeepEqual(t, []route{
+		{cidr: "10.244.0.0/24", via: "172.18.0.2"},
+		{cidr: "fd00:10:244::/64", via: "fc00:f853:ccd:e793::2"},
+	}, result)
+
+	// no route without an address of the same family
+	assert.DeepEqual(t, []route{}, routesForNode([]string{"fd00:10:244::/64"}, "172.18.0.2", ""))
+}
@@ -0,0 +1,239 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package multicluster
+
+import (
+	"bytes"
+	"net"
+	"regexp"
+	"strings"
+
+	"sigs.k8s.io/kind/pkg/errors"
+	"sigs.k8s.io/kind/pkg/log"
+
+	"sigs.k8s.io/kind/pkg/cluster/internal/providers"
+	"sigs.k8s.io/kind/pkg/cluster/internal/providers/common"
+	"sigs.k8s.io/kind/pkg/cluster/nodeutils"
+	"sigs.k8s.io/kind/pkg/internal/apis/config"
+)
+
+// AllocateSubnets sets any unset pod and service subnets of cfg to subnets
+// that do not overlap with those of the other clusters of the provider.
+// Callers must hold clusterlock.AcquireSubnetAllocation until the nodes of
+// the cluster have been created, which records the subnets on them.
+func AllocateSubnets(logger log.Logger, p providers.Provider, cfg *config.Cluster) error {
+	if cfg.Networking.PodSubnet != "" && cfg.Networking.ServiceSubnet != "" {
+		return nil
+	}
+	used, err := usedSubnets(logger, p, cfg.Name)
+	if err != nil {
+		return err
+	}
+	if cfg.Networking.PodSubnet == "" {
+		subnet, err := allocate(cfg.Networking.IPFamily, used, podSubnetPools)
+		if err != nil {
+			return errors.Wrap(err, "failed to allocate pod subnet")
+		}
+		cfg.Networking.PodSubnet = subnet
+	}
+	if cfg.Networking.ServiceSubnet == "" {
+		subnet, err := allocate(cfg.Networking.IPFamily, used, serviceSubnetPools)
+		if err != nil {
+			return errors.Wrap(err, "failed to allocate service subnet")
+		}
+		cfg.Networking.ServiceSubnet = subnet
+	}
+	logger.V(0).Infof("Allocated pod subnet %q and service subnet %q", cfg.Networking.PodSubnet, cfg.Networking.ServiceSubnet)
+	return nil
+}
+
+// pool generates the i-th candidate subnet of a pool, or nil if exhausted
+type pool func(i int) *net.IPNet
+
+// subnetPools are the candidate pools for a subnet by IP family
+type subnetPools struct {
+	ipv4 pool
+	ipv6 pool
+}
+
+// the first candidate of each pool matches the single cluster defaults
+var podSubnetPools = subnetPools{
+	// 10.244.0.0/16 ... 10.255.0.0/16
+	ipv4: func(i int) *net.IPNet {
+		if i >= 12 {
+			return nil
+		}
+		return &net.IPNet{IP: net.IPv4(10, byte(244+i), 0, 0).To4(), Mask: net.CIDRMask(16, 32)}
+	},
+	// fd00:10:244::/56 ... fd00:10:244:ff00::/56
+	ipv6: func(i int) *net.IPNet {
+		if i >= 256 {
+			return nil
+		}
+		ip := net.ParseIP("fd00:10:244::").To16()
+		ip[6] = byte(i)
+		return &net.IPNet{IP: ip, Mask: net.CIDRMask(56, 128)}
+	},
+}
+
+var serviceSubnetPools = subnetPools{
+	// 10.96.0.0/16 ... 10.111.0.0/16
+	ipv4: func(i int) *net.IPNet {
+		if i >= 16 {
+			return nil
+		}
+		return &net.IPNet{IP: net.IPv4(10, byte(96+i), 0, 0).To4(), Mask: net.CIDRMask(16, 32)}
+	},
+	// fd00:10:96::/112 ... fd00:10:96::ff:0/112
+	ipv6: func(i int) *net.IPNet {
+		if i >= 256 {
+			return nil
+		}
+		ip := net.ParseIP("fd00:10:96::").To16()
+		ip[13] = byte(i)
+		return &net.IPNet{IP: ip, Mask: net.CIDRMask(112, 128)}
+	},
+}
+
+// allocate returns the first non-overlapping subnet of each required IP family
+// from pools, in the order used by the default subnets
+func allocate(family config.ClusterIPFamily, used []*net.IPNet, pools subnetPools) (string, error) {
+	var families []pool
+	switch family {
+	case config.IPv4Family:
+		families = []pool{pools.ipv4}
+	case config.IPv6Family:
+		families = []pool{pools.ipv6}
+	case config.DualStackFamily:
+		families = []pool{pools.ipv4, pools.ipv6}
+	default:
+		return "", errors.Errorf("unknown cluster IP family: %v", family)
+	}
+	subnets := []string{}
+	for _, next := range families {
+		subnet := firstFree(next, used)
+		if subnet == nil {
+			return "", errors.New("all subnets in the pool are in use by other clusters")
+		}
+		subnets = append(subnets, subnet.String())
+	}
+	return strings.Join(subnets, ","), nil
+}
+
+func firstFree(next pool, used []*net.IPNet) *net.IPNet {
+	for i := 0; ; i++ {
+		candidate := next(i)
+		if candidate == nil {
+			return nil
+		}
+		if !overlapsAny(candidate, used) {
+			return candidate
+		}
+	}
+}
+
+func overlapsAny(subnet *net.IPNet, others []*net.IPNet) bool {
+	for _, other := range others {
+		if subnet.Contains(other.IP) || other.Contains(subnet.IP) {
+			return true
+		}
+	}
+	return false
+}
+
+// usedSubnets returns the pod and service subnets of the other clusters
+func usedSubnets(logger log.Logger, p providers.Provider, self string) ([]*net.IPNet, error) {
+	clusters, err := p.ListClusters()
+	if err != nil {
+		return nil, err
+	}
+	used := []*net.IPNet{}
+	for _, cluster := range clusters {
+		if cluster == self {
+			continue
+		}
+		allNodes, err := p.ListNodes(cluster)
+		if err != nil {
+			return nil, err
+		}
+		if len(allNodes) == 0 {
+			// deleted in the meantime
+			continue
+		}
+		// multi-cluster clusters record their subnets when creating the
+		// nodes, before the kubeadm config exists
+		containers, err := p.GetNodeContainers(allNodes)
+		if err != nil {
+			logger.Warnf("failed to inspect the nodes of cluster %q, ignoring its subnets: %v", cluster, err)
+			continue
+		}
+		if subnets := subnetsFromLabels(containers); len(subnets) > 0 {
+			used = append(used, subnets...)
+			continue
+		}
+		// otherwise use the kubeadm config, E.G. for clusters without
+		// multi-cluster mode
+		controlPlanes, err := nodeutils.ControlPlaneNodes(allNodes)
+		if err != nil || len(controlPlanes) == 0 {
+			logger.Warnf("failed to find a control plane node for cluster %q, ignoring its subnets", cluster)
+			continue
+		}
+		var buff bytes.Buffer
+		if err := controlPlanes[0].Command("cat", "/kind/kubeadm.conf").SetStdout(&buff).Run(); err != nil {
+			logger.Warnf("failed to read the config of cluster %q, ignoring its subnets: %v", cluster, err)
+			continue
+		}
+		used = append(used, parseSubnets(buff.String())...)
+	}
+	return used, nil
+}
+
+// subnetsFromLabels returns the pod and service subnets recorded in the
+// labels of the first node container that has them
+func subnetsFromLabels(containers []providers.NodeContainer) []*net.IPNet {
+	for _, c := range containers {
+		pod, hasPod := c.Labels[common.PodSubnetLabelKey]
+		service, hasService := c.Labels[common.ServiceSubnetLabelKey]
+		if hasPod || hasService {
+			return append(parseSubnetList(pod), parseSubnetList(service)...)
+		}
+	}
+	return nil
+}
+
+var subnetRE = regexp.MustCompile(`(?m)^\s*(?:podSubnet|serviceSubnet):\s*"?([^"\s]+)"?\s*$`)
+
+// parseSubnets returns the pod and service subnets in a kubeadm config
+func parseSubnets(kubeadmConfig string) []*net.IPNet {
+	subnets := []*net.IPNet{}
+	for _, match := range subnetRE.FindAllStringSubmatch(kubeadmConfig, -1) {
+		subnets = append(subnets, parseSubnetList(match[1])...)
+	}
+	return subnets
+}
+
+// parseSubnetList parses a comma separated list of subnets, skipping any
+// invalid entries
+func parseSubnetList(list string) []*net.IPNet {
+	subnets := []*net.IPNet{}
+	for _, s := range strings.Split(list, ",") {
+		if _, subnet, err := net.ParseCIDR(strings.TrimSpace(s)); err == nil {
+			subnets = append(subnets, subnet)
+		}
+	}
+	return subnets
+}
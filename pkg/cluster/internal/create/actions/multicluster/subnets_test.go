@@ -0,0 +1,130 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package multicluster
+
+import (
+	"net"
+	"testing"
+
+	"sigs.k8s.io/kind/pkg/cluster/internal/providers"
+	"sigs.k8s.io/kind/pkg/internal/apis/config"
+	"sigs.k8s.io/kind/pkg/internal/assert"
+)
+
+func TestAllocate(t *testing.T) {
+	t.Parallel()
+	mustParse := func(cidrs ...string) []*net.IPNet {
+		subnets := []*net.IPNet{}
+		for _, cidr := range cidrs {
+			_, subnet, err := net.ParseCIDR(cidr)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			subnets = append(subnets, subnet)
+		}
+		return subnets
+	}
+	cases := []struct {
+		name        string
+		family      config.ClusterIPFamily
+		used        []*net.IPNet
+		pools       subnetPools
+		expected    string
+		expectError bool
+	}{
+		{
+			name:     "first pod subnet is the default",
+			family:   config.IPv4Family,
+			pools:    podSubnetPools,
+			expected: "10.244.0.0/16",
+		},
+		{
+			name:     "skips used pod subnets",
+			family:   config.IPv4Family,
+			used:     mustParse("10.244.0.0/16", "10.96.0.0/16", "10.245.1.0/24"),
+			pools:    podSubnetPools,
+			expected: "10.246.0.0/16",
+		},
+		{
+			name:     "skips used service subnets",
+			family:   config.DualStackFamily,
+			used:     mustParse("10.244.0.0/16", "10.96.0.0/16", "fd00:10:96::/112"),
+			pools:    serviceSubnetPools,
+			expected: "10.97.0.0/16,fd00:10:96::1:0/112",
+		},
+		{
+			name:     "ipv6 pod subnets",
+			family:   config.IPv6Family,
+			used:     mustParse("fd00:10:244::/56"),
+			pools:    podSubnetPools,
+			expected: "fd00:10:244:100::/56",
+		},
+		{
+			name:        "exhausted",
+			family:      config.IPv4Family,
+			used:        mustParse("10.0.0.0/8"),
+			pools:       podSubnetPools,
+			expectError: true,
+		},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			result, err := allocate(tc.family, tc.used, tc.pools)
+			assert.ExpectError(t, tc.expectError, err)
+			assert.StringEqual(t, tc.expected, result)
+		})
+	}
+}
+
+func TestParseSubnets(t *testing.T) {
+	t.Parallel()
+	kubeadmConfig := `apiVersion: kubeadm.k8s.io/v1beta3
+kind: ClusterConfiguration
+networking:
+  podSubnet: "10.244.0.0/16,fd00:10:244::/56"
+  serviceSubnet: "10.96.0.0/16"
+`
+	subnets := parseSubnets(kubeadmConfig)
+	result := []string{}
+	for _, subnet := range subnets {
+		result = append(result, subnet.String())
+	}
+	assert.DeepEqual(t, []string{"10.244.0.0/16", "fd00:10:244::/56", "10.96.0.0/16"}, result)
+}
+
+func TestSubnetsFromLabels(t *testing.T) {
+	t.Parallel()
+	containers := []providers.NodeContainer{
+		// port forwarders do not record the subnets
+		{Labels: map[string]string{"io.x-k8s.kind.role": "port-forwarder"}},
+		{Labels: map[string]string{
+			"io.x-k8s.kind.pod-subnet":     "10.245.0.0/16,fd00:10:244:100::/56",
+			"io.x-k8s.kind.service-subnet": "10.97.0.0/16",
+		}},
+	}
+	result := []string{}
+	for _, subnet := range subnetsFromLabels(containers) {
+		result = append(result, subnet.String())
+	}
+	assert.DeepEqual(t, []string{"10.245.0.0/16", "fd00:10:244:100::/56", "10.97.0.0/16"}, result)
+
+	if subnets := subnetsFromLabels(containers[:1]); subnets != nil {
+		t.Errorf("expected no subnets without labels, got %v", subnets)
+	}
+}
@@ -0,0 +1,287 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package waitforready
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"sigs.k8s.io/kind/pkg/cluster/nodes"
+	"sigs.k8s.io/kind/pkg/errors"
+	"sigs.k8s.io/kind/pkg/exec"
+)
+
+// Gate is a condition the cluster must meet to be considered ready
+type Gate string
+
+const (
+	// GateControlPlane waits for the control plane nodes to be Ready,
+	// this is the default
+	GateControlPlane Gate = "control-plane"
+	// GateNodes waits for every node, including workers, to be Ready
+	GateNodes Gate = "nodes"
+	// GateSystemPods waits for every kube-system pod to be Ready
+	GateSystemPods Gate = "system-pods"
+	// GateCNI waits for the default CNI to be running on every node, or with
+	// a custom CNI for every node to be Ready, which requires pod networking
+	GateCNI Gate = "cni"
+	// GateStorage waits for a default StorageClass and its provisioner
+	GateStorage Gate = "storage"
+	// GateDNS waits for the cluster DNS deployment to be Ready
+	GateDNS Gate = "dns"
+)
+
+// AllGates lists the valid gates
+var AllGates = []Gate{GateControlPlane, GateNodes, GateSystemPods, GateCNI, GateStorage, GateDNS}
+
+// ParseGates validates gates, returning them in order without duplicates.
+// No gates selects the default GateControlPlane
+func ParseGates(gates []string) ([]Gate, error) {
+	if len(gates) == 0 {
+		return []Gate{GateControlPlane}, nil
+	}
+	out := []Gate{}
+	seen := map[Gate]bool{}
+	for _, g := range gates {
+		gate := Gate(strings.TrimSpace(g))
+		if !isGate(gate) {
+			valid := make([]string, len(AllGates))
+			for i := range AllGates {
+				valid[i] = string(AllGates[i])
+			}
+			return nil, errors.Errorf("unknown wait gate %q, must be one of: %s", g, strings.Join(valid, ", "))
+		}
+		if !seen[gate] {
+			seen[gate] = true
+			out = append(out, gate)
+		}
+	}
+	return out, nil
+}
+
+func isGate(gate Gate) bool {
+	for _, g := range AllGates {
+		if g == gate {
+			return true
+		}
+	}
+	return false
+}
+
+// check reports whether a gate is met, and if not what it is waiting on
+type check func() (ready bool, waitingOn string)
+
+// gateInfo is what the gate checks need to know about the cluster
+type gateInfo struct {
+	// node runs kubectl
+	node nodes.Node
+	// controlPlaneSelector selects the control plane nodes
+	controlPlaneSelector string
+	// controlPlanes and nodes are the expected node counts
+	controlPlanes int
+	nodes         int
+	// defaultCNI is true if kind installed the CNI
+	defaultCNI bool
+}
+
+func (i *gateInfo) check(gate Gate) check {
+	switch gate {
+	case GateControlPlane:
+		return func() (bool, string) {
+			lines, err := kubectl(i.node, "get", "nodes", "--selector="+i.controlPlaneSelector, "-o="+nodesJSONPath)
+			return nodesReady(lines, err, i.controlPlanes)
+		}
+	case GateNodes:
+		return i.allNodesReady
+	case GateSystemPods:
+		return func() (bool, string) {
+			return podsReady(kubectl(i.node, "get", "pods", "--namespace=kube-system", "-o="+podsJSONPath))
+		}
+	case GateCNI:
+		if !i.defaultCNI {
+			return i.allNodesReady
+		}
+		return func() (bool, string) {
+			lines, err := kubectl(i.node, "get", "daemonsets", "--namespace=kube-system", "--selector=app=kindnet", "-o="+daemonSetsJSONPath)
+			return daemonSetsReady(lines, err, i.nodes)
+		}
+	case GateStorage:
+		return func() (bool, string) {
+			if ready, waitingOn := defaultStorageClass(kubectl(i.node, "get", "storageclasses", "-o="+storageClassesJSONPath)); !ready {
+				return false, waitingOn
+			}
+			// legacy images have no provisioner deployment
+			lines, err := kubectl(i.node, "get", "deployments", "--namespace=local-path-storage", "-o="+deploymentsJSONPath)
+			if err == nil && len(nonEmpty(lines)) == 0 {
+				return true, ""
+			}
+			return deploymentsReady(lines, err)
+		}
+	case GateDNS:
+		return func() (bool, string) {
+			return deploymentsReady(kubectl(i.node, "get", "deployments", "--namespace=kube-system", "--selector=k8s-app=kube-dns", "-o="+deploymentsJSONPath))
+		}
+	}
+	return func() (bool, string) { return false, "unknown gate" }
+}
+
+func (i *gateInfo) allNodesReady() (bool, string) {
+	lines, err := kubectl(i.node, "get", "nodes", "-o="+nodesJSONPath)
+	return nodesReady(lines, err, i.nodes)
+}
+
+// kubectl runs kubectl with the admin credentials on node
+func kubectl(node nodes.Node, args ...string) ([]string, error) {
+	return exec.OutputLines(node.Command(
+		"kubectl", append([]string{"--kubeconfig=/etc/kubernetes/admin.conf"}, args...)...,
+	))
+}
+
+// the jsonpath templates print one object per line, space separated
+const (
+	nodesJSONPath          = `jsonpath={range .items[*]}{.metadata.name} {.status.conditions[?(@.type=="Ready")].status}{"\n"}{end}`
+	podsJSONPath           = `jsonpath={range .items[*]}{.metadata.name} {.status.phase} {.status.conditions[?(@.type=="Ready")].status}{"\n"}{end}`
+	daemonSetsJSONPath     = `jsonpath={range .items[*]}{.metadata.name} {.status.desiredNumberScheduled} {.status.numberReady}{"\n"}{end}`
+	deploymentsJSONPath    = `jsonpath={range .items[*]}{.metadata.name} {.spec.replicas} {.status.readyReplicas}{"\n"}{end}`
+	storageClassesJSONPath = `jsonpath={range .items[*]}{.metadata.name} {.metadata.annotations.storageclass\.kubernetes\.io/is-default-class}{"\n"}{end}`
+)
+
+// nodesReady checks the output of nodesJSONPath for expected Ready nodes
+func nodesReady(lines []string, err error, expected int) (bool, string) {
+	if err != nil {
+		return false, "nodes to be listed"
+	}
+	lines = nonEmpty(lines)
+	notReady := []string{}
+	for _, line := range lines {
+		fields := strings.Fields(line)
+		if len(fields) < 2 || fields[1] != "True" {
+			notReady = append(notReady, fields[0])
+		}
+	}
+	if len(notReady) > 0 {
+		return false, "nodes to be Ready: " + strings.Join(notReady, ", ")
+	}
+	if len(lines) < expected {
+		return false, fmt.Sprintf("nodes to register: %d/%d", len(lines), expected)
+	}
+	return true, ""
+}
+
+// podsReady checks the output of podsJSONPath for all pods to be Ready,
+// ignoring completed pods
+func podsReady(lines []string, err error) (bool, string) {
+	if err != nil {
+		return false, "pods to be listed"
+	}
+	lines = nonEmpty(lines)
+	if len(lines) == 0 {
+		return false, "pods to be created"
+	}
+	notReady := []string{}
+	for _, line := range lines {
+		fields := strings.Fields(line)
+		if len(fields) > 1 && fields[1] == "Succeeded" {
+			continue
+		}
+		if len(fields) < 3 || fields[2] != "True" {
+			notReady = append(notReady, fields[0])
+		}
+	}
+	if len(notReady) > 0 {
+		return false, "pods to be Ready: " + strings.Join(notReady, ", ")
+	}
+	return true, ""
+}
+
+// daemonSetsReady checks the output of daemonSetsJSONPath for daemonsets
+// running Ready on expected nodes
+func daemonSetsReady(lines []string, err error, expected int) (bool, string) {
+	if err != nil {
+		return false, "daemonsets to be listed"
+	}
+	lines = nonEmpty(lines)
+	if len(lines) == 0 {
+		return false, "daemonsets to be created"
+	}
+	for _, line := range lines {
+		name, desired, ready := parseCounts(line)
+		if desired < expected || ready < desired {
+			return false, fmt.Sprintf("%s to be Ready: %d/%d", name, ready, expected)
+		}
+	}
+	return true, ""
+}
+
+// deploymentsReady checks the output of deploymentsJSONPath for all replicas
+// to be Ready
+func deploymentsReady(lines []string, err error) (bool, string) {
+	if err != nil {
+		return false, "deployments to be listed"
+	}
+	lines = nonEmpty(lines)
+	if len(lines) == 0 {
+		return false, "deployments to be created"
+	}
+	for _, line := range lines {
+		name, replicas, ready := parseCounts(line)
+		if replicas == 0 || ready < replicas {
+			return false, fmt.Sprintf("%s to be Ready: %d/%d", name, ready, replicas)
+		}
+	}
+	return true, ""
+}
+
+// defaultStorageClass checks the output of storageClassesJSONPath for a
+// default StorageClass
+func defaultStorageClass(lines []string, err error) (bool, string) {
+	if err != nil {
+		return false, "storageclasses to be listed"
+	}
+	for _, line := range nonEmpty(lines) {
+		fields := strings.Fields(line)
+		if len(fields) == 2 && fields[1] == "true" {
+			return true, ""
+		}
+	}
+	return false, "a default storageclass"
+}
+
+// parseCounts parses "name a b" where a missing or invalid count is 0,
+// as kubectl prints nothing for unset status fields
+func parseCounts(line string) (name string, a, b int) {
+	fields := strings.Fields(line)
+	name = fields[0]
+	if len(fields) > 1 {
+		a, _ = strconv.Atoi(fields[1])
+	}
+	if len(fields) > 2 {
+		b, _ = strconv.Atoi(fields[2])
+	}
+	return name, a, b
+}
+
+func nonEmpty(lines []string) []string {
+	out := []string{}
+	for _, line := range lines {
+		if strings.TrimSpace(line) != "" {
+			out = append(out, line)
+		}
+	}
+	return out
+}
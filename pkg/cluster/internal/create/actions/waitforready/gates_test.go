@@ -0,0 +1,172 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package waitforready
+
+import (
+	"errors"
+	"testing"
+
+	"sigs.k8s.io/kind/pkg/internal/assert"
+)
+
+func TestParseGates(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		Name        string
+		Gates       []string
+		Expected    []Gate
+		ExpectError bool
+	}{
+		{
+			Name:     "default",
+			Expected: []Gate{GateControlPlane},
+		},
+		{
+			Name:     "ordered without duplicates",
+			Gates:    []string{"nodes", " dns", "nodes", "cni"},
+			Expected: []Gate{GateNodes, GateDNS, GateCNI},
+		},
+		{
+			Name:        "unknown",
+			Gates:       []string{"nodes", "pods"},
+			ExpectError: true,
+		},
+	}
+	for _, tc := range cases {
+		tc := tc // capture range variable
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+			gates, err := ParseGates(tc.Gates)
+			assert.ExpectError(t, tc.ExpectError, err)
+			if !tc.ExpectError {
+				assert.DeepEqual(t, tc.Expected, gates)
+			}
+		})
+	}
+}
+
+func TestChecks(t *testing.T) {
+	t.Parallel()
+	listErr := errors.New("connection refused")
+	cases := []struct {
+		Name              string
+		Check             func() (bool, string)
+		ExpectedReady     bool
+		ExpectedWaitingOn string
+	}{
+		{
+			Name: "nodes ready",
+			Check: func() (bool, string) {
+				return nodesReady([]string{"kind-control-plane True", "kind-worker True", ""}, nil, 2)
+			},
+			ExpectedReady: true,
+		},
+		{
+			Name: "nodes not ready",
+			Check: func() (bool, string) {
+				return nodesReady([]string{"kind-control-plane True", "kind-worker False", "kind-worker2"}, nil, 3)
+			},
+			ExpectedWaitingOn: "nodes to be Ready: kind-worker, kind-worker2",
+		},
+		{
+			Name: "nodes not registered",
+			Check: func() (bool, string) {
+				return nodesReady([]string{"kind-control-plane True"}, nil, 2)
+			},
+			ExpectedWaitingOn: "nodes to register: 1/2",
+		},
+		{
+			Name: "nodes error",
+			Check: func() (bool, string) {
+				return nodesReady(nil, listErr, 1)
+			},
+			ExpectedWaitingOn: "nodes to be listed",
+		},
+		{
+			Name: "pods ready, ignoring completed",
+			Check: func() (bool, string) {
+				return podsReady([]string{"coredns-1 Running True", "job-1 Succeeded False"}, nil)
+			},
+			ExpectedReady: true,
+		},
+		{
+			Name: "pods pending",
+			Check: func() (bool, string) {
+				return podsReady([]string{"coredns-1 Running True", "coredns-2 Pending"}, nil)
+			},
+			ExpectedWaitingOn: "pods to be Ready: coredns-2",
+		},
+		{
+			Name: "no pods",
+			Check: func() (bool, string) {
+				return podsReady(nil, nil)
+			},
+			ExpectedWaitingOn: "pods to be created",
+		},
+		{
+			Name: "daemonset ready",
+			Check: func() (bool, string) {
+				return daemonSetsReady([]string{"kindnet 3 3"}, nil, 3)
+			},
+			ExpectedReady: true,
+		},
+		{
+			Name: "daemonset not scheduled everywhere",
+			Check: func() (bool, string) {
+				return daemonSetsReady([]string{"kindnet 2 2"}, nil, 3)
+			},
+			ExpectedWaitingOn: "kindnet to be Ready: 2/3",
+		},
+		{
+			Name: "deployment without ready replicas",
+			Check: func() (bool, string) {
+				return deploymentsReady([]string{"coredns 2 "}, nil)
+			},
+			ExpectedWaitingOn: "coredns to be Ready: 0/2",
+		},
+		{
+			Name: "deployment ready",
+			Check: func() (bool, string) {
+				return deploymentsReady([]string{"coredns 2 2"}, nil)
+			},
+			ExpectedReady: true,
+		},
+		{
+			Name: "default storageclass",
+			Check: func() (bool, string) {
+				return defaultStorageClass([]string{"other", "standard true"}, nil)
+			},
+			ExpectedReady: true,
+		},
+		{
+			Name: "no default storageclass",
+			Check: func() (bool, string) {
+				return defaultStorageClass([]string{"other false"}, nil)
+			},
+			ExpectedWaitingOn: "a default storageclass",
+		},
+	}
+	for _, tc := range cases {
+		tc := tc // capture range variable
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+			ready, waitingOn := tc.Check()
+			assert.BoolEqual(t, tc.ExpectedReady, ready)
+			assert.StringEqual(t, tc.ExpectedWaitingOn, waitingOn)
+		})
+	}
+}
@@ -23,22 +23,29 @@ import (
 	"time"
 
 	"sigs.k8s.io/kind/pkg/cluster/internal/create/actions"
-	"sigs.k8s.io/kind/pkg/cluster/nodes"
 	"sigs.k8s.io/kind/pkg/cluster/nodeutils"
 	"sigs.k8s.io/kind/pkg/errors"
-	"sigs.k8s.io/kind/pkg/exec"
 	"sigs.k8s.io/kind/pkg/internal/version"
 )
 
 // Action implements an action for waiting for the cluster to be ready
 type Action struct {
 	waitTime time.Duration
+	gates    []Gate
+	// failOnTimeout returns an error on timeout, rather than warning
+	failOnTimeout bool
 }
 
-// NewAction returns a new action for waiting for the cluster to be ready
-func NewAction(waitTime time.Duration) actions.Action {
+// NewAction returns a new action for waiting for the cluster to be ready,
+// waiting up to waitTime in total for each of gates in order, see ParseGates
+func NewAction(waitTime time.Duration, gates []Gate, failOnTimeout bool) actions.Action {
+	if len(gates) == 0 {
+		gates = []Gate{GateControlPlane}
+	}
 	return &Action{
-		waitTime: waitTime,
+		waitTime:      waitTime,
+		gates:         gates,
+		failOnTimeout: failOnTimeout,
 	}
 }
 
@@ -48,10 +55,15 @@ func (a *Action) Execute(ctx *actions.ActionContext) error {
 	if a.waitTime == time.Duration(0) {
 		return nil
 	}
+	names := make([]string, len(a.gates))
+	for i := range a.gates {
+		names[i] = string(a.gates[i])
+	}
 	ctx.Status.Start(
 		fmt.Sprintf(
-			"Waiting ≤ %s for control-plane = Ready ⏳",
+			"Waiting ≤ %s for %s = Ready ⏳",
 			formatDuration(a.waitTime),
+			strings.Join(names, ", "),
 		),
 	)
 
@@ -64,9 +76,13 @@ func (a *Action) Execute(ctx *actions.ActionContext) error {
 	if err != nil {
 		return err
 	}
+	internalNodes, err := nodeutils.InternalNodes(allNodes)
+	if err != nil {
+		return err
+	}
 	node := controlPlanes[0] // kind expects at least one always
 
-	// Wait for the nodes to reach Ready status.
+	// Wait for the gates to be met.
 	startTime := time.Now()
 
 	// TODO: Remove the below handling once kubeadm 1.23 is no longer supported.
@@ -84,11 +100,25 @@ func (a *Action) Execute(ctx *actions.ActionContext) error {
 		selectorLabel = "node-role.kubernetes.io/master"
 	}
 
-	isReady := waitForReady(node, startTime.Add(a.waitTime), selectorLabel)
-	if !isReady {
-		ctx.Status.End(false)
-		ctx.Logger.V(0).Info(" • WARNING: Timed out waiting for Ready ⚠️")
-		return nil
+	info := &gateInfo{
+		node:                 node,
+		controlPlaneSelector: selectorLabel,
+		controlPlanes:        len(controlPlanes),
+		nodes:                len(internalNodes),
+		defaultCNI:           !ctx.Config.Networking.DisableDefaultCNI,
+	}
+	until := startTime.Add(a.waitTime)
+	for _, gate := range a.gates {
+		isReady, waitingOn := waitFor(until, info.check(gate))
+		if !isReady {
+			ctx.Status.End(false)
+			if a.failOnTimeout {
+				return errors.Errorf("timed out waiting for %s gate, waiting on %s", gate, waitingOn)
+			}
+			ctx.Logger.V(0).Infof(" • WARNING: Timed out waiting for %s gate ⚠️", gate)
+			ctx.Logger.V(0).Infof(" • Still waiting on %s", waitingOn)
+			return nil
+		}
 	}
 
 	// mark success
@@ -97,49 +127,16 @@ func (a *Action) Execute(ctx *actions.ActionContext) error {
 	return nil
 }
 
-// WaitForReady uses kubectl inside the "node" container to check if the
-// control plane nodes are "Ready".
-func waitForReady(node nodes.Node, until time.Time, selectorLabel string) bool {
-	return tryUntil(until, func() bool {
-		cmd := node.Command(
-			"kubectl",
-			"--kubeconfig=/etc/kubernetes/admin.conf",
-			"get",
-			"nodes",
-			"--selector="+selectorLabel,
-			// When the node reaches status ready, the status field will be set
-			// to true.
-			"-o=jsonpath='{.items..status.conditions[-1:].status}'",
-		)
-		lines, err := exec.OutputLines(cmd)
-		if err != nil {
-			return false
-		}
-
-		// 'lines' will return the status of all nodes labeled as master. For
-		// example, if we have three control plane nodes, and all are ready,
-		// then the status will have the following format: `True True True'.
-		status := strings.Fields(lines[0])
-		for _, s := range status {
-			// Check node status. If node is ready then this will be 'True',
-			// 'False' or 'Unknown' otherwise.
-			if !strings.Contains(s, "True") {
-				return false
-			}
-		}
-		return true
-	})
-}
-
-// helper that calls `try()“ in a loop until the deadline `until`
-// has passed or `try()`returns true, returns whether try ever returned true
-func tryUntil(until time.Time, try func() bool) bool {
-	for until.After(time.Now()) {
-		if try() {
-			return true
+// waitFor calls check until it is ready or the deadline until has passed,
+// returning what it was last waiting on if it never became ready.
+// check is always called at least once.
+func waitFor(until time.Time, check check) (bool, string) {
+	for {
+		ready, waitingOn := check()
+		if ready || !until.After(time.Now()) {
+			return ready, waitingOn
 		}
 	}
-	return false
 }
 
 func formatDuration(duration time.Duration) string {
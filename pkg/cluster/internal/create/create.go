@@ -24,7 +24,10 @@ import (
 
 	"al.essio.dev/pkg/shellescape"
 
+	"sigs.k8s.io/kind/pkg/apis/config/defaults"
+	"sigs.k8s.io/kind/pkg/cluster/internal/clusterlock"
 	"sigs.k8s.io/kind/pkg/cluster/internal/delete"
+	"sigs.k8s.io/kind/pkg/cluster/internal/doctor"
 	"sigs.k8s.io/kind/pkg/cluster/internal/providers"
 	"sigs.k8s.io/kind/pkg/errors"
 	"sigs.k8s.io/kind/pkg/internal/apis/config"
@@ -39,6 +42,7 @@ import (
 	"sigs.k8s.io/kind/pkg/cluster/internal/create/actions/kubeadminit"
 	"sigs.k8s.io/kind/pkg/cluster/internal/create/actions/kubeadmjoin"
 	"sigs.k8s.io/kind/pkg/cluster/internal/create/actions/loadbalancer"
+	"sigs.k8s.io/kind/pkg/cluster/internal/create/actions/multicluster"
 	"sigs.k8s.io/kind/pkg/cluster/internal/create/actions/waitforready"
 	"sigs.k8s.io/kind/pkg/cluster/internal/kubeconfig"
 )
@@ -47,6 +51,9 @@ const (
 	// Typical host name max limit is 64 characters (https://linux.die.net/man/2/sethostname)
 	// We append -control-plane (14 characters) to the cluster name on the control plane container
 	clusterNameMax = 50
+	// subnetLockTimeout bounds waiting for other multi-cluster clusters to
+	// create their nodes, which records their allocated subnets
+	subnetLockTimeout = 5 * time.Minute
 )
 
 // ClusterOptions holds cluster creation options
@@ -54,10 +61,27 @@ type ClusterOptions struct {
 	Config       *config.Cluster
 	NameOverride string // overrides config.Name
 	// NodeImage overrides the nodes' images in Config if non-zero
-	NodeImage      string
+	NodeImage string
+	// DefaultNodeImage overrides the images of nodes in Config using the
+	// default node image if non-zero
+	DefaultNodeImage string
+	// Labels and Annotations are merged into those in Config, overriding
+	// any keys that are set in both
+	Labels         map[string]string
+	Annotations    map[string]string
 	Retain         bool
 	WaitForReady   time.Duration
 	KubeconfigPath string
+	// KubeconfigStrategy is the kubeconfig.Strategy, used unless
+	// KubeconfigPath is set
+	KubeconfigStrategy kubeconfig.Strategy
+	// WaitFor selects what WaitForReady waits for, see waitforready.ParseGates
+	WaitFor []string
+	// FailOnWaitTimeout fails creation if WaitForReady times out
+	FailOnWaitTimeout bool
+	// LockTimeout is how long to wait for another process creating or
+	// deleting a cluster with the same name
+	LockTimeout time.Duration
 	// see https://github.com/kubernetes-sigs/kind/issues/324
 	StopBeforeSettingUpKubernetes bool // if false kind should setup kubernetes after creating nodes
 	// Options to control output
@@ -76,6 +100,18 @@ func Cluster(logger log.Logger, p providers.Provider, opts *ClusterOptions) erro
 	if err := fixupOptions(opts); err != nil {
 		return err
 	}
+	waitGates, err := waitforready.ParseGates(opts.WaitFor)
+	if err != nil {
+		return err
+	}
+
+	// ensure no other process is creating or deleting a cluster with this
+	// name until we are done
+	lock, err := clusterlock.Acquire(opts.Config.Name, clusterlock.Creating, opts.LockTimeout)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = lock.Release() }()
 
 	// Check if the cluster name already exists
 	if err := alreadyExists(p, opts.Config.Name); err != nil {
@@ -87,6 +123,27 @@ func Cluster(logger log.Logger, p providers.Provider, opts *ClusterOptions) erro
 		logger.Warnf("cluster name %q is probably too long, this might not work properly on some systems", opts.Config.Name)
 	}
 
+	// allocate subnets that do not overlap with other clusters, concurrent
+	// multi-cluster creates wait until the nodes have recorded them
+	var subnetLock *clusterlock.Lock
+	defer func() {
+		if subnetLock != nil {
+			_ = subnetLock.Release()
+		}
+	}()
+	if opts.Config.Networking.MultiCluster {
+		subnetLock, err = clusterlock.AcquireSubnetAllocation(opts.Config.Name, subnetLockTimeout)
+		if err != nil {
+			return err
+		}
+		if err := multicluster.AllocateSubnets(logger, p, opts.Config); err != nil {
+			return err
+		}
+		if err := multicluster.ValidateNetwork(logger, p, opts.Config); err != nil {
+			return err
+		}
+	}
+
 	// then validate
 	if err := opts.Config.Validate(); err != nil {
 		return err
@@ -102,11 +159,17 @@ func Cluster(logger log.Logger, p providers.Provider, opts *ClusterOptions) erro
 	if err := p.Provision(status, opts.Config); err != nil {
 		// In case of errors nodes are deleted (except if retain is explicitly set)
 		if !opts.Retain {
-			_ = delete.Cluster(logger, p, opts.Config.Name, opts.KubeconfigPath)
+			_ = delete.ClusterLocked(logger, p, opts.Config.Name, opts.KubeconfigPath)
 		}
 		return err
 	}
 
+	// the nodes record the allocated subnets, let other clusters allocate
+	if subnetLock != nil {
+		_ = subnetLock.Release()
+		subnetLock = nil
+	}
+
 	// TODO(bentheelder): make this controllable from the command line?
 	actionsToRun := []actions.Action{
 		loadbalancer.NewAction(), // setup external loadbalancer
@@ -124,9 +187,17 @@ func Cluster(logger log.Logger, p providers.Provider, opts *ClusterOptions) erro
 		}
 		// add remaining steps
 		actionsToRun = append(actionsToRun,
-			installstorage.NewAction(),                // install StorageClass
-			kubeadmjoin.NewAction(),                   // run kubeadm join
-			waitforready.NewAction(opts.WaitForReady), // wait for cluster readiness
+			installstorage.NewAction(), // install StorageClass
+			kubeadmjoin.NewAction(),    // run kubeadm join
+		)
+		// route to other multi-cluster clusters once all nodes have joined
+		if opts.Config.Networking.MultiCluster {
+			actionsToRun = append(actionsToRun,
+				multicluster.NewAction(), // install routes to other clusters
+			)
+		}
+		actionsToRun = append(actionsToRun,
+			waitforready.NewAction(opts.WaitForReady, waitGates, opts.FailOnWaitTimeout), // wait for cluster readiness
 		)
 	}
 
@@ -135,7 +206,7 @@ func Cluster(logger log.Logger, p providers.Provider, opts *ClusterOptions) erro
 	for _, action := range actionsToRun {
 		if err := action.Execute(actionsContext); err != nil {
 			if !opts.Retain {
-				_ = delete.Cluster(logger, p, opts.Config.Name, opts.KubeconfigPath)
+				_ = delete.ClusterLocked(logger, p, opts.Config.Name, opts.KubeconfigPath)
 			}
 			return err
 		}
@@ -149,10 +220,9 @@ func Cluster(logger log.Logger, p providers.Provider, opts *ClusterOptions) erro
 	// try exporting kubeconfig with backoff for locking failures
 	// TODO: factor out into a public errors API w/ backoff handling?
 	// for now this is easier than coming up with a good API
-	var err error
 	for _, b := range []time.Duration{0, time.Millisecond, time.Millisecond * 50, time.Millisecond * 100} {
 		time.Sleep(b)
-		if err = kubeconfig.Export(p, opts.Config.Name, opts.KubeconfigPath, true); err == nil {
+		if err = kubeconfig.Export(p, opts.Config.Name, opts.KubeconfigPath, true, opts.KubeconfigStrategy); err == nil {
 			break
 		}
 	}
@@ -162,7 +232,11 @@ func Cluster(logger log.Logger, p providers.Provider, opts *ClusterOptions) erro
 
 	// optionally display usage
 	if opts.DisplayUsage {
-		logUsage(logger, opts.Config.Name, opts.KubeconfigPath)
+		configPath, err := kubeconfig.ResolvePath(opts.Config.Name, opts.KubeconfigPath, opts.KubeconfigStrategy)
+		if err != nil {
+			return err
+		}
+		logUsage(logger, opts.Config.Name, configPath, opts.KubeconfigPath == "")
 	}
 	// optionally give the user a friendly salutation
 	if opts.DisplaySalutation {
@@ -185,16 +259,22 @@ func alreadyExists(p providers.Provider, name string) error {
 	return nil
 }
 
-func logUsage(logger log.Logger, name, explicitKubeconfigPath string) {
+// logUsage logs how to use the cluster, kubeconfigPath is the path the
+// kubeconfig was written to if not the kubectl default, isolated is true
+// if it is the cluster's own file
+func logUsage(logger log.Logger, name, kubeconfigPath string, isolated bool) {
 	// construct a sample command for interacting with the cluster
 	kctx := kubeconfig.ContextForCluster(name)
 	sampleCommand := fmt.Sprintf("kubectl cluster-info --context %s", kctx)
-	if explicitKubeconfigPath != "" {
-		// explicit path, include this
-		sampleCommand += " --kubeconfig " + shellescape.Quote(explicitKubeconfigPath)
+	if kubeconfigPath != "" {
+		// non-default path, include this
+		sampleCommand += " --kubeconfig " + shellescape.Quote(kubeconfigPath)
 	}
 	logger.V(0).Infof(`Set kubectl context to "%s"`, kctx)
 	logger.V(0).Infof("You can now use your cluster with:\n\n" + sampleCommand)
+	if isolated && kubeconfigPath != "" {
+		logger.V(0).Infof("\nOr point kubectl at it in this shell with:\n\neval \"$(kind env %s)\"", shellescape.Quote(name))
+	}
 }
 
 func logSalutation(logger log.Logger) {
@@ -234,27 +314,53 @@ func fixupOptions(opts *ClusterOptions) error {
 		}
 	}
 
+	// merge in cluster labels and annotations
+	opts.Config.Labels = mergeMetadata(opts.Config.Labels, opts.Labels)
+	opts.Config.Annotations = mergeMetadata(opts.Config.Annotations, opts.Annotations)
+
 	// default config fields (important for usage as a library, where the config
 	// may be constructed in memory rather than from disk)
 	config.SetDefaultsCluster(opts.Config)
 
+	// if DefaultNodeImage was set, override the image on nodes using the default
+	if opts.DefaultNodeImage != "" {
+		for i := range opts.Config.Nodes {
+			if opts.Config.Nodes[i].Image == defaults.Image {
+				opts.Config.Nodes[i].Image = opts.DefaultNodeImage
+			}
+		}
+	}
+
 	return nil
 }
 
+// mergeMetadata returns base with the values from overrides added
+func mergeMetadata(base, overrides map[string]string) map[string]string {
+	if len(overrides) == 0 {
+		return base
+	}
+	merged := make(map[string]string, len(base)+len(overrides))
+	for k, v := range base {
+		merged[k] = v
+	}
+	for k, v := range overrides {
+		merged[k] = v
+	}
+	return merged
+}
+
 func validateProvider(logger log.Logger, p providers.Provider) error {
 	info, err := p.Info()
 	if err != nil {
 		return err
 	}
-	if info.Rootless {
-		if !info.Cgroup2 {
-			return errors.New("running kind with rootless provider requires cgroup v2, see https://kind.sigs.k8s.io/docs/user/rootless/")
-		}
-		if !info.SupportsMemoryLimit || !info.SupportsPidsLimit || !info.SupportsCPUShares {
-			return errors.New("running kind with rootless provider requires setting systemd property \"Delegate=yes\", see https://kind.sigs.k8s.io/docs/user/rootless/")
+	for _, result := range doctor.CheckProviderInfo(info) {
+		switch result.Status {
+		case doctor.Fail:
+			return errors.New(result.Message)
+		case doctor.Warn:
+			logger.Warn(result.Message)
 		}
-	} else if !info.Cgroup2 {
-		logger.Warn("cgroup v1 is deprecated in Kubernetes and will not be supported in a future kind release, please upgrade to cgroup v2")
 	}
 	return nil
 }
@@ -18,17 +18,52 @@ limitations under the License.
 package delete
 
 import (
+	stderrors "errors"
+	"time"
+
 	"sigs.k8s.io/kind/pkg/errors"
 	"sigs.k8s.io/kind/pkg/log"
 
+	"sigs.k8s.io/kind/pkg/cluster/internal/clusterlock"
+	"sigs.k8s.io/kind/pkg/cluster/internal/create/actions/multicluster"
 	"sigs.k8s.io/kind/pkg/cluster/internal/kubeconfig"
 	"sigs.k8s.io/kind/pkg/cluster/internal/providers"
 )
 
+// DefaultLockTimeout is how long deletion waits by default for another
+// process creating or deleting the cluster to finish
+const DefaultLockTimeout = 5 * time.Minute
+
+// Options are the cluster deletion options
+type Options struct {
+	// LockTimeout is how long to wait for another process creating or
+	// deleting the cluster to finish
+	LockTimeout time.Duration
+	// Force deletes the cluster even if another process still holds its
+	// lock after LockTimeout, which will make that process fail
+	Force bool
+}
+
 // Cluster deletes the cluster identified by ctx
 // explicitKubeconfigPath is --kubeconfig, following the rules from
 // https://kubernetes.io/docs/reference/generated/kubectl/kubectl-commands
-func Cluster(logger log.Logger, p providers.Provider, name, explicitKubeconfigPath string) error {
+func Cluster(logger log.Logger, p providers.Provider, name, explicitKubeconfigPath string, opts *Options) error {
+	lock, err := clusterlock.Acquire(name, clusterlock.Deleting, opts.LockTimeout)
+	if err != nil {
+		var held *clusterlock.HeldError
+		if !opts.Force || !stderrors.As(err, &held) {
+			return err
+		}
+		logger.Warnf("Deleting cluster %q anyway: %v", name, err)
+		return ClusterLocked(logger, p, name, explicitKubeconfigPath)
+	}
+	defer func() { _ = lock.Release() }()
+	return ClusterLocked(logger, p, name, explicitKubeconfigPath)
+}
+
+// ClusterLocked is Cluster for callers already holding the cluster's lock,
+// see clusterlock.Acquire
+func ClusterLocked(logger log.Logger, p providers.Provider, name, explicitKubeconfigPath string) error {
 	n, err := p.ListNodes(name)
 	if err != nil {
 		return errors.Wrap(err, "error listing nodes")
@@ -40,6 +75,10 @@ func Cluster(logger log.Logger, p providers.Provider, name, explicitKubeconfigPa
 	}
 
 	if len(n) > 0 {
+		// the other multi-cluster clusters must stop routing to the nodes
+		if err := multicluster.Unroute(logger, p, name); err != nil {
+			logger.Warnf("failed to remove the routes to cluster %q from other clusters: %v", name, err)
+		}
 		err = p.DeleteNodes(n)
 		if err != nil {
 			return err
@@ -0,0 +1,448 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package doctor implements checks of the host environment for problems
+// that commonly cause cluster creation to fail.
+package doctor
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"sigs.k8s.io/kind/pkg/errors"
+	"sigs.k8s.io/kind/pkg/exec"
+	"sigs.k8s.io/kind/pkg/internal/apis/config"
+
+	"sigs.k8s.io/kind/pkg/cluster/internal/providers"
+	"sigs.k8s.io/kind/pkg/cluster/internal/providers/common"
+)
+
+// Status is the outcome of a check
+type Status string
+
+const (
+	// Pass means no problem was found
+	Pass Status = "pass"
+	// Warn means cluster creation may fail or the cluster may misbehave
+	Warn Status = "warn"
+	// Fail means cluster creation is expected to fail
+	Fail Status = "fail"
+)
+
+// Result is the result of a check
+type Result struct {
+	Check   string
+	Status  Status
+	Message string
+	// Remediation describes how to fix a warning or failure
+	Remediation string
+}
+
+// Run runs all of the checks for creating a cluster with cfg using p
+func Run(p providers.Provider, cfg *config.Cluster) []Result {
+	return run(p, cfg, realHost())
+}
+
+func run(p providers.Provider, cfg *config.Cluster, h *host) []Result {
+	results := []Result{}
+	info, err := p.Info()
+	if err != nil {
+		results = append(results, Result{
+			Check:       "provider",
+			Status:      Fail,
+			Message:     fmt.Sprintf("failed to get node provider info: %v", err),
+			Remediation: "ensure the container runtime is installed and running, and that you have permission to use it",
+		})
+	} else {
+		results = append(results, Result{
+			Check:   "provider",
+			Status:  Pass,
+			Message: fmt.Sprintf("node provider %s is available", p),
+		})
+		results = append(results, CheckProviderInfo(info)...)
+		results = append(results, checkDiskSpace(h, info.StorageRoot)...)
+	}
+	// the remaining checks only depend on the host
+	results = append(results, checkInotify(h)...)
+	results = append(results, checkPorts(h, cfg)...)
+	results = append(results, checkProxy(h, cfg, nodeSubnets(p, cfg))...)
+	results = append(results, checkKernelModules(h, cfg)...)
+	return results
+}
+
+const rootlessDocs = "see https://kind.sigs.k8s.io/docs/user/rootless/"
+
+// CheckProviderInfo checks the node provider's capabilities, the Message of
+// failures is suitable for use as an error
+func CheckProviderInfo(info *providers.ProviderInfo) []Result {
+	results := []Result{}
+	if info.Rootless {
+		switch {
+		case !info.Cgroup2:
+			results = append(results, Result{
+				Check:       "rootless",
+				Status:      Fail,
+				Message:     "running kind with rootless provider requires cgroup v2, " + rootlessDocs,
+				Remediation: "boot the host with cgroup v2 enabled",
+			})
+		case !info.SupportsMemoryLimit || !info.SupportsPidsLimit || !info.SupportsCPUShares:
+			results = append(results, Result{
+				Check:       "rootless",
+				Status:      Fail,
+				Message:     "running kind with rootless provider requires setting systemd property \"Delegate=yes\", " + rootlessDocs,
+				Remediation: "delegate the memory, pids and cpu cgroup controllers to your user",
+			})
+		default:
+			results = append(results, Result{
+				Check:   "rootless",
+				Status:  Pass,
+				Message: "cgroup controllers are delegated for rootless mode",
+			})
+		}
+	}
+	if info.Cgroup2 {
+		results = append(results, Result{
+			Check:   "cgroups",
+			Status:  Pass,
+			Message: "cgroup v2 is enabled",
+		})
+	} else {
+		results = append(results, Result{
+			Check:       "cgroups",
+			Status:      Warn,
+			Message:     "cgroup v1 is deprecated in Kubernetes and will not be supported in a future kind release, please upgrade to cgroup v2",
+			Remediation: "boot the host with cgroup v2 enabled, E.G. with systemd.unified_cgroup_hierarchy=1",
+		})
+	}
+	return results
+}
+
+// host abstracts the host environment for testing
+type host struct {
+	goos     string
+	readFile func(path string) ([]byte, error)
+	getEnv   func(key string) string
+	// portFree returns nil if a listener can be bound to address
+	portFree func(address string) error
+	// freeBytes returns the free space of the filesystem containing path
+	freeBytes func(path string) (uint64, error)
+}
+
+func realHost() *host {
+	return &host{
+		goos:     runtime.GOOS,
+		readFile: os.ReadFile,
+		getEnv:   os.Getenv,
+		portFree: func(address string) error {
+			l, err := net.Listen("tcp", address)
+			if err != nil {
+				return err
+			}
+			return l.Close()
+		},
+		freeBytes: func(path string) (uint64, error) {
+			out, err := exec.Output(exec.Command("df", "-Pk", path))
+			if err != nil {
+				return 0, err
+			}
+			return parseDFAvailable(string(out))
+		},
+	}
+}
+
+// parseDFAvailable returns the available bytes in `df -Pk` output
+func parseDFAvailable(out string) (uint64, error) {
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 2 {
+		return 0, errors.Errorf("unexpected df output: %q", out)
+	}
+	fields := strings.Fields(lines[1])
+	if len(fields) < 4 {
+		return 0, errors.Errorf("unexpected df output: %q", out)
+	}
+	kb, err := strconv.ParseUint(fields[3], 10, 64)
+	if err != nil {
+		return 0, errors.Errorf("unexpected df output: %q", out)
+	}
+	return kb * 1024, nil
+}
+
+// the recommended inotify limits, see the known issues docs
+var inotifyLimits = []struct {
+	name    string
+	minimum int
+}{
+	{"max_user_watches", 524288},
+	{"max_user_instances", 512},
+}
+
+// checkInotify checks the inotify limits, which are not namespaced
+func checkInotify(h *host) []Result {
+	// only linux hosts run the nodes on the same kernel
+	if h.goos != "linux" {
+		return nil
+	}
+	low := []string{}
+	for _, limit := range inotifyLimits {
+		raw, err := h.readFile("/proc/sys/fs/inotify/" + limit.name)
+		if err != nil {
+			continue
+		}
+		value, err := strconv.Atoi(strings.TrimSpace(string(raw)))
+		if err != nil || value >= limit.minimum {
+			continue
+		}
+		low = append(low, fmt.Sprintf("fs.inotify.%s=%d", limit.name, value))
+	}
+	if len(low) > 0 {
+		remediation := []string{}
+		for _, limit := range inotifyLimits {
+			remediation = append(remediation, fmt.Sprintf("sudo sysctl fs.inotify.%s=%d", limit.name, limit.minimum))
+		}
+		return []Result{{
+			Check:       "inotify",
+			Status:      Warn,
+			Message:     fmt.Sprintf("inotify limits are low (%s), pods may fail with \"too many open files\"", strings.Join(low, ", ")),
+			Remediation: strings.Join(remediation, " && "),
+		}}
+	}
+	return []Result{{
+		Check:   "inotify",
+		Status:  Pass,
+		Message: "inotify limits are sufficient",
+	}}
+}
+
+const (
+	gib = 1 << 30
+	// minimumFreeBytes is roughly the space needed for a node image and
+	// a few workload images
+	minimumFreeBytes = 5 * gib
+	// recommendedFreeBytes avoids kubelet image garbage collection and
+	// disk pressure evictions
+	recommendedFreeBytes = 20 * gib
+)
+
+// checkDiskSpace checks the free space of the runtime storage, if it is
+// on the host
+func checkDiskSpace(h *host, storageRoot string) []Result {
+	if h.goos != "linux" || storageRoot == "" {
+		return nil
+	}
+	free, err := h.freeBytes(storageRoot)
+	if err != nil {
+		// the storage may be inside a VM or not readable by the user
+		return nil
+	}
+	message := fmt.Sprintf("%.1f GiB free in %s", float64(free)/gib, storageRoot)
+	remediation := "free up disk space, E.G. by removing unused images and containers"
+	switch {
+	case free < minimumFreeBytes:
+		return []Result{{Check: "disk", Status: Fail, Message: message, Remediation: remediation}}
+	case free < recommendedFreeBytes:
+		return []Result{{Check: "disk", Status: Warn, Message: message, Remediation: remediation}}
+	default:
+		return []Result{{Check: "disk", Status: Pass, Message: message}}
+	}
+}
+
+// checkPorts checks that the fixed host ports of the cluster are free
+func checkPorts(h *host, cfg *config.Cluster) []Result {
+	addresses := []string{}
+	if cfg.Networking.APIServerPort != 0 {
+		addresses = append(addresses, net.JoinHostPort(cfg.Networking.APIServerAddress, strconv.Itoa(int(cfg.Networking.APIServerPort))))
+	}
+	for _, n := range cfg.Nodes {
+		for _, m := range n.ExtraPortMappings {
+			// only TCP ports are probed
+			if m.HostPort <= 0 || (m.Protocol != "" && m.Protocol != config.PortMappingProtocolTCP) {
+				continue
+			}
+			addresses = append(addresses, net.JoinHostPort(m.ListenAddress, strconv.Itoa(int(m.HostPort))))
+		}
+	}
+	if len(addresses) == 0 {
+		return nil
+	}
+	results := []Result{}
+	for _, address := range addresses {
+		if err := h.portFree(address); err != nil {
+			results = append(results, Result{
+				Check:       "ports",
+				Status:      Fail,
+				Message:     fmt.Sprintf("host port %s is not available: %v", address, err),
+				Remediation: "stop the process or cluster using the port, or change the port in the config",
+			})
+		}
+	}
+	if len(results) == 0 {
+		results = append(results, Result{
+			Check:   "ports",
+			Status:  Pass,
+			Message: fmt.Sprintf("host ports %s are available", strings.Join(addresses, ", ")),
+		})
+	}
+	return results
+}
+
+// nodeSubnets returns the subnets of the container network for the nodes,
+// which kind only knows if the network exists or its subnets are configured
+func nodeSubnets(p providers.Provider, cfg *config.Cluster) []string {
+	if subnets, err := p.NetworkSubnets(cfg); err == nil {
+		return subnets
+	}
+	subnets := []string{}
+	for _, subnet := range []string{cfg.Networking.ContainerNetwork.IPv4Subnet, cfg.Networking.ContainerNetwork.IPv6Subnet} {
+		if subnet != "" {
+			subnets = append(subnets, subnet)
+		}
+	}
+	return subnets
+}
+
+// checkProxy checks that a proxy configured on the host is bypassed for the
+// API server address and the pod, service and node subnets
+func checkProxy(h *host, cfg *config.Cluster, nodeSubnets []string) []Result {
+	if proxyEnv(h, common.HTTPProxy) == "" && proxyEnv(h, common.HTTPSProxy) == "" {
+		return nil
+	}
+	noProxy := proxyEnv(h, common.NOProxy)
+	addresses := []string{cfg.Networking.APIServerAddress}
+	for _, subnets := range []string{cfg.Networking.PodSubnet, cfg.Networking.ServiceSubnet} {
+		for _, subnet := range strings.Split(subnets, ",") {
+			if subnet = strings.TrimSpace(subnet); subnet != "" {
+				addresses = append(addresses, subnet)
+			}
+		}
+	}
+	addresses = append(addresses, nodeSubnets...)
+	missing := []string{}
+	for _, address := range addresses {
+		if !noProxyCovers(noProxy, address) {
+			missing = append(missing, address)
+		}
+	}
+	if len(missing) > 0 {
+		return []Result{{
+			Check:       "proxy",
+			Status:      Warn,
+			Message:     fmt.Sprintf("a proxy is configured but NO_PROXY does not include %s, requests to the cluster may be sent to the proxy", strings.Join(missing, ", ")),
+			Remediation: fmt.Sprintf("add %s to NO_PROXY", strings.Join(missing, ",")),
+		}}
+	}
+	return []Result{{
+		Check:   "proxy",
+		Status:  Pass,
+		Message: "NO_PROXY includes the API server address and the cluster subnets",
+	}}
+}
+
+// proxyEnv returns the value of the proxy variable name, which may be set
+// in upper or lower case
+func proxyEnv(h *host, name string) string {
+	if val := h.getEnv(name); val != "" {
+		return val
+	}
+	return h.getEnv(strings.ToLower(name))
+}
+
+// noProxyCovers returns true if the NO_PROXY list covers address, which is
+// an IP address or a CIDR
+func noProxyCovers(noProxy, address string) bool {
+	ip, subnet, err := net.ParseCIDR(address)
+	if err != nil {
+		ip = net.ParseIP(address)
+	}
+	for _, entry := range strings.Split(noProxy, ",") {
+		entry = strings.TrimSpace(entry)
+		switch {
+		case entry == "":
+			continue
+		case entry == "*" || entry == address:
+			return true
+		case ip != nil:
+			_, cidr, err := net.ParseCIDR(entry)
+			if err != nil || !cidr.Contains(ip) {
+				continue
+			}
+			// a subnet must fit entirely in the entry
+			if subnet == nil || cidrOnes(cidr) <= cidrOnes(subnet) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// cidrOnes returns the prefix length of cidr
+func cidrOnes(cidr *net.IPNet) int {
+	ones, _ := cidr.Mask.Size()
+	return ones
+}
+
+// the kernel modules needed by each kube-proxy mode
+var proxyModeModules = map[config.ProxyMode][]string{
+	config.IPVSProxyMode:     {"ip_vs", "ip_vs_rr", "ip_vs_wrr", "ip_vs_sh", "nf_conntrack"},
+	config.NFTablesProxyMode: {"nf_tables"},
+}
+
+// checkKernelModules checks that the kernel modules needed by kube-proxy are
+// loaded or built in, the nodes share the host kernel but cannot load modules
+func checkKernelModules(h *host, cfg *config.Cluster) []Result {
+	modules := proxyModeModules[cfg.Networking.KubeProxyMode]
+	if h.goos != "linux" || len(modules) == 0 {
+		return nil
+	}
+	available := map[string]bool{}
+	if loaded, err := h.readFile("/proc/modules"); err == nil {
+		for _, line := range strings.Split(string(loaded), "\n") {
+			if fields := strings.Fields(line); len(fields) > 0 {
+				available[fields[0]] = true
+			}
+		}
+	}
+	if release, err := h.readFile("/proc/sys/kernel/osrelease"); err == nil {
+		builtin, _ := h.readFile("/lib/modules/" + strings.TrimSpace(string(release)) + "/modules.builtin")
+		for _, line := range strings.Split(string(builtin), "\n") {
+			// E.G. kernel/net/netfilter/nf_tables.ko
+			name := strings.TrimSuffix(line[strings.LastIndex(line, "/")+1:], ".ko")
+			available[strings.ReplaceAll(name, "-", "_")] = true
+		}
+	}
+	missing := []string{}
+	for _, module := range modules {
+		if !available[module] {
+			missing = append(missing, module)
+		}
+	}
+	if len(missing) > 0 {
+		return []Result{{
+			Check:       "kernel modules",
+			Status:      Warn,
+			Message:     fmt.Sprintf("kube-proxy mode %s needs kernel modules that are not loaded: %s", cfg.Networking.KubeProxyMode, strings.Join(missing, ", ")),
+			Remediation: "sudo modprobe " + strings.Join(missing, " "),
+		}}
+	}
+	return []Result{{
+		Check:   "kernel modules",
+		Status:  Pass,
+		Message: fmt.Sprintf("kernel modules for kube-proxy mode %s are available", cfg.Networking.KubeProxyMode),
+	}}
+}
@@ -0,0 +1,331 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package doctor
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"sigs.k8s.io/kind/pkg/internal/apis/config"
+	"sigs.k8s.io/kind/pkg/internal/assert"
+
+	"sigs.k8s.io/kind/pkg/cluster/internal/providers"
+)
+
+// fakeHost returns a linux host with the given files
+func fakeHost(files map[string]string) *host {
+	return &host{
+		goos: "linux",
+		readFile: func(path string) ([]byte, error) {
+			content, ok := files[path]
+			if !ok {
+				return nil, os.ErrNotExist
+			}
+			return []byte(content), nil
+		},
+		getEnv:    func(string) string { return "" },
+		portFree:  func(string) error { return nil },
+		freeBytes: func(string) (uint64, error) { return 100 * gib, nil },
+	}
+}
+
+func statuses(results []Result) []Status {
+	out := []Status{}
+	for _, r := range results {
+		out = append(out, r.Status)
+	}
+	return out
+}
+
+func TestCheckProviderInfo(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		Name     string
+		Info     providers.ProviderInfo
+		Expected []Status
+	}{
+		{
+			Name:     "cgroup v2",
+			Info:     providers.ProviderInfo{Cgroup2: true},
+			Expected: []Status{Pass},
+		},
+		{
+			Name:     "cgroup v1",
+			Info:     providers.ProviderInfo{},
+			Expected: []Status{Warn},
+		},
+		{
+			Name:     "rootless cgroup v1",
+			Info:     providers.ProviderInfo{Rootless: true},
+			Expected: []Status{Fail, Warn},
+		},
+		{
+			Name:     "rootless without delegation",
+			Info:     providers.ProviderInfo{Rootless: true, Cgroup2: true, SupportsMemoryLimit: true},
+			Expected: []Status{Fail, Pass},
+		},
+		{
+			Name: "rootless with delegation",
+			Info: providers.ProviderInfo{
+				Rootless:            true,
+				Cgroup2:             true,
+				SupportsMemoryLimit: true,
+				SupportsPidsLimit:   true,
+				SupportsCPUShares:   true,
+			},
+			Expected: []Status{Pass, Pass},
+		},
+	}
+	for _, tc := range cases {
+		tc := tc // capture range variable
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+			assert.DeepEqual(t, tc.Expected, statuses(CheckProviderInfo(&tc.Info)))
+		})
+	}
+}
+
+func TestCheckInotify(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		Name     string
+		GOOS     string
+		Files    map[string]string
+		Expected []Status
+	}{
+		{
+			Name: "sufficient",
+			GOOS: "linux",
+			Files: map[string]string{
+				"/proc/sys/fs/inotify/max_user_watches":   "524288\n",
+				"/proc/sys/fs/inotify/max_user_instances": "8192\n",
+			},
+			Expected: []Status{Pass},
+		},
+		{
+			Name: "low instances",
+			GOOS: "linux",
+			Files: map[string]string{
+				"/proc/sys/fs/inotify/max_user_watches":   "524288\n",
+				"/proc/sys/fs/inotify/max_user_instances": "128\n",
+			},
+			Expected: []Status{Warn},
+		},
+		{
+			Name:     "not linux",
+			GOOS:     "darwin",
+			Expected: []Status{},
+		},
+	}
+	for _, tc := range cases {
+		tc := tc // capture range variable
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+			h := fakeHost(tc.Files)
+			h.goos = tc.GOOS
+			assert.DeepEqual(t, tc.Expected, statuses(checkInotify(h)))
+		})
+	}
+}
+
+func TestCheckDiskSpace(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		Name        string
+		StorageRoot string
+		Free        uint64
+		Err         error
+		Expected    []Status
+	}{
+		{
+			Name:        "plenty",
+			StorageRoot: "/var/lib/docker",
+			Free:        100 * gib,
+			Expected:    []Status{Pass},
+		},
+		{
+			Name:        "low",
+			StorageRoot: "/var/lib/docker",
+			Free:        10 * gib,
+			Expected:    []Status{Warn},
+		},
+		{
+			Name:        "too low",
+			StorageRoot: "/var/lib/docker",
+			Free:        1 * gib,
+			Expected:    []Status{Fail},
+		},
+		{
+			Name:        "not on the host",
+			StorageRoot: "/var/lib/docker",
+			Err:         errors.New("no such file or directory"),
+			Expected:    []Status{},
+		},
+		{
+			Name:     "unknown storage root",
+			Expected: []Status{},
+		},
+	}
+	for _, tc := range cases {
+		tc := tc // capture range variable
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+			h := fakeHost(nil)
+			h.freeBytes = func(string) (uint64, error) { return tc.Free, tc.Err }
+			assert.DeepEqual(t, tc.Expected, statuses(checkDiskSpace(h, tc.StorageRoot)))
+		})
+	}
+}
+
+func TestCheckPorts(t *testing.T) {
+	t.Parallel()
+	cfg := &config.Cluster{
+		Networking: config.Networking{
+			APIServerAddress: "127.0.0.1",
+			APIServerPort:    6443,
+		},
+		Nodes: []config.Node{{
+			ExtraPortMappings: []config.PortMapping{
+				{HostPort: 80, ListenAddress: "0.0.0.0", Protocol: config.PortMappingProtocolTCP},
+				{HostPort: 53, ListenAddress: "0.0.0.0", Protocol: config.PortMappingProtocolUDP},
+				// random host port
+				{HostPort: 0, ListenAddress: "0.0.0.0", Protocol: config.PortMappingProtocolTCP},
+			},
+		}},
+	}
+	probed := []string{}
+	h := fakeHost(nil)
+	h.portFree = func(address string) error {
+		probed = append(probed, address)
+		if address == "0.0.0.0:80" {
+			return errors.New("address already in use")
+		}
+		return nil
+	}
+	assert.DeepEqual(t, []Status{Fail}, statuses(checkPorts(h, cfg)))
+	assert.DeepEqual(t, []string{"127.0.0.1:6443", "0.0.0.0:80"}, probed)
+}
+
+func TestNoProxyCovers(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		NoProxy  string
+		Address  string
+		Expected bool
+	}{
+		{NoProxy: "", Address: "127.0.0.1", Expected: false},
+		{NoProxy: "localhost, 127.0.0.1", Address: "127.0.0.1", Expected: true},
+		{NoProxy: "localhost,127.0.0.0/8", Address: "127.0.0.1", Expected: true},
+		{NoProxy: "*", Address: "::1", Expected: true},
+		{NoProxy: "localhost,10.0.0.0/8", Address: "127.0.0.1", Expected: false},
+		{NoProxy: "10.0.0.0/8", Address: "10.244.0.0/16", Expected: true},
+		{NoProxy: "10.244.0.0/16", Address: "10.244.0.0/16", Expected: true},
+		{NoProxy: "10.244.0.0/24", Address: "10.244.0.0/16", Expected: false},
+		{NoProxy: "10.0.0.0/8", Address: "fd00:10:244::/56", Expected: false},
+	}
+	for _, tc := range cases {
+		tc := tc // capture range variable
+		t.Run(tc.NoProxy, func(t *testing.T) {
+			t.Parallel()
+			assert.BoolEqual(t, tc.Expected, noProxyCovers(tc.NoProxy, tc.Address))
+		})
+	}
+}
+
+func TestCheckProxy(t *testing.T) {
+	t.Parallel()
+	cfg := &config.Cluster{
+		Networking: config.Networking{
+			APIServerAddress: "127.0.0.1",
+			PodSubnet:        "10.244.0.0/16",
+			ServiceSubnet:    "10.96.0.0/16",
+		},
+	}
+	cases := []struct {
+		Name     string
+		Env      map[string]string
+		Expected []Status
+	}{
+		{
+			Name:     "no proxy",
+			Env:      map[string]string{"NO_PROXY": "localhost"},
+			Expected: []Status{},
+		},
+		{
+			Name:     "node subnet not excluded",
+			Env:      map[string]string{"https_proxy": "http://proxy:3128", "no_proxy": "127.0.0.1,10.0.0.0/8"},
+			Expected: []Status{Warn},
+		},
+		{
+			Name:     "all excluded",
+			Env:      map[string]string{"HTTP_PROXY": "http://proxy:3128", "NO_PROXY": "127.0.0.1,10.0.0.0/8,172.18.0.0/16"},
+			Expected: []Status{Pass},
+		},
+	}
+	for _, tc := range cases {
+		tc := tc // capture range variable
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+			h := fakeHost(nil)
+			h.getEnv = func(key string) string { return tc.Env[key] }
+			assert.DeepEqual(t, tc.Expected, statuses(checkProxy(h, cfg, []string{"172.18.0.0/16"})))
+		})
+	}
+}
+
+func TestCheckKernelModules(t *testing.T) {
+	t.Parallel()
+	files := map[string]string{
+		"/proc/modules": "ip_vs 172032 6 ip_vs_rr, Live 0x0000000000000000\n" +
+			"ip_vs_rr 12288 0 - Live 0x0000000000000000\n" +
+			"nf_conntrack 196608 5 ip_vs, Live 0x0000000000000000\n",
+		"/proc/sys/kernel/osrelease":         "6.1.0\n",
+		"/lib/modules/6.1.0/modules.builtin": "kernel/net/netfilter/ipvs/ip_vs_wrr.ko\nkernel/net/netfilter/nf_tables.ko\n",
+	}
+	cases := []struct {
+		Name     string
+		Mode     config.ProxyMode
+		Expected []Status
+	}{
+		{Name: "iptables", Mode: config.IPTablesProxyMode, Expected: []Status{}},
+		{Name: "nftables", Mode: config.NFTablesProxyMode, Expected: []Status{Pass}},
+		// missing ip_vs_sh
+		{Name: "ipvs", Mode: config.IPVSProxyMode, Expected: []Status{Warn}},
+	}
+	for _, tc := range cases {
+		tc := tc // capture range variable
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+			cfg := &config.Cluster{Networking: config.Networking{KubeProxyMode: tc.Mode}}
+			assert.DeepEqual(t, tc.Expected, statuses(checkKernelModules(fakeHost(files), cfg)))
+		})
+	}
+}
+
+func TestParseDFAvailable(t *testing.T) {
+	t.Parallel()
+	out := "Filesystem     1024-blocks      Used Available Capacity Mounted on\n" +
+		"/dev/nvme0n1p2   479151816 301216716 153500540      67% /\n"
+	free, err := parseDFAvailable(out)
+	assert.ExpectError(t, false, err)
+	if free != 153500540*1024 {
+		t.Errorf("expected %d but got %d", 153500540*1024, free)
+	}
+	_, err = parseDFAvailable("")
+	assert.ExpectError(t, true, err)
+}
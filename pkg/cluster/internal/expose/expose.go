@@ -0,0 +1,174 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package expose implements publishing additional host ports on a running
+// cluster by forwarding them to the nodes.
+package expose
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"sigs.k8s.io/kind/pkg/cluster/nodes"
+	"sigs.k8s.io/kind/pkg/cluster/nodeutils"
+	"sigs.k8s.io/kind/pkg/errors"
+	"sigs.k8s.io/kind/pkg/internal/apis/config"
+
+	"sigs.k8s.io/kind/pkg/cluster/internal/loadbalancer"
+	"sigs.k8s.io/kind/pkg/cluster/internal/providers"
+)
+
+// bootstrapTimeout is how long to wait for a new forwarder to start Envoy
+const bootstrapTimeout = 30 * time.Second
+
+// Port publishes mapping.HostPort on the host and forwards it to
+// mapping.ContainerPort on the node target of cluster, or on the bootstrap
+// control plane node if target is empty. The forwarder node is returned.
+func Port(p providers.Provider, cluster, target string, mapping config.PortMapping) (nodes.Node, error) {
+	allNodes, err := p.ListNodes(cluster)
+	if err != nil {
+		return nil, errors.Wrap(err, "error listing nodes")
+	}
+	if len(allNodes) == 0 {
+		return nil, errors.Errorf("no nodes found for cluster %q", cluster)
+	}
+	targetNode, err := selectTarget(allNodes, target)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := defaultMapping(&mapping); err != nil {
+		return nil, err
+	}
+	ipv4, _, err := targetNode.IP()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get IP for node %q", targetNode.String())
+	}
+	ipv6 := ipv4 == ""
+	if mapping.ListenAddress == "" {
+		mapping.ListenAddress = "0.0.0.0"
+		if ipv6 {
+			mapping.ListenAddress = "::"
+		}
+	}
+
+	name := ForwarderName(targetNode.String(), mapping)
+	for _, n := range allNodes {
+		if n.String() == name {
+			return nil, errors.Errorf("port %d/%s of node %q is already exposed by %q", mapping.ContainerPort, strings.ToLower(string(mapping.Protocol)), targetNode.String(), name)
+		}
+	}
+
+	ldsConfig, cdsConfig, err := generateConfig(&loadbalancer.PortForwardConfigData{
+		Port:   mapping.ContainerPort,
+		Target: targetNode.String(),
+		UDP:    mapping.Protocol == config.PortMappingProtocolUDP,
+		IPv6:   ipv6,
+	})
+	if err != nil {
+		return nil, err
+	}
+	fwd, err := p.CreatePortForwarder(cluster, &providers.PortForwarder{
+		Name:      name,
+		Target:    targetNode.String(),
+		Mapping:   mapping,
+		LDSConfig: ldsConfig,
+		CDSConfig: cdsConfig,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := waitForBootstrap(fwd); err != nil {
+		// don't leave a forwarder that does not forward
+		_ = p.DeleteNodes([]nodes.Node{fwd})
+		return nil, err
+	}
+	return fwd, nil
+}
+
+// ForwarderName returns the name of the node forwarding mapping to target
+func ForwarderName(target string, mapping config.PortMapping) string {
+	return fmt.Sprintf("%s-expose-%d-%s", target, mapping.ContainerPort, strings.ToLower(string(mapping.Protocol)))
+}
+
+// selectTarget returns the Kubernetes node named target
+func selectTarget(allNodes []nodes.Node, target string) (nodes.Node, error) {
+	kubeNodes, err := nodeutils.InternalNodes(allNodes)
+	if err != nil {
+		return nil, err
+	}
+	if target == "" {
+		return nodeutils.BootstrapControlPlaneNode(kubeNodes)
+	}
+	for _, n := range kubeNodes {
+		if n.String() == target {
+			return n, nil
+		}
+	}
+	return nil, errors.Errorf("unknown node %q", target)
+}
+
+// defaultMapping defaults and validates the protocol and ports of mapping
+func defaultMapping(mapping *config.PortMapping) error {
+	if mapping.Protocol == "" {
+		mapping.Protocol = config.PortMappingProtocolTCP
+	}
+	mapping.Protocol = config.PortMappingProtocol(strings.ToUpper(string(mapping.Protocol)))
+	switch mapping.Protocol {
+	case config.PortMappingProtocolTCP, config.PortMappingProtocolUDP:
+	default:
+		return errors.Errorf("unsupported protocol %q, only TCP and UDP ports may be exposed", mapping.Protocol)
+	}
+	if mapping.ContainerPort < 1 || mapping.ContainerPort > 65535 {
+		return errors.Errorf("invalid container port %d", mapping.ContainerPort)
+	}
+	// -1 lets the runtime pick the host port, like in the config
+	if mapping.HostPort < -1 || mapping.HostPort > 65535 {
+		return errors.Errorf("invalid host port %d", mapping.HostPort)
+	}
+	return nil
+}
+
+// generateConfig returns the Envoy listener and cluster configs of a
+// forwarder, which are part of its command so that they survive restarts
+func generateConfig(data *loadbalancer.PortForwardConfigData) (ldsConfig, cdsConfig string, err error) {
+	ldsConfig, err = loadbalancer.Config(data, loadbalancer.PortForwardLDSConfigTemplate)
+	if err != nil {
+		return "", "", errors.Wrap(err, "failed to generate port forwarder config data")
+	}
+	cdsConfig, err = loadbalancer.Config(data, loadbalancer.PortForwardCDSConfigTemplate)
+	if err != nil {
+		return "", "", errors.Wrap(err, "failed to generate port forwarder config data")
+	}
+	return ldsConfig, cdsConfig, nil
+}
+
+// waitForBootstrap waits for the bootstrap command of the new forwarder fwd
+// to write its configs
+func waitForBootstrap(fwd nodes.Node) error {
+	deadline := time.Now().Add(bootstrapTimeout)
+	for {
+		err := fwd.Command("test", "-f", loadbalancer.ProxyConfigPathLDS).Run()
+		if err == nil {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return errors.Wrapf(err, "timed out waiting for port forwarder %q to start", fwd.String())
+		}
+		time.Sleep(250 * time.Millisecond)
+	}
+}
@@ -0,0 +1,81 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package expose
+
+import (
+	"testing"
+
+	"sigs.k8s.io/kind/pkg/internal/apis/config"
+	"sigs.k8s.io/kind/pkg/internal/assert"
+)
+
+func TestDefaultMapping(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		Name        string
+		Mapping     config.PortMapping
+		Expected    config.PortMapping
+		ExpectError bool
+	}{
+		{
+			Name:     "defaults to TCP",
+			Mapping:  config.PortMapping{HostPort: 8080, ContainerPort: 80},
+			Expected: config.PortMapping{HostPort: 8080, ContainerPort: 80, Protocol: config.PortMappingProtocolTCP},
+		},
+		{
+			Name:     "protocol is case insensitive",
+			Mapping:  config.PortMapping{ContainerPort: 53, Protocol: "udp"},
+			Expected: config.PortMapping{ContainerPort: 53, Protocol: config.PortMappingProtocolUDP},
+		},
+		{
+			Name:        "SCTP is not supported",
+			Mapping:     config.PortMapping{ContainerPort: 80, Protocol: config.PortMappingProtocolSCTP},
+			ExpectError: true,
+		},
+		{
+			Name:        "container port is required",
+			Mapping:     config.PortMapping{HostPort: 8080},
+			ExpectError: true,
+		},
+		{
+			Name:        "invalid host port",
+			Mapping:     config.PortMapping{HostPort: 70000, ContainerPort: 80},
+			ExpectError: true,
+		},
+	}
+	for _, tc := range cases {
+		tc := tc // capture range variable
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+			mapping := tc.Mapping
+			err := defaultMapping(&mapping)
+			assert.ExpectError(t, tc.ExpectError, err)
+			if !tc.ExpectError {
+				assert.DeepEqual(t, tc.Expected, mapping)
+			}
+		})
+	}
+}
+
+func TestForwarderName(t *testing.T) {
+	t.Parallel()
+	assert.StringEqual(t, "kind-worker-expose-80-tcp", ForwarderName("kind-worker", config.PortMapping{
+		HostPort:      8080,
+		ContainerPort: 80,
+		Protocol:      config.PortMappingProtocolTCP,
+	}))
+}
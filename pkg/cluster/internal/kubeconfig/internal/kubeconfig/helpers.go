@@ -25,6 +25,12 @@ func KINDClusterKey(clusterName string) string {
 	return "kind-" + clusterName
 }
 
+// KINDUserKey identifies users of kind clusters other than the kubeadm admin
+// in kubeconfig files, it is also used for the user's context
+func KINDUserKey(clusterName, user string) string {
+	return KINDClusterKey(clusterName) + "@" + user
+}
+
 // checkKubeadmExpectations validates that a kubeadm created KUBECONFIG meets
 // our expectations, namely on the number of entries
 func checkKubeadmExpectations(cfg *Config) error {
@@ -27,6 +27,11 @@ func TestKINDClusterKey(t *testing.T) {
 	assert.StringEqual(t, "kind-foobar", KINDClusterKey("foobar"))
 }
 
+func TestKINDUserKey(t *testing.T) {
+	t.Parallel()
+	assert.StringEqual(t, "kind-foobar@alice", KINDUserKey("foobar", "alice"))
+}
+
 func TestCheckKubeadmExpectations(t *testing.T) {
 	t.Parallel()
 	cases := []struct {
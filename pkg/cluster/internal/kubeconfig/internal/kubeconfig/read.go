@@ -17,6 +17,7 @@ limitations under the License.
 package kubeconfig
 
 import (
+	"encoding/base64"
 	"io"
 	"os"
 
@@ -58,6 +59,38 @@ func KINDFromRawKubeadm(rawKubeadmKubeConfig, clusterName, server string) (*Conf
 	return cfg, nil
 }
 
+// KINDForUser returns a kind kubeconfig for the user of the kind cluster
+// clusterName, derived from the cluster's kind kubeconfig (see
+// KINDFromRawKubeadm) and the user's PEM encoded client certificate and key
+func KINDForUser(kindConfig *Config, clusterName, user string, certPEM, keyPEM []byte) (*Config, error) {
+	// verify assumptions about kind kubeconfigs
+	if err := checkKubeadmExpectations(kindConfig); err != nil {
+		return nil, err
+	}
+
+	// the user and context share a key, the cluster is shared with the admin
+	key := KINDUserKey(clusterName, user)
+	return &Config{
+		Clusters: []NamedCluster{kindConfig.Clusters[0]},
+		Users: []NamedUser{{
+			Name: key,
+			User: map[string]interface{}{
+				"client-certificate-data": base64.StdEncoding.EncodeToString(certPEM),
+				"client-key-data":         base64.StdEncoding.EncodeToString(keyPEM),
+			},
+		}},
+		Contexts: []NamedContext{{
+			Name: key,
+			Context: Context{
+				Cluster: kindConfig.Clusters[0].Name,
+				User:    key,
+			},
+		}},
+		CurrentContext: key,
+		OtherFields:    kindConfig.OtherFields,
+	}, nil
+}
+
 // read loads a KUBECONFIG file from configPath
 func read(configPath string) (*Config, error) {
 	// try to open, return default if no such file
@@ -103,3 +103,35 @@ users:
 		}
 	})
 }
+
+func TestKINDForUser(t *testing.T) {
+	t.Parallel()
+	kindConfig := &Config{
+		Clusters: []NamedCluster{{
+			Name:    "kind-kind",
+			Cluster: Cluster{Server: "https://127.0.0.1:6443"},
+		}},
+		Users:    []NamedUser{{Name: "kind-kind"}},
+		Contexts: []NamedContext{{Name: "kind-kind", Context: Context{Cluster: "kind-kind", User: "kind-kind"}}},
+	}
+	cfg, err := KINDForUser(kindConfig, "kind", "alice", []byte("cert"), []byte("key"))
+	assert.ExpectError(t, false, err)
+	assert.DeepEqual(t, &Config{
+		Clusters: kindConfig.Clusters,
+		Users: []NamedUser{{
+			Name: "kind-kind@alice",
+			User: map[string]interface{}{
+				"client-certificate-data": "Y2VydA==",
+				"client-key-data":         "a2V5",
+			},
+		}},
+		Contexts: []NamedContext{{
+			Name:    "kind-kind@alice",
+			Context: Context{Cluster: "kind-kind", User: "kind-kind@alice"},
+		}},
+		CurrentContext: "kind-kind@alice",
+	}, cfg)
+
+	_, err = KINDForUser(&Config{}, "kind", "alice", nil, nil)
+	assert.ExpectError(t, true, err)
+}
@@ -18,6 +18,7 @@ package kubeconfig
 
 import (
 	"os"
+	"strings"
 
 	"sigs.k8s.io/kind/pkg/errors"
 )
@@ -27,6 +28,11 @@ import (
 func RemoveKIND(kindClusterName string, explicitPath string) error {
 	// remove kind from each if present
 	for _, configPath := range paths(explicitPath, os.Getenv) {
+		// avoid locking files without any kind entry to remove, the lock
+		// is contended when many clusters are created in parallel
+		if existing, err := read(configPath); err == nil && !remove(existing, kindClusterName) {
+			continue
+		}
 		if err := func(configPath string) error {
 			// lock before modifying
 			if err := lockFile(configPath); err != nil {
@@ -77,32 +83,40 @@ func remove(cfg *Config, kindClusterName string) bool {
 	}
 	cfg.Clusters = cfg.Clusters[:kept]
 
-	// filter out kind cluster from users
+	// filter out kind cluster from contexts, including the contexts of
+	// other users of the cluster (see KINDForUser)
+	removedContexts := map[string]bool{}
+	removedUsers := map[string]bool{key: true}
 	kept = 0
-	for _, u := range cfg.Users {
-		if u.Name != key {
-			cfg.Users[kept] = u
+	for _, c := range cfg.Contexts {
+		if c.Name != key && c.Context.Cluster != key {
+			cfg.Contexts[kept] = c
 			kept++
 		} else {
+			removedContexts[c.Name] = true
+			// only remove users kind created for this cluster
+			if strings.HasPrefix(c.Context.User, key+"@") {
+				removedUsers[c.Context.User] = true
+			}
 			mutated = true
 		}
 	}
-	cfg.Users = cfg.Users[:kept]
+	cfg.Contexts = cfg.Contexts[:kept]
 
-	// filter out kind cluster from contexts
+	// filter out kind cluster from users
 	kept = 0
-	for _, c := range cfg.Contexts {
-		if c.Name != key {
-			cfg.Contexts[kept] = c
+	for _, u := range cfg.Users {
+		if !removedUsers[u.Name] {
+			cfg.Users[kept] = u
 			kept++
 		} else {
 			mutated = true
 		}
 	}
-	cfg.Contexts = cfg.Contexts[:kept]
+	cfg.Users = cfg.Users[:kept]
 
 	// unset current context if it points to this cluster
-	if cfg.CurrentContext == key {
+	if removedContexts[cfg.CurrentContext] || cfg.CurrentContext == key {
 		cfg.CurrentContext = ""
 		mutated = true
 	}
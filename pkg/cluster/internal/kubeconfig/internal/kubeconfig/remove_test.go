@@ -124,6 +124,44 @@ func TestRemove(t *testing.T) {
 			},
 			ExpectModified: true,
 		},
+		{
+			Name: "remove kind users, leave kind-foo",
+			Existing: &Config{
+				Clusters: []NamedCluster{
+					{Name: "kind-kind"},
+					{Name: "kind-kind-foo"},
+				},
+				Users: []NamedUser{
+					{Name: "kind-kind"},
+					{Name: "kind-kind@alice"},
+					{Name: "kind-kind-foo"},
+					{Name: "kind-kind-foo@bob"},
+				},
+				Contexts: []NamedContext{
+					{Name: "kind-kind", Context: Context{Cluster: "kind-kind", User: "kind-kind"}},
+					{Name: "kind-kind@alice", Context: Context{Cluster: "kind-kind", User: "kind-kind@alice"}},
+					{Name: "kind-kind-foo", Context: Context{Cluster: "kind-kind-foo", User: "kind-kind-foo"}},
+					{Name: "kind-kind-foo@bob", Context: Context{Cluster: "kind-kind-foo", User: "kind-kind-foo@bob"}},
+				},
+				CurrentContext: "kind-kind@alice",
+			},
+			ClusterName: "kind",
+			Expected: &Config{
+				Clusters: []NamedCluster{
+					{Name: "kind-kind-foo"},
+				},
+				Users: []NamedUser{
+					{Name: "kind-kind-foo"},
+					{Name: "kind-kind-foo@bob"},
+				},
+				Contexts: []NamedContext{
+					{Name: "kind-kind-foo", Context: Context{Cluster: "kind-kind-foo", User: "kind-kind-foo"}},
+					{Name: "kind-kind-foo@bob", Context: Context{Cluster: "kind-kind-foo", User: "kind-kind-foo@bob"}},
+				},
+				CurrentContext: "",
+			},
+			ExpectModified: true,
+		},
 	}
 	for _, tc := range cases {
 		tc := tc
@@ -20,7 +20,9 @@ package kubeconfig
 
 import (
 	"bytes"
+	"os"
 
+	"sigs.k8s.io/kind/pkg/cluster/nodes"
 	"sigs.k8s.io/kind/pkg/cluster/nodeutils"
 	"sigs.k8s.io/kind/pkg/errors"
 
@@ -32,20 +34,37 @@ import (
 
 // Export exports the kubeconfig given the cluster context and a path to write it to
 // This will always be an external kubeconfig
-func Export(p providers.Provider, name, explicitPath string, external bool) error {
+// Without explicitPath the file is selected by strategy, see ResolvePath
+func Export(p providers.Provider, name, explicitPath string, external bool, strategy Strategy) error {
+	configPath, err := ResolvePath(name, explicitPath, strategy)
+	if err != nil {
+		return err
+	}
 	cfg, err := get(p, name, external)
 	if err != nil {
 		return err
 	}
-	return kubeconfig.WriteMerged(cfg, explicitPath)
+	return kubeconfig.WriteMerged(cfg, configPath)
 }
 
 // Remove removes clusterName from the kubeconfig paths detected based on
 // either explicitPath being set or $KUBECONFIG or $HOME/.kube/config, following
 // the rules set by kubectl
+// The cluster's isolated kubeconfig file is also removed, regardless of
+// explicitPath and the Strategy, as either may have changed since creation
 // clusterName must identify a kind cluster.
 func Remove(clusterName, explicitPath string) error {
-	return kubeconfig.RemoveKIND(clusterName, explicitPath)
+	if err := kubeconfig.RemoveKIND(clusterName, explicitPath); err != nil {
+		return err
+	}
+	isolatedPath, err := PathForCluster(clusterName)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(isolatedPath); err != nil && !os.IsNotExist(err) {
+		return errors.Wrap(err, "failed to remove cluster kubeconfig")
+	}
+	return nil
 }
 
 // Get returns the kubeconfig for the cluster
@@ -70,22 +89,13 @@ func ContextForCluster(kindClusterName string) string {
 
 func get(p providers.Provider, name string, external bool) (*kubeconfig.Config, error) {
 	// find a control plane node to get the kubeadm config from
-	n, err := p.ListNodes(name)
+	node, err := controlPlaneNode(p, name)
 	if err != nil {
 		return nil, err
 	}
-	var buff bytes.Buffer
-	nodes, err := nodeutils.ControlPlaneNodes(n)
-	if err != nil {
-		return nil, err
-	}
-	if len(nodes) < 1 {
-		return nil, errors.Errorf("could not locate any control plane nodes for cluster named '%s'. "+
-			"Use the --name option to select a different cluster", name)
-	}
-	node := nodes[0]
 
 	// grab kubeconfig version from the node
+	var buff bytes.Buffer
 	if err := node.Command("cat", "/etc/kubernetes/admin.conf").SetStdout(&buff).Run(); err != nil {
 		return nil, errors.Wrap(err, "failed to get cluster internal kubeconfig")
 	}
@@ -103,3 +113,20 @@ func get(p providers.Provider, name string, external bool) (*kubeconfig.Config,
 	// actually encode
 	return kubeconfig.KINDFromRawKubeadm(buff.String(), name, server)
 }
+
+// controlPlaneNode returns the bootstrap control plane node of the cluster
+func controlPlaneNode(p providers.Provider, name string) (nodes.Node, error) {
+	n, err := p.ListNodes(name)
+	if err != nil {
+		return nil, err
+	}
+	controlPlanes, err := nodeutils.ControlPlaneNodes(n)
+	if err != nil {
+		return nil, err
+	}
+	if len(controlPlanes) < 1 {
+		return nil, errors.Errorf("could not locate any control plane nodes for cluster named '%s'. "+
+			"Use the --name option to select a different cluster", name)
+	}
+	return controlPlanes[0], nil
+}
@@ -0,0 +1,48 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubeconfig
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"sigs.k8s.io/kind/pkg/internal/assert"
+
+	"sigs.k8s.io/kind/pkg/cluster/internal/state"
+)
+
+func TestRemove(t *testing.T) {
+	t.Setenv(state.DirEnv, t.TempDir())
+	explicitPath := filepath.Join(t.TempDir(), "config")
+	if err := os.WriteFile(explicitPath, []byte("apiVersion: v1\nkind: Config\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	for _, explicit := range []string{explicitPath, ""} {
+		isolatedPath, err := PathForCluster("foo")
+		assert.ExpectError(t, false, err)
+		if err := os.WriteFile(isolatedPath, []byte("apiVersion: v1\nkind: Config\n"), 0600); err != nil {
+			t.Fatal(err)
+		}
+		if explicit == "" {
+			t.Setenv("KUBECONFIG", explicitPath)
+		}
+		assert.ExpectError(t, false, Remove("foo", explicit))
+		_, err = os.Stat(isolatedPath)
+		assert.BoolEqual(t, true, os.IsNotExist(err))
+	}
+}
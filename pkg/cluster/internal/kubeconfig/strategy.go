@@ -0,0 +1,77 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubeconfig
+
+import (
+	"path/filepath"
+
+	"sigs.k8s.io/kind/pkg/cluster/internal/kubeconfig/internal/kubeconfig"
+	"sigs.k8s.io/kind/pkg/cluster/internal/providers"
+	"sigs.k8s.io/kind/pkg/cluster/internal/state"
+)
+
+// Strategy is how kind writes cluster kubeconfigs when no explicit
+// --kubeconfig path is given
+type Strategy string
+
+const (
+	// StrategyIsolated writes each cluster to its own file in the kind state
+	// directory, see PathForCluster. This is the default of the kind CLI.
+	StrategyIsolated Strategy = "isolated"
+	// StrategyMerged merges clusters into $KUBECONFIG or $HOME/.kube/config,
+	// following the rules set by kubectl. This is the default otherwise.
+	StrategyMerged Strategy = "merged"
+)
+
+// PathForCluster returns the path of the isolated kubeconfig file for the
+// cluster, creating its directory
+func PathForCluster(name string) (string, error) {
+	dir, err := state.Dir("kubeconfigs")
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, name+".kubeconfig"), nil
+}
+
+// ResolvePath returns the kubeconfig path to write the cluster to, given
+// the --kubeconfig value explicitPath and strategy, an empty strategy is
+// StrategyMerged.
+// An empty result means the kubectl rules for $KUBECONFIG and
+// $HOME/.kube/config apply.
+func ResolvePath(name, explicitPath string, strategy Strategy) (string, error) {
+	if explicitPath != "" {
+		return explicitPath, nil
+	}
+	if strategy != StrategyIsolated {
+		return "", nil
+	}
+	return PathForCluster(name)
+}
+
+// ExportIsolated exports the cluster's kubeconfig to its isolated file,
+// regardless of the current Strategy, returning the path, see PathForCluster
+func ExportIsolated(p providers.Provider, name string, external bool) (string, error) {
+	configPath, err := PathForCluster(name)
+	if err != nil {
+		return "", err
+	}
+	cfg, err := get(p, name, external)
+	if err != nil {
+		return "", err
+	}
+	return configPath, kubeconfig.WriteMerged(cfg, configPath)
+}
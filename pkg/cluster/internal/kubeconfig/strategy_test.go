@@ -0,0 +1,55 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubeconfig
+
+import (
+	"path/filepath"
+	"testing"
+
+	"sigs.k8s.io/kind/pkg/internal/assert"
+
+	"sigs.k8s.io/kind/pkg/cluster/internal/state"
+)
+
+func TestResolvePath(t *testing.T) {
+	stateDir := t.TempDir()
+	t.Setenv(state.DirEnv, stateDir)
+
+	// the library default
+	path, err := ResolvePath("foo", "", "")
+	assert.ExpectError(t, false, err)
+	assert.StringEqual(t, "", path)
+
+	path, err = ResolvePath("foo", "", StrategyMerged)
+	assert.ExpectError(t, false, err)
+	assert.StringEqual(t, "", path)
+
+	// the CLI default
+	path, err = ResolvePath("foo", "", StrategyIsolated)
+	assert.ExpectError(t, false, err)
+	assert.StringEqual(t, filepath.Join(stateDir, "kubeconfigs", "foo.kubeconfig"), path)
+
+	path, err = ResolvePath("foo", "/explicit/config", StrategyIsolated)
+	assert.ExpectError(t, false, err)
+	assert.StringEqual(t, "/explicit/config", path)
+
+	// the environment no longer applies to the library
+	t.Setenv("KIND_KUBECONFIG_STRATEGY", string(StrategyIsolated))
+	path, err = ResolvePath("foo", "", "")
+	assert.ExpectError(t, false, err)
+	assert.StringEqual(t, "", path)
+}
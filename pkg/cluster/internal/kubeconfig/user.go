@@ -0,0 +1,227 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubeconfig
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/pem"
+	"math/big"
+	"strings"
+	"time"
+
+	"sigs.k8s.io/kind/pkg/cluster/nodes"
+	"sigs.k8s.io/kind/pkg/errors"
+
+	"sigs.k8s.io/kind/pkg/cluster/internal/kubeconfig/internal/kubeconfig"
+	"sigs.k8s.io/kind/pkg/cluster/internal/providers"
+)
+
+const (
+	caCertPath = "/etc/kubernetes/pki/ca.crt"
+	caKeyPath  = "/etc/kubernetes/pki/ca.key"
+)
+
+// User describes a user authenticating with a client certificate signed by
+// the cluster CA
+type User struct {
+	// Name is the user name, the certificate common name
+	Name string
+	// Groups are the user's groups, the certificate organizations
+	Groups []string
+	// TTL is how long the certificate is valid for, it is limited by the
+	// expiry of the cluster CA
+	TTL time.Duration
+	// ClusterRole is the name of a ClusterRole to bind the user to, if set
+	ClusterRole string
+}
+
+// ExportUser exports a kubeconfig for user, merging it into the same file as
+// Export with the user's own context, see UserContextForCluster
+func ExportUser(p providers.Provider, name, explicitPath string, external bool, strategy Strategy, user *User) error {
+	configPath, err := ResolvePath(name, explicitPath, strategy)
+	if err != nil {
+		return err
+	}
+	if user.Name == "" {
+		return errors.New("a user name is required")
+	}
+	if user.TTL <= 0 {
+		return errors.Errorf("invalid certificate TTL %v", user.TTL)
+	}
+	cfg, err := get(p, name, external)
+	if err != nil {
+		return err
+	}
+	node, err := controlPlaneNode(p, name)
+	if err != nil {
+		return err
+	}
+	caCertPEM, err := readNodeFile(node, caCertPath)
+	if err != nil {
+		return err
+	}
+	caKeyPEM, err := readNodeFile(node, caKeyPath)
+	if err != nil {
+		return err
+	}
+	certPEM, keyPEM, err := signUserCert(caCertPEM, caKeyPEM, user, time.Now())
+	if err != nil {
+		return err
+	}
+	if user.ClusterRole != "" {
+		if err := bindClusterRole(node, user); err != nil {
+			return err
+		}
+	}
+	userCfg, err := kubeconfig.KINDForUser(cfg, name, user.Name, certPEM, keyPEM)
+	if err != nil {
+		return err
+	}
+	return kubeconfig.WriteMerged(userCfg, configPath)
+}
+
+// UserContextForCluster returns the context name for a user of a kind
+// cluster, see ExportUser
+func UserContextForCluster(kindClusterName, user string) string {
+	return kubeconfig.KINDUserKey(kindClusterName, user)
+}
+
+func readNodeFile(node nodes.Node, path string) ([]byte, error) {
+	var buff bytes.Buffer
+	if err := node.Command("cat", path).SetStdout(&buff).Run(); err != nil {
+		return nil, errors.Wrapf(err, "failed to read %s from node %q", path, node.String())
+	}
+	return buff.Bytes(), nil
+}
+
+// signUserCert returns a PEM encoded client certificate for user signed by
+// the CA, and its PEM encoded private key
+func signUserCert(caCertPEM, caKeyPEM []byte, user *User, now time.Time) (certPEM, keyPEM []byte, err error) {
+	// this handles both the RSA and ECDSA keys kubeadm may generate
+	ca, err := tls.X509KeyPair(caCertPEM, caKeyPEM)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to load cluster CA")
+	}
+	caCert, err := x509.ParseCertificate(ca.Certificate[0])
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to parse cluster CA certificate")
+	}
+	signer, ok := ca.PrivateKey.(crypto.Signer)
+	if !ok {
+		return nil, nil, errors.New("unsupported cluster CA key type")
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to generate user key")
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to generate certificate serial number")
+	}
+	notAfter := now.Add(user.TTL)
+	if notAfter.After(caCert.NotAfter) {
+		notAfter = caCert.NotAfter
+	}
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject: pkix.Name{
+			CommonName:   user.Name,
+			Organization: user.Groups,
+		},
+		// tolerate some clock skew between the host and the nodes
+		NotBefore:   now.Add(-5 * time.Minute),
+		NotAfter:    notAfter,
+		KeyUsage:    x509.KeyUsageDigitalSignature,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, caCert, key.Public(), signer)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to sign user certificate")
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to encode user key")
+	}
+	certPEM = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM = pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	return certPEM, keyPEM, nil
+}
+
+// bindClusterRole creates or updates a ClusterRoleBinding of the user to
+// user.ClusterRole using the admin kubeconfig on node
+func bindClusterRole(node nodes.Node, user *User) error {
+	bindingName := clusterRoleBindingName(user)
+	var manifest bytes.Buffer
+	if err := node.Command(
+		"kubectl", "--kubeconfig=/etc/kubernetes/admin.conf",
+		"create", "clusterrolebinding", bindingName,
+		"--clusterrole="+user.ClusterRole,
+		"--user="+user.Name,
+		"--dry-run=client", "-o", "yaml",
+	).SetStdout(&manifest).Run(); err != nil {
+		return errors.Wrapf(err, "failed to generate ClusterRoleBinding for user %q", user.Name)
+	}
+	// apply rather than create so that exporting again is idempotent
+	if err := node.Command(
+		"kubectl", "--kubeconfig=/etc/kubernetes/admin.conf", "apply", "-f", "-",
+	).SetStdin(&manifest).Run(); err != nil {
+		return errors.Wrapf(err, "failed to bind user %q to ClusterRole %q", user.Name, user.ClusterRole)
+	}
+	return nil
+}
+
+// maxBindingNameLength is the maximum length of a ClusterRoleBinding name,
+// that of a DNS subdomain
+const maxBindingNameLength = 253
+
+// clusterRoleBindingName returns a valid ClusterRoleBinding name for
+// binding user to user.ClusterRole.
+// User and role names such as "alice@example.com" or "system:basic-user" are
+// lowercased with other invalid characters replaced, and then get a hash of
+// the original names appended to keep distinct bindings distinct.
+func clusterRoleBindingName(user *User) string {
+	name := "kind-user-" + user.Name + "-" + user.ClusterRole
+	sanitized := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '-':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r - 'A' + 'a'
+		default:
+			return '-'
+		}
+	}, name)
+	if sanitized == name && len(name) <= maxBindingNameLength {
+		return name
+	}
+	sum := sha256.Sum256([]byte(user.Name + "\x00" + user.ClusterRole))
+	suffix := "-" + hex.EncodeToString(sum[:])[:10]
+	if len(sanitized) > maxBindingNameLength-len(suffix) {
+		sanitized = sanitized[:maxBindingNameLength-len(suffix)]
+	}
+	return strings.TrimRight(sanitized, "-") + suffix
+}
@@ -0,0 +1,206 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubeconfig
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"sigs.k8s.io/kind/pkg/internal/assert"
+)
+
+// newCA returns a PEM encoded self-signed CA certificate and key, like those
+// generated by kubeadm
+func newCA(t *testing.T, key crypto.Signer, keyPEM []byte, notAfter time.Time) (certPEM []byte, caKeyPEM []byte) {
+	t.Helper()
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "kubernetes"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              notAfter,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	if err != nil {
+		t.Fatalf("failed to create CA: %v", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), keyPEM
+}
+
+func TestSignUserCert(t *testing.T) {
+	t.Parallel()
+	now := time.Now()
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	rsaKeyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(rsaKey)})
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	ecKeyDER, err := x509.MarshalECPrivateKey(ecKey)
+	if err != nil {
+		t.Fatalf("failed to encode key: %v", err)
+	}
+	ecKeyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: ecKeyDER})
+
+	cases := []struct {
+		Name             string
+		Key              crypto.Signer
+		KeyPEM           []byte
+		CAExpiry         time.Time
+		ExpectedNotAfter time.Time
+	}{
+		{
+			Name:             "RSA CA",
+			Key:              rsaKey,
+			KeyPEM:           rsaKeyPEM,
+			CAExpiry:         now.Add(365 * 24 * time.Hour),
+			ExpectedNotAfter: now.Add(24 * time.Hour),
+		},
+		{
+			Name:     "ECDSA CA expiring before the TTL",
+			Key:      ecKey,
+			KeyPEM:   ecKeyPEM,
+			CAExpiry: now.Add(time.Hour),
+			// the CA expiry is truncated to seconds
+			ExpectedNotAfter: now.Add(time.Hour).Truncate(time.Second),
+		},
+	}
+	for _, tc := range cases {
+		tc := tc // capture range variable
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+			caCertPEM, caKeyPEM := newCA(t, tc.Key, tc.KeyPEM, tc.CAExpiry)
+			certPEM, keyPEM, err := signUserCert(caCertPEM, caKeyPEM, &User{
+				Name:   "alice",
+				Groups: []string{"dev", "qa"},
+				TTL:    24 * time.Hour,
+			}, now)
+			assert.ExpectError(t, false, err)
+
+			block, _ := pem.Decode(certPEM)
+			cert, err := x509.ParseCertificate(block.Bytes)
+			if err != nil {
+				t.Fatalf("failed to parse certificate: %v", err)
+			}
+			assert.StringEqual(t, "alice", cert.Subject.CommonName)
+			// the organizations are a DER set, which is not ordered
+			organizations := cert.Subject.Organization
+			sort.Strings(organizations)
+			assert.DeepEqual(t, []string{"dev", "qa"}, organizations)
+			assert.DeepEqual(t, []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}, cert.ExtKeyUsage)
+			assert.BoolEqual(t, true, cert.NotAfter.Equal(tc.ExpectedNotAfter.Truncate(time.Second)))
+
+			// the certificate must be verifiable with the CA
+			caBlock, _ := pem.Decode(caCertPEM)
+			caCert, err := x509.ParseCertificate(caBlock.Bytes)
+			if err != nil {
+				t.Fatalf("failed to parse CA certificate: %v", err)
+			}
+			roots := x509.NewCertPool()
+			roots.AddCert(caCert)
+			_, err = cert.Verify(x509.VerifyOptions{
+				Roots:       roots,
+				CurrentTime: now,
+				KeyUsages:   []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+			})
+			assert.ExpectError(t, false, err)
+
+			keyBlock, _ := pem.Decode(keyPEM)
+			_, err = x509.ParseECPrivateKey(keyBlock.Bytes)
+			assert.ExpectError(t, false, err)
+		})
+	}
+
+	_, _, err = signUserCert([]byte("bogus"), rsaKeyPEM, &User{Name: "alice", TTL: time.Hour}, now)
+	assert.ExpectError(t, true, err)
+}
+
+func TestClusterRoleBindingName(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name string
+		user User
+		want string
+	}{
+		{
+			name: "valid names are kept",
+			user: User{Name: "alice", ClusterRole: "view"},
+			want: "kind-user-alice-view",
+		},
+		{
+			name: "email user",
+			user: User{Name: "alice@example.com", ClusterRole: "view"},
+			want: "kind-user-alice-example-com-view-",
+		},
+		{
+			name: "uppercase user",
+			user: User{Name: "Alice", ClusterRole: "view"},
+			want: "kind-user-alice-view-",
+		},
+		{
+			name: "system role",
+			user: User{Name: "alice", ClusterRole: "system:basic-user"},
+			want: "kind-user-alice-system-basic-user-",
+		},
+	}
+	for _, tc := range cases {
+		tc := tc // capture range variable
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			got := clusterRoleBindingName(&tc.user)
+			if !strings.HasPrefix(got, tc.want) {
+				t.Errorf("expected name with prefix %q, got %q", tc.want, got)
+			}
+			if !validBindingName.MatchString(got) {
+				t.Errorf("invalid binding name %q", got)
+			}
+		})
+	}
+
+	// names that sanitize to the same string must not share a binding
+	a := clusterRoleBindingName(&User{Name: "Alice", ClusterRole: "view"})
+	b := clusterRoleBindingName(&User{Name: "alice!", ClusterRole: "view"})
+	if a == b {
+		t.Errorf("expected distinct binding names, got %q twice", a)
+	}
+
+	long := clusterRoleBindingName(&User{Name: strings.Repeat("A", 300), ClusterRole: "view"})
+	if len(long) > maxBindingNameLength || !validBindingName.MatchString(long) {
+		t.Errorf("invalid binding name %q", long)
+	}
+}
+
+// validBindingName matches the DNS labels binding names are made of
+var validBindingName = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
@@ -20,8 +20,11 @@ import (
 	"bytes"
 	"fmt"
 	"net"
+	"strings"
 	"text/template"
 
+	"sigs.k8s.io/kind/pkg/cluster/nodes"
+	"sigs.k8s.io/kind/pkg/cluster/nodeutils"
 	"sigs.k8s.io/kind/pkg/errors"
 )
 
@@ -133,6 +136,72 @@ resources:
 {{- end }}
 `
 
+// PortForwardConfigData is supplied to the port forwarder config templates
+type PortForwardConfigData struct {
+	// Port is both the port listened on and the port forwarded to on Target
+	Port   int32
+	Target string
+	UDP    bool
+	IPv6   bool
+}
+
+// PortForwardLDSConfigTemplate is the port forwarder config template for
+// listeners
+const PortForwardLDSConfigTemplate = `
+resources:
+- "@type": type.googleapis.com/envoy.config.listener.v3.Listener
+  name: listener_forward
+  address:
+    socket_address:
+{{- if .UDP }}
+      protocol: UDP
+{{- end }}
+      address: {{ if .IPv6 }}"::"{{ else }}"0.0.0.0"{{ end }}
+      port_value: {{ .Port }}
+{{- if .UDP }}
+  listener_filters:
+  - name: envoy.filters.udp_listener.udp_proxy
+    typed_config:
+      "@type": type.googleapis.com/envoy.extensions.filters.udp.udp_proxy.v3.UdpProxyConfig
+      stat_prefix: forward_udp
+      matcher:
+        on_no_match:
+          action:
+            name: route
+            typed_config:
+              "@type": type.googleapis.com/envoy.extensions.filters.udp.udp_proxy.v3.Route
+              cluster: forward_target
+{{- else }}
+  filter_chains:
+  - filters:
+    - name: envoy.filters.network.tcp_proxy
+      typed_config:
+        "@type": type.googleapis.com/envoy.extensions.filters.network.tcp_proxy.v3.TcpProxy
+        stat_prefix: forward_tcp
+        cluster: forward_target
+{{- end }}
+`
+
+// PortForwardCDSConfigTemplate is the port forwarder config template for
+// clusters
+const PortForwardCDSConfigTemplate = `
+resources:
+- "@type": type.googleapis.com/envoy.config.cluster.v3.Cluster
+  name: forward_target
+  connect_timeout: 0.25s
+  type: STRICT_DNS
+  dns_lookup_family: {{ if .IPv6 -}} AUTO {{- else -}} V4_PREFERRED {{- end }}
+  load_assignment:
+    cluster_name: forward_target
+    endpoints:
+    - lb_endpoints:
+      - endpoint:
+          address:
+            socket_address:
+              address: {{ .Target }}
+              port_value: {{ .Port }}
+`
+
 func hostPort(addr string) (map[string]string, error) {
 	host, port, err := net.SplitHostPort(addr)
 	if err != nil {
@@ -143,7 +212,9 @@ func hostPort(addr string) (map[string]string, error) {
 
 // Config returns a kubeadm config generated from config data, in particular
 // the kubernetes version
-func Config(data *ConfigData, configTemplate string) (config string, err error) {
+//
+// data is a *ConfigData or a *PortForwardConfigData matching configTemplate
+func Config(data interface{}, configTemplate string) (config string, err error) {
 	funcs := template.FuncMap{
 		"hostPort": hostPort,
 	}
@@ -161,7 +232,41 @@ func Config(data *ConfigData, configTemplate string) (config string, err error)
 	return buff.String(), nil
 }
 
+// WriteDynamicConfig atomically replaces the listener and cluster configs of
+// the Envoy node n, which then reloads them
+func WriteDynamicConfig(n nodes.Node, ldsConfig, cdsConfig string) error {
+	tmpLDS := ProxyConfigPathLDS + ".tmp"
+	tmpCDS := ProxyConfigPathCDS + ".tmp"
+
+	if err := nodeutils.WriteFile(n, tmpLDS, ldsConfig); err != nil {
+		return errors.Wrap(err, "failed to copy loadbalancer config to node")
+	}
+	if err := nodeutils.WriteFile(n, tmpCDS, cdsConfig); err != nil {
+		return errors.Wrap(err, "failed to copy loadbalancer config to node")
+	}
+	cmd := fmt.Sprintf("chmod 666 %s %s && mv %s %s && mv %s %s", tmpCDS, tmpLDS, tmpCDS, ProxyConfigPathCDS, tmpLDS, ProxyConfigPathLDS)
+	if err := n.Command("sh", "-c", cmd).Run(); err != nil {
+		return errors.Wrap(err, "failed to reload envoy config")
+	}
+	return nil
+}
+
+// GenerateBootstrapCommand returns the command for an Envoy node that starts
+// with empty listener and cluster configs, to be written later by kind
 func GenerateBootstrapCommand(clusterName, containerName string) []string {
+	// Create dynamic Envoy config files with valid empty resources
+	emptyConfig := "resources: []"
+	return generateBootstrapCommand(clusterName, containerName, emptyConfig, emptyConfig)
+}
+
+// GenerateForwarderBootstrapCommand returns the command for an Envoy node
+// that starts with the given listener and cluster configs.
+// The configs are part of the command so that they survive restarts.
+func GenerateForwarderBootstrapCommand(clusterName, containerName, ldsConfig, cdsConfig string) []string {
+	return generateBootstrapCommand(clusterName, containerName, ldsConfig, cdsConfig)
+}
+
+func generateBootstrapCommand(clusterName, containerName, ldsConfig, cdsConfig string) []string {
 	// populate the values to dynamic template
 	envoyConfig := fmt.Sprintf(
 		DynamicFilesystemConfigTemplate,
@@ -173,16 +278,18 @@ func GenerateBootstrapCommand(clusterName, containerName string) []string {
 
 	// Create dynamic Envoy config files and start Envoy with retry,
 	// since it has an initialization phase before forwarding traffic.
-	// cmd := []string{"bash", "-c",
-	// 	fmt.Sprintf(`mkdir -p %s && echo -en '%s' > %s && touch %s && touch %s && while true; do envoy -c %s && break; sleep 1; done`, constants.ProxyConfigDir,
-	// 		envoyConfig, constants.ProxyConfigPath, constants.ProxyConfigPathCDS, constants.ProxyConfigPathLDS, constants.ProxyConfigPath)}
-	// Create dynamic Envoy config files with valid empty resources
-	emptyConfig := "resources: []"
 	return []string{"bash", "-c",
 		fmt.Sprintf(`mkdir -p %s && echo -en '%s' > %s && echo -en '%s' > %s && echo -en '%s' > %s && while true; do envoy -c %s && break; sleep 1; done`,
 			ProxyConfigDir,
 			envoyConfig, ProxyConfigPath,
-			emptyConfig, ProxyConfigPathCDS, // Initialize CDS
-			emptyConfig, ProxyConfigPathLDS, // Initialize LDS
+			quoteConfig(cdsConfig), ProxyConfigPathCDS, // Initialize CDS
+			quoteConfig(ldsConfig), ProxyConfigPathLDS, // Initialize LDS
 			ProxyConfigPath)}
 }
+
+// quoteConfig escapes config for use within single quotes as an argument of
+// echo -e
+func quoteConfig(config string) string {
+	config = strings.ReplaceAll(config, `\`, `\\`)
+	return strings.ReplaceAll(config, `'`, `'\''`)
+}
@@ -26,7 +26,7 @@ func TestConfig(t *testing.T) {
 
 	tests := []struct {
 		name            string
-		data            *ConfigData
+		data            interface{}
 		configTemplate  string
 		wantConfig      string
 		wantErrContains string
@@ -177,6 +177,61 @@ func TestConfig(t *testing.T) {
 			configTemplate:  ProxyCDSConfigTemplate,
 			wantErrContains: "error executing config template",
 		},
+		{
+			name: "renders port forward lds template for udp",
+			data: &PortForwardConfigData{
+				Port:   53,
+				Target: "kind-worker",
+				UDP:    true,
+			},
+			configTemplate: PortForwardLDSConfigTemplate,
+			wantConfig: "\n" +
+				"resources:\n" +
+				"- \"@type\": type.googleapis.com/envoy.config.listener.v3.Listener\n" +
+				"  name: listener_forward\n" +
+				"  address:\n" +
+				"    socket_address:\n" +
+				"      protocol: UDP\n" +
+				"      address: \"0.0.0.0\"\n" +
+				"      port_value: 53\n" +
+				"  listener_filters:\n" +
+				"  - name: envoy.filters.udp_listener.udp_proxy\n" +
+				"    typed_config:\n" +
+				"      \"@type\": type.googleapis.com/envoy.extensions.filters.udp.udp_proxy.v3.UdpProxyConfig\n" +
+				"      stat_prefix: forward_udp\n" +
+				"      matcher:\n" +
+				"        on_no_match:\n" +
+				"          action:\n" +
+				"            name: route\n" +
+				"            typed_config:\n" +
+				"              \"@type\": type.googleapis.com/envoy.extensions.filters.udp.udp_proxy.v3.Route\n" +
+				"              cluster: forward_target\n",
+		},
+		{
+			name: "renders port forward cds template",
+			data: &PortForwardConfigData{
+				Port:   80,
+				Target: "kind-worker",
+				IPv6:   true,
+			},
+			configTemplate: PortForwardCDSConfigTemplate,
+			wantConfig: "\n" +
+				"resources:\n" +
+				"- \"@type\": type.googleapis.com/envoy.config.cluster.v3.Cluster\n" +
+				"  name: forward_target\n" +
+				"  connect_timeout: 0.25s\n" +
+				"  type: STRICT_DNS\n" +
+				"  dns_lookup_family: AUTO\n" +
+				"  load_assignment:\n" +
+				"    cluster_name: forward_target\n" +
+				"    endpoints:\n" +
+				"    - lb_endpoints:\n" +
+				"      - endpoint:\n" +
+				"          address:\n" +
+				"            socket_address:\n" +
+				"              address: kind-worker\n" +
+				"              port_value: 80\n",
+		},
 	}
 
 	for _, tt := range tests {
@@ -206,3 +261,20 @@ func TestConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestGenerateForwarderBootstrapCommand(t *testing.T) {
+	t.Parallel()
+
+	cmd := GenerateForwarderBootstrapCommand("kind", "kind-expose", "lds: 'a'", `cds: \n`)
+	if len(cmd) != 3 || cmd[0] != "bash" || cmd[1] != "-c" {
+		t.Fatalf("unexpected command: %v", cmd)
+	}
+	for _, want := range []string{
+		`echo -en 'lds: '\''a'\''' > ` + ProxyConfigPathLDS,
+		`echo -en 'cds: \\n' > ` + ProxyConfigPathCDS,
+	} {
+		if !strings.Contains(cmd[2], want) {
+			t.Errorf("expected command to contain %q, got: %s", want, cmd[2])
+		}
+	}
+}
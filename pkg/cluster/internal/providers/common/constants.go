@@ -19,3 +19,22 @@ package common
 // APIServerInternalPort defines the port where the control plane is listening
 // _inside_ the node network
 const APIServerInternalPort = 6443
+
+// ClusterLabelKey is applied to each "node" container for identification
+const ClusterLabelKey = "io.x-k8s.kind.cluster"
+
+// NodeRoleLabelKey is applied to each "node" container for categorization
+// of nodes by role
+const NodeRoleLabelKey = "io.x-k8s.kind.role"
+
+// NetworkLabelKey is applied to each "node" container to record the primary
+// network it was created on, nodes may be attached to extra networks
+const NetworkLabelKey = "io.x-k8s.kind.network"
+
+// PodSubnetLabelKey and ServiceSubnetLabelKey are applied to each "node"
+// container of multi-cluster clusters to record the allocated subnets, so they
+// are known to other clusters as soon as the nodes exist
+const (
+	PodSubnetLabelKey     = "io.x-k8s.kind.pod-subnet"
+	ServiceSubnetLabelKey = "io.x-k8s.kind.service-subnet"
+)
@@ -0,0 +1,121 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"sigs.k8s.io/kind/pkg/errors"
+
+	"sigs.k8s.io/kind/pkg/cluster/internal/providers"
+)
+
+// NodeContainerInspectFormat returns the `inspect --format` template used
+// with ParseNodeContainers, imageField is the field holding the container's
+// image name, E.G. ".Config.Image" for docker
+func NodeContainerInspectFormat(imageField string) string {
+	return fmt.Sprintf(`{{ .Name }}{{ "\t" }}{{ %s }}{{ "\t" }}{{ .State.Status }}{{ "\t" }}{{ json .NetworkSettings.Ports }}{{ "\t" }}{{ json .Config.Labels }}`, imageField)
+}
+
+// ParseNodeContainers parses the NodeContainerInspectFormat output lines,
+// returning the containers in the order of names
+func ParseNodeContainers(lines []string, names []string) ([]providers.NodeContainer, error) {
+	byName := map[string]providers.NodeContainer{}
+	for _, line := range lines {
+		parts := strings.Split(line, "\t")
+		if len(parts) != 5 {
+			return nil, errors.Errorf("invalid container details: %q", line)
+		}
+		ports, err := parsePorts(parts[3])
+		if err != nil {
+			return nil, err
+		}
+		labels := map[string]string{}
+		if parts[4] != "" && parts[4] != "null" {
+			if err := json.Unmarshal([]byte(parts[4]), &labels); err != nil {
+				return nil, errors.Wrap(err, "failed to decode container labels")
+			}
+		}
+		// docker prefixes the name with a slash
+		byName[strings.TrimPrefix(parts[0], "/")] = providers.NodeContainer{
+			Image:  parts[1],
+			State:  parts[2],
+			Ports:  ports,
+			Labels: labels,
+		}
+	}
+	containers := make([]providers.NodeContainer, 0, len(names))
+	for _, name := range names {
+		container, exists := byName[name]
+		if !exists {
+			return nil, errors.Errorf("no container details for node %q", name)
+		}
+		containers = append(containers, container)
+	}
+	return containers, nil
+}
+
+// parsePorts parses the JSON encoded .NetworkSettings.Ports of a container
+func parsePorts(raw string) ([]providers.PortMapping, error) {
+	bindings := map[string][]struct {
+		HostIP   string `json:"HostIp"`
+		HostPort string `json:"HostPort"`
+	}{}
+	if raw != "" && raw != "null" {
+		if err := json.Unmarshal([]byte(raw), &bindings); err != nil {
+			return nil, errors.Wrap(err, "failed to decode container ports")
+		}
+	}
+	ports := []providers.PortMapping{}
+	for containerPort, hostBindings := range bindings {
+		parts := strings.SplitN(containerPort, "/", 2)
+		port, err := strconv.ParseInt(parts[0], 10, 32)
+		if err != nil {
+			return nil, errors.Errorf("invalid container port %q", containerPort)
+		}
+		protocol := "tcp"
+		if len(parts) == 2 {
+			protocol = strings.ToLower(parts[1])
+		}
+		for _, binding := range hostBindings {
+			hostPort, err := strconv.ParseInt(binding.HostPort, 10, 32)
+			if err != nil {
+				return nil, errors.Errorf("invalid host port %q", binding.HostPort)
+			}
+			ports = append(ports, providers.PortMapping{
+				HostIP:        binding.HostIP,
+				HostPort:      int32(hostPort),
+				ContainerPort: int32(port),
+				Protocol:      protocol,
+			})
+		}
+	}
+	sort.Slice(ports, func(i, j int) bool {
+		if ports[i].ContainerPort != ports[j].ContainerPort {
+			return ports[i].ContainerPort < ports[j].ContainerPort
+		}
+		if ports[i].Protocol != ports[j].Protocol {
+			return ports[i].Protocol < ports[j].Protocol
+		}
+		return ports[i].HostIP < ports[j].HostIP
+	})
+	return ports, nil
+}
@@ -0,0 +1,58 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"testing"
+
+	"sigs.k8s.io/kind/pkg/cluster/internal/providers"
+	"sigs.k8s.io/kind/pkg/internal/assert"
+)
+
+func TestParseNodeContainers(t *testing.T) {
+	t.Parallel()
+	lines := []string{
+		"/kind-worker\tkindest/node:v1.31.0\texited\t{}\tnull",
+		`/kind-control-plane` + "\tkindest/node:v1.31.0\trunning\t" + `{"6443/tcp":[{"HostIp":"127.0.0.1","HostPort":"34567"}],"80/TCP":[{"HostIp":"0.0.0.0","HostPort":"8080"},{"HostIp":"::","HostPort":"8080"}],"53/udp":null}` + "\t" + `{"io.x-k8s.kind.role":"control-plane"}`,
+	}
+	containers, err := ParseNodeContainers(lines, []string{"kind-control-plane", "kind-worker"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.DeepEqual(t, []providers.NodeContainer{
+		{
+			Image: "kindest/node:v1.31.0",
+			State: "running",
+			Ports: []providers.PortMapping{
+				{HostIP: "0.0.0.0", HostPort: 8080, ContainerPort: 80, Protocol: "tcp"},
+				{HostIP: "::", HostPort: 8080, ContainerPort: 80, Protocol: "tcp"},
+				{HostIP: "127.0.0.1", HostPort: 34567, ContainerPort: 6443, Protocol: "tcp"},
+			},
+			Labels: map[string]string{"io.x-k8s.kind.role": "control-plane"},
+		},
+		{
+			Image:  "kindest/node:v1.31.0",
+			State:  "exited",
+			Ports:  []providers.PortMapping{},
+			Labels: map[string]string{},
+		},
+	}, containers)
+
+	if _, err := ParseNodeContainers(lines, []string{"kind-missing"}); err == nil {
+		t.Errorf("expected error for missing node")
+	}
+}
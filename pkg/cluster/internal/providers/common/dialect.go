@@ -0,0 +1,78 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+// Dialect declares how a docker CLI compatible binary differs from the
+// docker CLI, for the small subset of behavior that kind depends on
+type Dialect struct {
+	// Name identifies the dialect, it is used for the node providerID
+	Name string
+	// LabelFilterSeparator separates the label key and value in
+	// `ps --filter label=<key><separator><value>`, docker uses "="
+	LabelFilterSeparator string
+	// NetworkCreateArgs are additional arguments to `network create`
+	NetworkCreateArgs []string
+	// NetworkMTUOption is the network driver option used to set the MTU
+	// when creating the network, if empty the MTU is not set
+	NetworkMTUOption string
+	// NetworkEnv is an environment variable overriding the default network
+	// name, if any
+	NetworkEnv string
+	// RemoveDuplicateNetworks should be true if networks are not unique by
+	// name, duplicates created by concurrent creates are then removed
+	RemoveDuplicateNetworks bool
+	// SupportsCgroupNS should be true if `run --cgroupns=private` is supported
+	SupportsCgroupNS bool
+	// SerialCreate should be true if node containers must not be created
+	// concurrently
+	SerialCreate bool
+	// SupportsNetworkConnect should be true if `network connect` is supported,
+	// otherwise extra node networks are attached when creating the container
+	SupportsNetworkConnect bool
+	// ContainerImageField is the `inspect` template field holding the image
+	// name of a container, docker uses ".Config.Image" which is the default
+	ContainerImageField string
+	// StopBeforeDelete should be true if containers must have their restart
+	// policy cleared and be stopped before `rm -f` reliably removes them
+	StopBeforeDelete bool
+	// HostStorageQuirks should be true if `info` reports docker's security
+	// options and storage driver, nodes then run with --userns=host when
+	// user namespaces are remapped and mount /dev/mapper on btrfs, zfs or
+	// devicemapper storage
+	HostStorageQuirks bool
+}
+
+// ImageField returns ContainerImageField or the docker default
+func (d *Dialect) ImageField() string {
+	if d.ContainerImageField == "" {
+		return ".Config.Image"
+	}
+	return d.ContainerImageField
+}
+
+// LabelFilter returns a `ps --filter` value matching containers with the
+// label key, and with value if value is non-empty
+func (d *Dialect) LabelFilter(key, value string) string {
+	if value == "" {
+		return "label=" + key
+	}
+	sep := d.LabelFilterSeparator
+	if sep == "" {
+		sep = "="
+	}
+	return "label=" + key + sep + value
+}
@@ -0,0 +1,60 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"testing"
+)
+
+func TestDialectLabelFilter(t *testing.T) {
+	cases := []struct {
+		name      string
+		separator string
+		key       string
+		value     string
+		expected  string
+	}{
+		{
+			name:     "key only",
+			key:      "io.x-k8s.kind.cluster",
+			expected: "label=io.x-k8s.kind.cluster",
+		},
+		{
+			name:     "default separator",
+			key:      "io.x-k8s.kind.cluster",
+			value:    "kind",
+			expected: "label=io.x-k8s.kind.cluster=kind",
+		},
+		{
+			name:      "custom separator",
+			separator: ":",
+			key:       "io.x-k8s.kind.cluster",
+			value:     "kind",
+			expected:  "label=io.x-k8s.kind.cluster:kind",
+		},
+	}
+	for _, tc := range cases {
+		tc := tc // capture range variable
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			d := Dialect{LabelFilterSeparator: tc.separator}
+			if got := d.LabelFilter(tc.key, tc.value); got != tc.expected {
+				t.Errorf("expected %q but got %q", tc.expected, got)
+			}
+		})
+	}
+}
@@ -1,5 +1,5 @@
 /*
-Copyright 2020 The Kubernetes Authors.
+Copyright The Kubernetes Authors.
 
 Licensed under the Apache License, Version 2.0 (the "License");
 you may not use this file except in compliance with the License.
@@ -14,7 +14,7 @@ See the License for the specific language governing permissions and
 limitations under the License.
 */
 
-package docker
+package common
 
 import (
 	"bytes"
@@ -31,43 +31,53 @@ import (
 
 	"sigs.k8s.io/kind/pkg/errors"
 	"sigs.k8s.io/kind/pkg/exec"
+
+	"sigs.k8s.io/kind/pkg/internal/apis/config"
 )
 
-// This may be overridden by KIND_EXPERIMENTAL_DOCKER_NETWORK env,
-// experimentally...
-//
-// By default currently picking a single network is equivalent to the previous
-// behavior *except* that we moved from the default bridge to a user defined
-// network because the default bridge is actually special versus any other
-// docker network and lacks the embedded DNS
-//
-// For now this also makes it easier for apps to join the same network, and
-// leaves users with complex networking desires to create and manage their own
-// networks.
-const fixedNetworkName = "kind"
-
-// ensureNetwork checks if docker network by name exists, if not it creates it
-func ensureNetwork(name string) error {
+// EnsureNetwork checks if the network by name exists, if not it creates it
+// with the requested settings, if it does exist its settings are validated
+// against the requested settings
+func EnsureNetwork(binaryName string, dialect *Dialect, name string, requested *config.ContainerNetwork, requireIPv6 bool) error {
+	n := &networkCreator{binaryName: binaryName, dialect: dialect, name: name, requested: requested}
+
 	// check if network exists already and remove any duplicate networks
-	exists, err := removeDuplicateNetworks(name)
+	exists, err := n.exists(true)
 	if err != nil {
 		return err
 	}
 
-	// network already exists, we're good
-	// TODO: the network might already exist and not have ipv6 ... :|
-	// discussion: https://github.com/kubernetes-sigs/kind/pull/1508#discussion_r414594198
+	// network already exists, we're good if it matches
 	if exists {
-		return nil
+		return n.validate(requireIPv6)
+	}
+
+	// Use the requested MTU or else the MTU configured for the default network
+	mtu := int(requested.MTU)
+	if mtu == 0 {
+		mtu = n.defaultMTU()
+	}
+
+	// an explicitly requested IPv6 subnet gets exactly one attempt
+	if requested.IPv6Subnet != "" {
+		err := n.create(requested.IPv6Subnet, mtu)
+		if isPoolOverlapError(err) {
+			// perhaps another process created the network
+			exists, existsErr := n.exists(false)
+			if existsErr != nil {
+				return existsErr
+			}
+			if exists {
+				return n.validate(requireIPv6)
+			}
+		}
+		return err
 	}
 
 	// Generate unique subnet per network based on the name
 	// obtained from the ULA fc00::/8 range
-	// Use the MTU configured for the docker default network
 	// Make N attempts with "probing" in case we happen to collide
-	subnet := generateULASubnetFromName(name, 0)
-	mtu := getDefaultNetworkMTU()
-	err = createNetworkNoDuplicates(name, subnet, mtu)
+	err = n.create(GenerateULASubnetFromName(name, 0), mtu)
 	if err == nil {
 		// Success!
 		return nil
@@ -79,91 +89,118 @@ func ensureNetwork(name string) error {
 	// If it is, make more attempts below
 	if isIPv6UnavailableError(err) {
 		// only one attempt, IPAM is automatic in ipv4 only
-		return createNetworkNoDuplicates(name, "", mtu)
+		return n.create("", mtu)
 	}
-	if isPoolOverlapError(err) {
-		// pool overlap suggests perhaps another process created the network
-		// check if network exists already and remove any duplicate networks
-		exists, err := checkIfNetworkExists(name)
-		if err != nil {
-			return err
-		}
-		if exists {
-			return nil
-		}
-		// otherwise we'll start trying with different subnets
-	} else {
+	if !isPoolOverlapError(err) {
 		// unknown error ...
 		return err
 	}
 
 	// keep trying for ipv6 subnets
 	const maxAttempts = 5
-	for attempt := int32(1); attempt < maxAttempts; attempt++ {
-		subnet := generateULASubnetFromName(name, attempt)
-		err = createNetworkNoDuplicates(name, subnet, mtu)
+	for attempt := int32(1); attempt <= maxAttempts; attempt++ {
+		// pool overlap suggests perhaps another process created the network
+		exists, err := n.exists(false)
+		if err != nil {
+			return err
+		}
+		if exists {
+			return n.validate(requireIPv6)
+		}
+		if attempt == maxAttempts {
+			break
+		}
+		// otherwise we'll try again with a different subnet
+		err = n.create(GenerateULASubnetFromName(name, attempt), mtu)
 		if err == nil {
 			// success!
 			return nil
 		}
-		if isPoolOverlapError(err) {
-			// pool overlap suggests perhaps another process created the network
-			// check if network exists already and remove any duplicate networks
-			exists, err := checkIfNetworkExists(name)
-			if err != nil {
-				return err
-			}
-			if exists {
-				return nil
-			}
-			// otherwise we'll try again
-			continue
+		if !isPoolOverlapError(err) {
+			// unknown error ...
+			return err
 		}
-		// unknown error ...
-		return err
 	}
 	return errors.New("exhausted attempts trying to find a non-overlapping subnet")
 }
 
-func createNetworkNoDuplicates(name, ipv6Subnet string, mtu int) error {
-	if err := createNetwork(name, ipv6Subnet, mtu); err != nil && !isNetworkAlreadyExistsError(err) {
-		return err
+// GetNetworkSettings returns the settings of the existing network by name
+func GetNetworkSettings(binaryName string, dialect *Dialect, name string) (*NetworkSettings, error) {
+	out, err := exec.Output(exec.Command(binaryName, "network", "inspect", name))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to inspect network %q", name)
 	}
-	_, err := removeDuplicateNetworks(name)
-	return err
+	return ParseDockerNetworkInspect(out, dialect.NetworkMTUOption)
 }
 
-func removeDuplicateNetworks(name string) (bool, error) {
-	networks, err := sortedNetworksWithName(name)
+// networkCreator creates the network name for EnsureNetwork
+type networkCreator struct {
+	binaryName string
+	dialect    *Dialect
+	name       string
+	requested  *config.ContainerNetwork
+}
+
+// validate checks that the existing network is compatible with the
+// requested settings
+func (n *networkCreator) validate(requireIPv6 bool) error {
+	existing, err := GetNetworkSettings(n.binaryName, n.dialect, n.name)
 	if err != nil {
-		return false, err
+		return err
 	}
-	if len(networks) > 1 {
-		if err := deleteNetworks(networks[1:]...); err != nil && !isOnlyErrorNoSuchNetwork(err) {
-			return false, err
+	return ValidateExistingNetwork(n.name, n.requested, existing, requireIPv6)
+}
+
+// exists checks if the network exists, removing duplicates first if
+// removeDuplicates is set and the dialect does not have unique names
+func (n *networkCreator) exists(removeDuplicates bool) (bool, error) {
+	if !n.dialect.RemoveDuplicateNetworks {
+		out, err := exec.Output(exec.Command(
+			n.binaryName, "network", "inspect",
+			n.name, "--format={{.Name}}",
+		))
+		if err != nil {
+			return false, nil
 		}
+		return strings.HasPrefix(string(out), n.name), nil
 	}
-	return len(networks) > 0, nil
+	if removeDuplicates {
+		return n.removeDuplicates()
+	}
+	out, err := exec.Output(exec.Command(
+		n.binaryName, "network", "ls",
+		"--filter=name=^"+regexp.QuoteMeta(n.name)+"$",
+		"--format={{.Name}}",
+	))
+	return strings.HasPrefix(string(out), n.name), err
 }
 
-func createNetwork(name, ipv6Subnet string, mtu int) error {
-	args := []string{"network", "create", "-d=bridge",
-		"-o", "com.docker.network.bridge.enable_ip_masquerade=true",
+func (n *networkCreator) create(ipv6Subnet string, mtu int) error {
+	args := []string{"network", "create", "-d=bridge"}
+	args = append(args, n.dialect.NetworkCreateArgs...)
+	if mtu > 0 && n.dialect.NetworkMTUOption != "" {
+		args = append(args, "-o", fmt.Sprintf("%s=%d", n.dialect.NetworkMTUOption, mtu))
 	}
-	if mtu > 0 {
-		args = append(args, "-o", fmt.Sprintf("com.docker.network.driver.mtu=%d", mtu))
+	args = append(args, NetworkSubnetArgs(n.requested, ipv6Subnet)...)
+	args = append(args, n.name)
+	err := exec.Command(n.binaryName, args...).Run()
+	if !n.dialect.RemoveDuplicateNetworks {
+		return err
 	}
-	if ipv6Subnet != "" {
-		args = append(args, "--ipv6", "--subnet", ipv6Subnet)
+	if err != nil && !isNetworkAlreadyExistsError(err) {
+		return err
 	}
-	args = append(args, name)
-	return exec.Command("docker", args...).Run()
+	_, err = n.removeDuplicates()
+	return err
 }
 
-// getDefaultNetworkMTU obtains the MTU from the docker default network
-func getDefaultNetworkMTU() int {
-	cmd := exec.Command("docker", "network", "inspect", "bridge",
-		"-f", `{{ index .Options "com.docker.network.driver.mtu" }}`)
+// defaultMTU obtains the MTU from the default bridge network
+func (n *networkCreator) defaultMTU() int {
+	if n.dialect.NetworkMTUOption == "" {
+		return 0
+	}
+	cmd := exec.Command(n.binaryName, "network", "inspect", "bridge",
+		"-f", fmt.Sprintf(`{{ index .Options %q }}`, n.dialect.NetworkMTUOption))
 	lines, err := exec.OutputLines(cmd)
 	if err != nil || len(lines) != 1 {
 		return 0
@@ -175,9 +212,23 @@ func getDefaultNetworkMTU() int {
 	return mtu
 }
 
-func sortedNetworksWithName(name string) ([]string, error) {
+func (n *networkCreator) removeDuplicates() (bool, error) {
+	networks, err := n.sortedNetworksWithName()
+	if err != nil {
+		return false, err
+	}
+	if len(networks) > 1 {
+		args := append([]string{"network", "rm"}, networks[1:]...)
+		if err := exec.Command(n.binaryName, args...).Run(); err != nil && !isOnlyErrorNoSuchNetwork(err) {
+			return false, err
+		}
+	}
+	return len(networks) > 0, nil
+}
+
+func (n *networkCreator) sortedNetworksWithName() ([]string, error) {
 	// query which networks exist with the name
-	ids, err := networksWithName(name)
+	ids, err := networksWithName(n.binaryName, n.name)
 	if err != nil {
 		return nil, err
 	}
@@ -186,7 +237,7 @@ func sortedNetworksWithName(name string) ([]string, error) {
 		return ids, nil
 	}
 	// inspect them to get more detail for sorting
-	networks, err := inspectNetworks(ids)
+	networks, err := n.inspectNetworks(ids)
 	if err != nil {
 		return nil, err
 	}
@@ -201,18 +252,8 @@ func sortedNetworksWithName(name string) ([]string, error) {
 	return sortedIDs, nil
 }
 
-func sortNetworkInspectEntries(networks []networkInspectEntry) {
-	sort.Slice(networks, func(i, j int) bool {
-		// we want networks with active containers first
-		if len(networks[i].Containers) > len(networks[j].Containers) {
-			return true
-		}
-		return networks[i].ID < networks[j].ID
-	})
-}
-
-func inspectNetworks(networkIDs []string) ([]networkInspectEntry, error) {
-	inspectOut, err := exec.Output(exec.Command("docker", append([]string{"network", "inspect"}, networkIDs...)...))
+func (n *networkCreator) inspectNetworks(networkIDs []string) ([]networkInspectEntry, error) {
+	inspectOut, err := exec.Output(exec.Command(n.binaryName, append([]string{"network", "inspect"}, networkIDs...)...))
 	// NOTE: the caller can detect if the network isn't present in the output anyhow
 	// we don't want to fail on this here.
 	if err != nil && !isOnlyErrorNoSuchNetwork(err) {
@@ -226,6 +267,16 @@ func inspectNetworks(networkIDs []string) ([]networkInspectEntry, error) {
 	return networks, nil
 }
 
+func sortNetworkInspectEntries(networks []networkInspectEntry) {
+	sort.Slice(networks, func(i, j int) bool {
+		// we want networks with active containers first
+		if len(networks[i].Containers) > len(networks[j].Containers) {
+			return true
+		}
+		return networks[i].ID < networks[j].ID
+	})
+}
+
 type networkInspectEntry struct {
 	ID string `json:"Id"`
 	// NOTE: we don't care about the contents here but we need to parse
@@ -234,9 +285,9 @@ type networkInspectEntry struct {
 }
 
 // networksWithName returns a list of network IDs for networks with this name
-func networksWithName(name string) ([]string, error) {
+func networksWithName(binaryName, name string) ([]string, error) {
 	lsOut, err := exec.Output(exec.Command(
-		"docker", "network", "ls",
+		binaryName, "network", "ls",
 		"--filter=name=^"+regexp.QuoteMeta(name)+"$",
 		"--format={{.ID}}", // output as unambiguous IDs
 	))
@@ -250,15 +301,6 @@ func networksWithName(name string) ([]string, error) {
 	return strings.Split(cleaned, "\n"), nil
 }
 
-func checkIfNetworkExists(name string) (bool, error) {
-	out, err := exec.Output(exec.Command(
-		"docker", "network", "ls",
-		"--filter=name=^"+regexp.QuoteMeta(name)+"$",
-		"--format={{.Name}}",
-	))
-	return strings.HasPrefix(string(out), name), err
-}
-
 func isIPv6UnavailableError(err error) bool {
 	rerr := exec.RunErrorForError(err)
 	if rerr == nil {
@@ -266,20 +308,20 @@ func isIPv6UnavailableError(err error) bool {
 	}
 	errorMessage := string(rerr.Output)
 	// we get this error when ipv6 was disabled in docker
-	const dockerIPV6DisabledError = "Error response from daemon: Cannot read IPv6 setup for bridge"
+	const dockerIPV6DisabledError = "Cannot read IPv6 setup for bridge"
 	// TODO: this is fragile, and only necessary due to docker enabling ipv6 by default
 	// even on hosts that lack ip6tables setup.
 	// Preferably users would either have ip6tables setup properly or else disable ipv6 in docker
-	const dockerIPV6TablesError = "Error response from daemon: Failed to Setup IP tables: Unable to enable NAT rule:  (iptables failed: ip6tables"
+	const dockerIPV6TablesError = "Failed to Setup IP tables: Unable to enable NAT rule:  (iptables failed: ip6tables"
 	// we get this error when ipv6 is missing in kernel
-	const dockerIPV6PolicyError = "Error response from daemon: setting default policy to DROP in FORWARD chain failed:  (iptables failed: ip6tables"
+	const dockerIPV6PolicyError = "setting default policy to DROP in FORWARD chain failed:  (iptables failed: ip6tables"
 
-	return strings.HasPrefix(errorMessage, dockerIPV6DisabledError) || strings.HasPrefix(errorMessage, dockerIPV6TablesError) || strings.HasPrefix(errorMessage, dockerIPV6PolicyError)
+	return strings.Contains(errorMessage, dockerIPV6DisabledError) || strings.Contains(errorMessage, dockerIPV6TablesError) || strings.Contains(errorMessage, dockerIPV6PolicyError)
 }
 
 func isPoolOverlapError(err error) bool {
 	rerr := exec.RunErrorForError(err)
-	return rerr != nil && strings.HasPrefix(string(rerr.Output), "Error response from daemon: Pool overlaps with other one on this address space") || strings.Contains(string(rerr.Output), "networks have overlapping")
+	return rerr != nil && (strings.Contains(string(rerr.Output), "Pool overlaps with other one on this address space") || strings.Contains(string(rerr.Output), "networks have overlapping"))
 }
 
 func isNetworkAlreadyExistsError(err error) bool {
@@ -317,13 +359,9 @@ func isOnlyErrorNoSuchNetwork(err error) bool {
 	return true
 }
 
-func deleteNetworks(networks ...string) error {
-	return exec.Command("docker", append([]string{"network", "rm"}, networks...)...).Run()
-}
-
-// generateULASubnetFromName generate an IPv6 subnet based on the
+// GenerateULASubnetFromName generate an IPv6 subnet based on the
 // name and Nth probing attempt
-func generateULASubnetFromName(name string, attempt int32) string {
+func GenerateULASubnetFromName(name string, attempt int32) string {
 	ip := make([]byte, 16)
 	ip[0] = 0xfc
 	ip[1] = 0x00
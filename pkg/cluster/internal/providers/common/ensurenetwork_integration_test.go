@@ -17,7 +17,7 @@ See the License for the specific language governing permissions and
 limitations under the License.
 */
 
-package docker
+package common
 
 import (
 	"fmt"
@@ -27,6 +27,7 @@ import (
 	"sigs.k8s.io/kind/pkg/errors"
 	"sigs.k8s.io/kind/pkg/exec"
 
+	"sigs.k8s.io/kind/pkg/internal/apis/config"
 	"sigs.k8s.io/kind/pkg/internal/integration"
 )
 
@@ -37,9 +38,9 @@ func TestIntegrationEnsureNetworkConcurrent(t *testing.T) {
 
 	// cleanup
 	cleanup := func() {
-		ids, _ := networksWithName(testNetworkName)
+		ids, _ := networksWithName("docker", testNetworkName)
 		if len(ids) > 0 {
-			_ = deleteNetworks(ids...)
+			_ = exec.Command("docker", append([]string{"network", "rm"}, ids...)...).Run()
 		}
 	}
 	cleanup()
@@ -48,11 +49,13 @@ func TestIntegrationEnsureNetworkConcurrent(t *testing.T) {
 	// this is more than enough to trigger race conditions
 	networkConcurrency := 10
 
+	dialect := &Dialect{RemoveDuplicateNetworks: true}
+
 	// Create multiple networks concurrently
 	errCh := make(chan error, networkConcurrency)
 	for i := 0; i < networkConcurrency; i++ {
 		go func() {
-			errCh <- ensureNetwork(testNetworkName)
+			errCh <- EnsureNetwork("docker", dialect, testNetworkName, &config.ContainerNetwork{}, false)
 		}()
 	}
 	for i := 0; i < networkConcurrency; i++ {
@@ -1,5 +1,5 @@
 /*
-Copyright 2020 The Kubernetes Authors.
+Copyright The Kubernetes Authors.
 
 Licensed under the Apache License, Version 2.0 (the "License");
 you may not use this file except in compliance with the License.
@@ -14,7 +14,7 @@ See the License for the specific language governing permissions and
 limitations under the License.
 */
 
-package docker
+package common
 
 import (
 	"fmt"
@@ -23,7 +23,7 @@ import (
 	"sigs.k8s.io/kind/pkg/internal/assert"
 )
 
-func Test_generateULASubnetFromName(t *testing.T) {
+func TestGenerateULASubnetFromName(t *testing.T) {
 	t.Parallel()
 	cases := []struct {
 		name    string
@@ -61,7 +61,7 @@ func Test_generateULASubnetFromName(t *testing.T) {
 		tc := tc // capture variable
 		t.Run(fmt.Sprintf("%s,%d", tc.name, tc.attempt), func(t *testing.T) {
 			t.Parallel()
-			subnet := generateULASubnetFromName(tc.name, tc.attempt)
+			subnet := GenerateULASubnetFromName(tc.name, tc.attempt)
 			if subnet != tc.subnet {
 				t.Errorf("Wrong subnet from %v: expected %v, received %v", tc.name, tc.subnet, subnet)
 			}
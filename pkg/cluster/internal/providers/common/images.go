@@ -17,6 +17,12 @@ limitations under the License.
 package common
 
 import (
+	"io"
+	"strings"
+
+	"sigs.k8s.io/kind/pkg/errors"
+	"sigs.k8s.io/kind/pkg/exec"
+
 	"sigs.k8s.io/kind/pkg/internal/apis/config"
 	"sigs.k8s.io/kind/pkg/internal/sets"
 )
@@ -31,3 +37,67 @@ func RequiredNodeImages(cfg *config.Cluster) sets.String {
 	}
 	return images
 }
+
+// ListImages returns the tagged images in the local image store of the
+// container runtime binaryName matching the reference filter
+func ListImages(binaryName, reference string) ([]string, error) {
+	cmd := exec.Command(binaryName, "images",
+		"--filter", "reference="+reference,
+		"--format", "{{.Repository}}:{{.Tag}}",
+	)
+	lines, err := exec.OutputLines(cmd)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list images")
+	}
+	return parseImageList(lines), nil
+}
+
+// parseImageList parses `images --format {{.Repository}}:{{.Tag}}` output,
+// dropping untagged images and duplicates
+func parseImageList(lines []string) []string {
+	images := sets.NewString()
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.Contains(line, "<none>") {
+			continue
+		}
+		images.Insert(line)
+	}
+	return images.List()
+}
+
+// ImageID returns the ID of image in the local image store of the container
+// runtime binaryName, with the "sha256:" prefix which some runtimes omit
+func ImageID(binaryName, image string) (string, error) {
+	cmd := exec.Command(binaryName, "image", "inspect",
+		"-f", "{{ .Id }}",
+		image,
+	)
+	lines, err := exec.OutputLines(cmd)
+	if err != nil {
+		return "", errors.Wrapf(err, "image %q not present locally", image)
+	}
+	if len(lines) != 1 {
+		return "", errors.Errorf("image ID should only be one line, got %d lines", len(lines))
+	}
+	return normalizeImageID(lines[0]), nil
+}
+
+// normalizeImageID adds the "sha256:" prefix to bare image IDs
+func normalizeImageID(id string) string {
+	id = strings.TrimSpace(id)
+	if id != "" && !strings.Contains(id, ":") {
+		return "sha256:" + id
+	}
+	return id
+}
+
+// SaveImages writes an archive of images in the local image store of the
+// container runtime binaryName to w
+func SaveImages(binaryName string, w io.Writer, images ...string) error {
+	args := append([]string{"save"}, images...)
+	if err := exec.Command(binaryName, args...).SetStdout(w).Run(); err != nil {
+		return errors.Wrap(err, "failed to save images")
+	}
+	return nil
+}
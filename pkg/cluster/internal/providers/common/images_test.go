@@ -66,3 +66,33 @@ func TestRequiredNodeImages(t *testing.T) {
 		})
 	}
 }
+
+func TestParseImageList(t *testing.T) {
+	t.Parallel()
+	lines := []string{
+		"kindest/node:v1.36.1",
+		"kindest/node:latest",
+		"<none>:<none>",
+		"kindest/node:<none>",
+		"",
+		"kindest/node:latest",
+	}
+	want := []string{"kindest/node:latest", "kindest/node:v1.36.1"}
+	if got := parseImageList(lines); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseImageList() = %v, want %v", got, want)
+	}
+}
+
+func TestNormalizeImageID(t *testing.T) {
+	t.Parallel()
+	for in, want := range map[string]string{
+		"sha256:9f1c":   "sha256:9f1c",
+		"9f1c\n":        "sha256:9f1c",
+		"":              "",
+		"sha512:abcdef": "sha512:abcdef",
+	} {
+		if got := normalizeImageID(in); got != want {
+			t.Errorf("normalizeImageID(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
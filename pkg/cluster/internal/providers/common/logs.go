@@ -19,6 +19,7 @@ package common
 import (
 	"os"
 	"path/filepath"
+	"time"
 )
 
 // FileOnHost is a helper to create a file at path
@@ -30,3 +31,17 @@ func FileOnHost(path string) (*os.File, error) {
 	}
 	return os.Create(path)
 }
+
+// SerialLogsArgs returns the docker CLI compatible `logs` arguments for the
+// node container logs with timestamps, following them if follow is true,
+// starting since ago if non-zero
+func SerialLogsArgs(name string, follow bool, since time.Duration) []string {
+	args := []string{"logs", "--timestamps"}
+	if follow {
+		args = append(args, "--follow")
+	}
+	if since > 0 {
+		args = append(args, "--since", since.String())
+	}
+	return append(args, name)
+}
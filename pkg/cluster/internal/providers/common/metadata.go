@@ -0,0 +1,115 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"sigs.k8s.io/kind/pkg/errors"
+
+	"sigs.k8s.io/kind/pkg/cluster/internal/providers"
+	"sigs.k8s.io/kind/pkg/internal/apis/config"
+)
+
+// cluster labels and annotations are stored as node container labels
+// with these prefixes, followed by the original key
+const (
+	clusterLabelPrefix      = "io.x-k8s.kind.label/"
+	clusterAnnotationPrefix = "io.x-k8s.kind.annotation/"
+)
+
+// GenerateClusterMetadataArgs converts the cluster labels and annotations to
+// a list of `run` args for docker compatible CLIs
+func GenerateClusterMetadataArgs(labels, annotations map[string]string) []string {
+	args := []string{}
+	for _, m := range []struct {
+		prefix string
+		values map[string]string
+	}{
+		{clusterLabelPrefix, labels},
+		{clusterAnnotationPrefix, annotations},
+	} {
+		keys := make([]string, 0, len(m.values))
+		for key := range m.values {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			args = append(args, "--label", fmt.Sprintf("%s%s=%s", m.prefix, key, m.values[key]))
+		}
+	}
+	return args
+}
+
+// GenerateSubnetLabelArgs returns the `run` args recording the pod and service
+// subnets of multi-cluster clusters, see PodSubnetLabelKey
+func GenerateSubnetLabelArgs(networking *config.Networking) []string {
+	if !networking.MultiCluster {
+		return []string{}
+	}
+	return []string{
+		"--label", fmt.Sprintf("%s=%s", PodSubnetLabelKey, networking.PodSubnet),
+		"--label", fmt.Sprintf("%s=%s", ServiceSubnetLabelKey, networking.ServiceSubnet),
+	}
+}
+
+// ClusterMetadataInspectFormat is the `inspect --format` template used with
+// ParseClusterMetadata, it outputs one line per container
+const ClusterMetadataInspectFormat = `{{ json .Config.Labels }}{{ "\t" }}{{ json .Created }}`
+
+// ParseClusterMetadata parses the ClusterMetadataInspectFormat output for
+// all of a cluster's node containers
+func ParseClusterMetadata(lines []string) (*providers.ClusterMetadata, error) {
+	if len(lines) == 0 {
+		return nil, errors.New("no nodes to read cluster metadata from")
+	}
+	metadata := &providers.ClusterMetadata{
+		Labels:      map[string]string{},
+		Annotations: map[string]string{},
+	}
+	for i, line := range lines {
+		parts := strings.SplitN(line, "\t", 2)
+		if len(parts) != 2 {
+			return nil, errors.Errorf("invalid container metadata: %q", line)
+		}
+		labels := map[string]string{}
+		if err := json.Unmarshal([]byte(parts[0]), &labels); err != nil {
+			return nil, errors.Wrap(err, "failed to decode container labels")
+		}
+		var created time.Time
+		if err := json.Unmarshal([]byte(parts[1]), &created); err != nil {
+			return nil, errors.Wrap(err, "failed to decode container creation time")
+		}
+		if i == 0 || created.Before(metadata.CreationTimestamp) {
+			metadata.CreationTimestamp = created
+		}
+		// the labels are the same on every node that has them, port
+		// forwarders are created without them
+		for key, value := range labels {
+			if strings.HasPrefix(key, clusterLabelPrefix) {
+				metadata.Labels[strings.TrimPrefix(key, clusterLabelPrefix)] = value
+			} else if strings.HasPrefix(key, clusterAnnotationPrefix) {
+				metadata.Annotations[strings.TrimPrefix(key, clusterAnnotationPrefix)] = value
+			}
+		}
+	}
+	return metadata, nil
+}
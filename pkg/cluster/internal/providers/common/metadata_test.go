@@ -0,0 +1,75 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"testing"
+	"time"
+
+	"sigs.k8s.io/kind/pkg/cluster/internal/providers"
+	"sigs.k8s.io/kind/pkg/internal/apis/config"
+	"sigs.k8s.io/kind/pkg/internal/assert"
+)
+
+func TestGenerateClusterMetadataArgs(t *testing.T) {
+	t.Parallel()
+	args := GenerateClusterMetadataArgs(
+		map[string]string{"owner": "ci", "example.com/job": "1234"},
+		map[string]string{"purpose": "e2e, nightly"},
+	)
+	assert.DeepEqual(t, []string{
+		"--label", "io.x-k8s.kind.label/example.com/job=1234",
+		"--label", "io.x-k8s.kind.label/owner=ci",
+		"--label", "io.x-k8s.kind.annotation/purpose=e2e, nightly",
+	}, args)
+}
+
+func TestGenerateSubnetLabelArgs(t *testing.T) {
+	t.Parallel()
+	networking := config.Networking{
+		PodSubnet:     "10.245.0.0/16,fd00:10:244:100::/56",
+		ServiceSubnet: "10.97.0.0/16,fd00:10:96::1:0/112",
+	}
+	assert.DeepEqual(t, []string{}, GenerateSubnetLabelArgs(&networking))
+	networking.MultiCluster = true
+	assert.DeepEqual(t, []string{
+		"--label", "io.x-k8s.kind.pod-subnet=10.245.0.0/16,fd00:10:244:100::/56",
+		"--label", "io.x-k8s.kind.service-subnet=10.97.0.0/16,fd00:10:96::1:0/112",
+	}, GenerateSubnetLabelArgs(&networking))
+}
+
+func TestParseClusterMetadata(t *testing.T) {
+	t.Parallel()
+	metadata, err := ParseClusterMetadata([]string{
+		// port forwarders do not have the cluster metadata
+		`{"io.x-k8s.kind.cluster":"kind","io.x-k8s.kind.role":"port-forwarder"}` + "\t" + `"2024-05-03T00:00:00Z"`,
+		`{"io.x-k8s.kind.cluster":"kind","io.x-k8s.kind.label/owner":"ci","io.x-k8s.kind.annotation/purpose":"e2e"}` + "\t" + `"2024-05-02T10:00:01.5Z"`,
+		`{"io.x-k8s.kind.cluster":"kind","io.x-k8s.kind.label/owner":"ci","io.x-k8s.kind.annotation/purpose":"e2e"}` + "\t" + `"2024-05-02T10:00:00Z"`,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.DeepEqual(t, &providers.ClusterMetadata{
+		Labels:            map[string]string{"owner": "ci"},
+		Annotations:       map[string]string{"purpose": "e2e"},
+		CreationTimestamp: time.Date(2024, 5, 2, 10, 0, 0, 0, time.UTC),
+	}, metadata)
+
+	if _, err := ParseClusterMetadata(nil); err == nil {
+		t.Errorf("expected error for no nodes")
+	}
+}
@@ -0,0 +1,395 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"sigs.k8s.io/kind/pkg/errors"
+	"sigs.k8s.io/kind/pkg/exec"
+
+	"sigs.k8s.io/kind/pkg/internal/apis/config"
+)
+
+// NetworkSubnet is a subnet configured on an existing container network
+type NetworkSubnet struct {
+	Subnet  string
+	Gateway string
+	// RangeStart and RangeEnd are the first and last address the runtime
+	// assigns dynamically, if limited to a part of Subnet, E.G. with
+	// `network create --ip-range`
+	RangeStart string
+	RangeEnd   string
+}
+
+// inDynamicRange returns true if ip may be assigned dynamically by the
+// runtime, when the network limits that to a range
+func (n *NetworkSubnet) inDynamicRange(ip net.IP) bool {
+	start, end := net.ParseIP(n.RangeStart), net.ParseIP(n.RangeEnd)
+	if start == nil || end == nil {
+		return false
+	}
+	ip = ip.To16()
+	return bytes.Compare(ip, start.To16()) >= 0 && bytes.Compare(ip, end.To16()) <= 0
+}
+
+// cidrRange returns the first and last address of the CIDR, empty if it is
+// invalid
+func cidrRange(cidr string) (first, last string) {
+	_, ipNet, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return "", ""
+	}
+	end := make(net.IP, len(ipNet.IP))
+	for i := range ipNet.IP {
+		end[i] = ipNet.IP[i] | ^ipNet.Mask[i]
+	}
+	return ipNet.IP.String(), end.String()
+}
+
+// NetworkSettings are the settings of an existing container network
+type NetworkSettings struct {
+	Subnets []NetworkSubnet
+	// MTU is 0 if unknown
+	MTU int
+}
+
+// subnet returns the subnet of the given family, if any
+func (n *NetworkSettings) subnet(ipv6 bool) *NetworkSubnet {
+	for i := range n.Subnets {
+		ip, _, err := net.ParseCIDR(n.Subnets[i].Subnet)
+		if err != nil {
+			continue
+		}
+		if (ip.To4() == nil) == ipv6 {
+			return &n.Subnets[i]
+		}
+	}
+	return nil
+}
+
+// ValidateExistingNetwork returns an error describing all conflicts between
+// the requested network settings and an existing network's settings, or nil
+// if the existing network is compatible.
+// requireIPv6 should be true if the cluster needs IPv6 node addresses.
+func ValidateExistingNetwork(name string, requested *config.ContainerNetwork, existing *NetworkSettings, requireIPv6 bool) error {
+	errs := []error{}
+	for _, family := range []struct {
+		name    string
+		ipv6    bool
+		subnet  string
+		gateway string
+	}{
+		{"IPv4", false, requested.IPv4Subnet, requested.IPv4Gateway},
+		{"IPv6", true, requested.IPv6Subnet, requested.IPv6Gateway},
+	} {
+		actual := existing.subnet(family.ipv6)
+		if actual == nil {
+			if family.subnet != "" || (family.ipv6 && requireIPv6) {
+				errs = append(errs, errors.Errorf("network %q has no %s subnet", name, family.name))
+			}
+			continue
+		}
+		if family.subnet != "" && !sameCIDR(family.subnet, actual.Subnet) {
+			errs = append(errs, errors.Errorf("network %q has %s subnet %q, but %q was requested", name, family.name, actual.Subnet, family.subnet))
+		}
+		if family.gateway != "" && actual.Gateway != "" && !sameIP(family.gateway, actual.Gateway) {
+			errs = append(errs, errors.Errorf("network %q has %s gateway %q, but %q was requested", name, family.name, actual.Gateway, family.gateway))
+		}
+	}
+	if requested.MTU != 0 && existing.MTU != 0 && int(requested.MTU) != existing.MTU {
+		errs = append(errs, errors.Errorf("network %q has mtu %d, but %d was requested", name, existing.MTU, requested.MTU))
+	}
+	if len(errs) > 0 {
+		return errors.Wrapf(errors.NewAggregate(errs), "existing network %q conflicts with the requested containerNetwork, delete the network or update the config", name)
+	}
+	return nil
+}
+
+// ValidateNodeAddresses returns an error describing all static node addresses
+// that do not fit the existing network's subnets, or nil if there are none
+func ValidateNodeAddresses(name string, nodes []config.Node, existing *NetworkSettings) error {
+	errs := []error{}
+	for _, n := range nodes {
+		errs = append(errs, validateAddresses(name, n.IPv4Address, n.IPv6Address, existing)...)
+	}
+	if len(errs) > 0 {
+		return errors.Wrap(errors.NewAggregate(errs), "invalid static node addresses")
+	}
+	return nil
+}
+
+// ValidateExtraNetworkAddresses returns an error describing all static
+// extraNetworks addresses that do not fit the subnets of their network,
+// inspect returns the settings of an existing network by name
+func ValidateExtraNetworkAddresses(nodes []config.Node, inspect func(name string) (*NetworkSettings, error)) error {
+	errs := []error{}
+	inspected := map[string]*NetworkSettings{}
+	for _, n := range nodes {
+		for _, network := range n.ExtraNetworks {
+			if network.IPv4Address == "" && network.IPv6Address == "" {
+				continue
+			}
+			existing, ok := inspected[network.Name]
+			if !ok {
+				var err error
+				existing, err = inspect(network.Name)
+				if err != nil {
+					return err
+				}
+				inspected[network.Name] = existing
+			}
+			errs = append(errs, validateAddresses(network.Name, network.IPv4Address, network.IPv6Address, existing)...)
+		}
+	}
+	if len(errs) > 0 {
+		return errors.Wrap(errors.NewAggregate(errs), "invalid static extraNetworks addresses")
+	}
+	return nil
+}
+
+// validateAddresses checks the optional static addresses against the
+// subnets of the existing network name
+func validateAddresses(name, ipv4Address, ipv6Address string, existing *NetworkSettings) []error {
+	errs := []error{}
+	for _, family := range []struct {
+		name    string
+		ipv6    bool
+		address string
+	}{
+		{"IPv4", false, ipv4Address},
+		{"IPv6", true, ipv6Address},
+	} {
+		if family.address == "" {
+			continue
+		}
+		actual := existing.subnet(family.ipv6)
+		if actual == nil {
+			errs = append(errs, errors.Errorf("address %q requires an %s subnet, but network %q has none", family.address, family.name, name))
+			continue
+		}
+		_, cidr, err := net.ParseCIDR(actual.Subnet)
+		if err != nil {
+			continue
+		}
+		ip := net.ParseIP(family.address)
+		if !cidr.Contains(ip) {
+			errs = append(errs, errors.Errorf("address %q is not within the %s subnet %q of network %q", family.address, family.name, actual.Subnet, name))
+		} else if actual.Gateway != "" && sameIP(family.address, actual.Gateway) {
+			errs = append(errs, errors.Errorf("address %q is the gateway of network %q", family.address, name))
+		} else if actual.inDynamicRange(ip) {
+			errs = append(errs, errors.Errorf("address %q is within %s-%s, which network %q reserves for dynamically assigned addresses", family.address, actual.RangeStart, actual.RangeEnd, name))
+		}
+	}
+	return errs
+}
+
+// HasStaticAddresses returns true if any of the nodes has a static address
+func HasStaticAddresses(nodes []config.Node) bool {
+	for _, n := range nodes {
+		if n.IPv4Address != "" || n.IPv6Address != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// NetworkSubnetArgs returns the `network create` arguments for the requested
+// IPv4 settings and the IPv6 subnet, which may have been generated instead of
+// requested. The requested IPv6 gateway is only used for the requested subnet.
+// Requested subnets are created with the dynamic address range of
+// config.DynamicAddressRange, reserving the rest for static node addresses.
+func NetworkSubnetArgs(requested *config.ContainerNetwork, ipv6Subnet string) []string {
+	args := []string{}
+	if requested.IPv4Subnet != "" {
+		args = append(args, "--subnet", requested.IPv4Subnet)
+		if requested.IPv4Gateway != "" {
+			args = append(args, "--gateway", requested.IPv4Gateway)
+		}
+		if ipRange := config.DynamicAddressRange(requested.IPv4Subnet); ipRange != "" {
+			args = append(args, "--ip-range", ipRange)
+		}
+	}
+	if ipv6Subnet != "" {
+		args = append(args, "--ipv6", "--subnet", ipv6Subnet)
+		if ipv6Subnet == requested.IPv6Subnet {
+			if requested.IPv6Gateway != "" {
+				args = append(args, "--gateway", requested.IPv6Gateway)
+			}
+			if ipRange := config.DynamicAddressRange(requested.IPv6Subnet); ipRange != "" {
+				args = append(args, "--ip-range", ipRange)
+			}
+		}
+	}
+	return args
+}
+
+// ParseDockerNetworkInspect parses `network inspect` output from docker CLI
+// compatible runtimes, mtuOption is the driver option key containing the MTU
+func ParseDockerNetworkInspect(inspectOut []byte, mtuOption string) (*NetworkSettings, error) {
+	networks := []struct {
+		IPAM struct {
+			Config []struct {
+				Subnet  string `json:"Subnet"`
+				Gateway string `json:"Gateway"`
+				IPRange string `json:"IPRange"`
+			} `json:"Config"`
+		} `json:"IPAM"`
+		Options map[string]string `json:"Options"`
+	}{}
+	if err := json.Unmarshal(inspectOut, &networks); err != nil {
+		return nil, errors.Wrap(err, "failed to decode network inspect output")
+	}
+	if len(networks) != 1 {
+		return nil, errors.Errorf("expected one network in inspect output, got %d", len(networks))
+	}
+	settings := &NetworkSettings{}
+	for _, c := range networks[0].IPAM.Config {
+		subnet := NetworkSubnet{
+			Subnet:  c.Subnet,
+			Gateway: c.Gateway,
+		}
+		subnet.RangeStart, subnet.RangeEnd = cidrRange(c.IPRange)
+		settings.Subnets = append(settings.Subnets, subnet)
+	}
+	if mtuOption != "" {
+		if mtu, err := strconv.Atoi(strings.TrimSpace(networks[0].Options[mtuOption])); err == nil {
+			settings.MTU = mtu
+		}
+	}
+	return settings, nil
+}
+
+// NodeIPInspectFormat returns the `inspect --format` template used with
+// ParseNodeIPs, networkLabelKey is the label holding the node's primary network
+func NodeIPInspectFormat(networkLabelKey string) string {
+	return fmt.Sprintf(
+		`{{ index .Config.Labels %q }}{{ range $name, $network := .NetworkSettings.Networks }}|{{ $name }},{{ $network.IPAddress }},{{ $network.GlobalIPv6Address }}{{ end }}`,
+		networkLabelKey,
+	)
+}
+
+// ParseNodeIPs parses the output of NodeIPInspectFormat, returning the node's
+// addresses on its primary network.
+// Nodes created before the primary network label was introduced are only
+// attached to a single network, which is then used.
+func ParseNodeIPs(line string) (ipv4, ipv6 string, err error) {
+	parts := strings.Split(line, "|")
+	primary, networks := parts[0], parts[1:]
+	if primary == "" && len(networks) != 1 {
+		return "", "", errors.Errorf("container is attached to %d networks and has no primary network label", len(networks))
+	}
+	for _, network := range networks {
+		values := strings.Split(network, ",")
+		if len(values) != 3 {
+			return "", "", errors.Errorf("container addresses should have 2 values, got %d values", len(values)-1)
+		}
+		if primary == "" || values[0] == primary {
+			return values[1], values[2], nil
+		}
+	}
+	return "", "", errors.Errorf("container is not attached to its primary network %q", primary)
+}
+
+// PrimaryNetwork returns the name of the primary network of the node
+// container name, using binaryName, a docker CLI compatible binary
+func PrimaryNetwork(binaryName, networkLabelKey, name string) (string, error) {
+	lines, err := exec.OutputLines(exec.Command(binaryName, "inspect",
+		"-f", NodeIPInspectFormat(networkLabelKey),
+		name,
+	))
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get container details")
+	}
+	if len(lines) != 1 {
+		return "", errors.Errorf("file should only be one line, got %d lines", len(lines))
+	}
+	return ParsePrimaryNetwork(lines[0])
+}
+
+// ParsePrimaryNetwork parses the output of NodeIPInspectFormat, returning
+// the name of the node's primary network, see ParseNodeIPs
+func ParsePrimaryNetwork(line string) (string, error) {
+	parts := strings.Split(line, "|")
+	primary, networks := parts[0], parts[1:]
+	if primary != "" {
+		return primary, nil
+	}
+	if len(networks) != 1 {
+		return "", errors.Errorf("container is attached to %d networks and has no primary network label", len(networks))
+	}
+	return strings.SplitN(networks[0], ",", 2)[0], nil
+}
+
+// ConnectExtraNetworks connects the container to the node's extra networks
+// using binaryName, a docker CLI compatible binary
+func ConnectExtraNetworks(binaryName, name string, networks []config.NodeNetwork) error {
+	for _, network := range networks {
+		args := []string{"network", "connect"}
+		if network.IPv4Address != "" {
+			args = append(args, "--ip", network.IPv4Address)
+		}
+		if network.IPv6Address != "" {
+			args = append(args, "--ip6", network.IPv6Address)
+		}
+		args = append(args, network.Name, name)
+		if err := exec.Command(binaryName, args...).Run(); err != nil {
+			return errors.Wrapf(err, "failed to connect %q to network %q", name, network.Name)
+		}
+	}
+	return nil
+}
+
+// GenerateExtraNetworkArgs converts the node's extra networks to a list of
+// `run` args, for runtimes that cannot connect running containers to networks.
+// Static addresses are not supported this way, as they would be ambiguous.
+func GenerateExtraNetworkArgs(networks []config.NodeNetwork) ([]string, error) {
+	args := []string{}
+	for _, network := range networks {
+		if network.IPv4Address != "" || network.IPv6Address != "" {
+			return nil, errors.Errorf("static addresses are not supported for extraNetworks %q with this runtime", network.Name)
+		}
+		args = append(args, "--net", network.Name)
+	}
+	return args, nil
+}
+
+func sameCIDR(a, b string) bool {
+	_, aNet, aErr := net.ParseCIDR(a)
+	_, bNet, bErr := net.ParseCIDR(b)
+	if aErr != nil || bErr != nil {
+		return a == b
+	}
+	return aNet.String() == bNet.String()
+}
+
+func sameIP(a, b string) bool {
+	// some runtimes report the gateway in CIDR notation
+	if i := strings.IndexByte(b, '/'); i >= 0 {
+		b = b[:i]
+	}
+	aIP, bIP := net.ParseIP(a), net.ParseIP(b)
+	if aIP == nil || bIP == nil {
+		return a == b
+	}
+	return aIP.Equal(bIP)
+}
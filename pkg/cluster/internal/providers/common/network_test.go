@@ -0,0 +1,353 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"testing"
+
+	"sigs.k8s.io/kind/pkg/errors"
+
+	"sigs.k8s.io/kind/pkg/internal/apis/config"
+	"sigs.k8s.io/kind/pkg/internal/assert"
+)
+
+func TestValidateExistingNetwork(t *testing.T) {
+	t.Parallel()
+	existing := &NetworkSettings{
+		Subnets: []NetworkSubnet{
+			{Subnet: "172.18.0.0/16", Gateway: "172.18.0.1"},
+			{Subnet: "fc00:f853:ccd:e793::/64", Gateway: "fc00:f853:ccd:e793::1"},
+		},
+		MTU: 1500,
+	}
+	ipv4Only := &NetworkSettings{
+		Subnets: []NetworkSubnet{
+			{Subnet: "172.18.0.0/16", Gateway: "172.18.0.1/16"},
+		},
+	}
+	cases := []struct {
+		name        string
+		requested   config.ContainerNetwork
+		existing    *NetworkSettings
+		requireIPv6 bool
+		expectError bool
+	}{
+		{
+			name:      "nothing requested",
+			existing:  existing,
+			requested: config.ContainerNetwork{},
+		},
+		{
+			name:     "matching settings",
+			existing: existing,
+			requested: config.ContainerNetwork{
+				IPv4Subnet:  "172.18.0.0/16",
+				IPv4Gateway: "172.18.0.1",
+				IPv6Subnet:  "fc00:f853:ccd:e793::/64",
+				MTU:         1500,
+			},
+			requireIPv6: true,
+		},
+		{
+			name:     "gateway in CIDR notation",
+			existing: ipv4Only,
+			requested: config.ContainerNetwork{
+				IPv4Gateway: "172.18.0.1",
+			},
+		},
+		{
+			name:     "different subnet",
+			existing: existing,
+			requested: config.ContainerNetwork{
+				IPv4Subnet: "10.10.0.0/16",
+			},
+			expectError: true,
+		},
+		{
+			name:     "different mtu",
+			existing: existing,
+			requested: config.ContainerNetwork{
+				MTU: 1400,
+			},
+			expectError: true,
+		},
+		{
+			name:        "missing required ipv6",
+			existing:    ipv4Only,
+			requested:   config.ContainerNetwork{},
+			requireIPv6: true,
+			expectError: true,
+		},
+		{
+			name:     "missing requested ipv6",
+			existing: ipv4Only,
+			requested: config.ContainerNetwork{
+				IPv6Subnet: "fc00:f853:ccd:e793::/64",
+			},
+			expectError: true,
+		},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			err := ValidateExistingNetwork("kind", &tc.requested, tc.existing, tc.requireIPv6)
+			assert.ExpectError(t, tc.expectError, err)
+		})
+	}
+}
+
+func TestValidateNodeAddresses(t *testing.T) {
+	t.Parallel()
+	existing := &NetworkSettings{
+		Subnets: []NetworkSubnet{
+			{Subnet: "172.18.0.0/16", Gateway: "172.18.0.1", RangeStart: "172.18.128.0", RangeEnd: "172.18.255.255"},
+		},
+	}
+	cases := []struct {
+		name        string
+		nodes       []config.Node
+		expectError bool
+	}{
+		{
+			name:  "no static addresses",
+			nodes: []config.Node{{}},
+		},
+		{
+			name:  "address within subnet",
+			nodes: []config.Node{{IPv4Address: "172.18.0.10"}},
+		},
+		{
+			name:        "address outside subnet",
+			nodes:       []config.Node{{IPv4Address: "10.0.0.10"}},
+			expectError: true,
+		},
+		{
+			name:        "address in the dynamic range",
+			nodes:       []config.Node{{IPv4Address: "172.18.200.10"}},
+			expectError: true,
+		},
+		{
+			name:        "address is the gateway",
+			nodes:       []config.Node{{IPv4Address: "172.18.0.1"}},
+			expectError: true,
+		},
+		{
+			name:        "network lacks subnet family",
+			nodes:       []config.Node{{IPv6Address: "fc00::10"}},
+			expectError: true,
+		},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			err := ValidateNodeAddresses("kind", tc.nodes, existing)
+			assert.ExpectError(t, tc.expectError, err)
+		})
+	}
+}
+
+func TestValidateExtraNetworkAddresses(t *testing.T) {
+	t.Parallel()
+	networks := map[string]*NetworkSettings{
+		"dmz": {Subnets: []NetworkSubnet{{Subnet: "10.20.0.0/16", Gateway: "10.20.0.1"}}},
+	}
+	inspect := func(name string) (*NetworkSettings, error) {
+		if n, ok := networks[name]; ok {
+			return n, nil
+		}
+		return nil, errors.Errorf("no such network %q", name)
+	}
+	cases := []struct {
+		name        string
+		nodes       []config.Node
+		expectError bool
+	}{
+		{
+			name:  "no static addresses does not inspect",
+			nodes: []config.Node{{ExtraNetworks: []config.NodeNetwork{{Name: "missing"}}}},
+		},
+		{
+			name:  "address within subnet",
+			nodes: []config.Node{{ExtraNetworks: []config.NodeNetwork{{Name: "dmz", IPv4Address: "10.20.0.10"}}}},
+		},
+		{
+			name:        "address outside subnet",
+			nodes:       []config.Node{{ExtraNetworks: []config.NodeNetwork{{Name: "dmz", IPv4Address: "10.30.0.10"}}}},
+			expectError: true,
+		},
+		{
+			name:        "network lacks subnet family",
+			nodes:       []config.Node{{ExtraNetworks: []config.NodeNetwork{{Name: "dmz", IPv6Address: "fd00:20::10"}}}},
+			expectError: true,
+		},
+		{
+			name:        "network does not exist",
+			nodes:       []config.Node{{ExtraNetworks: []config.NodeNetwork{{Name: "missing", IPv4Address: "10.20.0.10"}}}},
+			expectError: true,
+		},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			err := ValidateExtraNetworkAddresses(tc.nodes, inspect)
+			assert.ExpectError(t, tc.expectError, err)
+		})
+	}
+}
+
+func TestParseNodeIPs(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name        string
+		line        string
+		ipv4        string
+		ipv6        string
+		expectError bool
+	}{
+		{
+			name: "single network without label",
+			line: "|kind,172.18.0.2,fc00::2",
+			ipv4: "172.18.0.2",
+			ipv6: "fc00::2",
+		},
+		{
+			name: "primary network selected",
+			line: "kind|dmz,10.20.0.10,|kind,172.18.0.2,",
+			ipv4: "172.18.0.2",
+		},
+		{
+			name:        "multiple networks without label",
+			line:        "|dmz,10.20.0.10,|kind,172.18.0.2,",
+			expectError: true,
+		},
+		{
+			name:        "not attached to primary network",
+			line:        "kind|dmz,10.20.0.10,",
+			expectError: true,
+		},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			ipv4, ipv6, err := ParseNodeIPs(tc.line)
+			assert.ExpectError(t, tc.expectError, err)
+			assert.StringEqual(t, tc.ipv4, ipv4)
+			assert.StringEqual(t, tc.ipv6, ipv6)
+		})
+	}
+}
+
+func TestParsePrimaryNetwork(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name        string
+		line        string
+		network     string
+		expectError bool
+	}{
+		{
+			name:    "single network without label",
+			line:    "|kind,172.18.0.2,fc00::2",
+			network: "kind",
+		},
+		{
+			name:    "primary network label",
+			line:    "kind|dmz,10.20.0.10,|kind,172.18.0.2,",
+			network: "kind",
+		},
+		{
+			name:        "multiple networks without label",
+			line:        "|dmz,10.20.0.10,|kind,172.18.0.2,",
+			expectError: true,
+		},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			network, err := ParsePrimaryNetwork(tc.line)
+			assert.ExpectError(t, tc.expectError, err)
+			assert.StringEqual(t, tc.network, network)
+		})
+	}
+}
+
+func TestNetworkSubnetArgs(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name       string
+		requested  config.ContainerNetwork
+		ipv6Subnet string
+		want       []string
+	}{
+		{
+			name: "nothing requested",
+			want: []string{},
+		},
+		{
+			name:       "generated ipv6 subnet ignores requested gateway",
+			requested:  config.ContainerNetwork{IPv6Gateway: "fc00::1"},
+			ipv6Subnet: "fc00:f853:ccd:e793::/64",
+			want:       []string{"--ipv6", "--subnet", "fc00:f853:ccd:e793::/64"},
+		},
+		{
+			name: "all requested",
+			requested: config.ContainerNetwork{
+				IPv4Subnet:  "172.30.0.0/16",
+				IPv4Gateway: "172.30.0.1",
+				IPv6Subnet:  "fc00::/64",
+				IPv6Gateway: "fc00::1",
+			},
+			ipv6Subnet: "fc00::/64",
+			want: []string{
+				"--subnet", "172.30.0.0/16", "--gateway", "172.30.0.1", "--ip-range", "172.30.128.0/17",
+				"--ipv6", "--subnet", "fc00::/64", "--gateway", "fc00::1", "--ip-range", "fc00::8000:0:0:0/65",
+			},
+		},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			assert.DeepEqual(t, tc.want, NetworkSubnetArgs(&tc.requested, tc.ipv6Subnet))
+		})
+	}
+}
+
+func TestParseDockerNetworkInspect(t *testing.T) {
+	t.Parallel()
+	out := []byte(`[{"Name":"kind","IPAM":{"Driver":"default","Config":[{"Subnet":"172.18.0.0/16","Gateway":"172.18.0.1","IPRange":"172.18.128.0/17"},{"Subnet":"fc00:f853:ccd:e793::/64"}]},"Options":{"com.docker.network.driver.mtu":"1400"}}]`)
+	settings, err := ParseDockerNetworkInspect(out, "com.docker.network.driver.mtu")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.DeepEqual(t, &NetworkSettings{
+		Subnets: []NetworkSubnet{
+			{Subnet: "172.18.0.0/16", Gateway: "172.18.0.1", RangeStart: "172.18.128.0", RangeEnd: "172.18.255.255"},
+			{Subnet: "fc00:f853:ccd:e793::/64"},
+		},
+		MTU: 1400,
+	}, settings)
+
+	if _, err := ParseDockerNetworkInspect([]byte(`[]`), ""); err == nil {
+		t.Errorf("expected error for empty inspect output")
+	}
+}
@@ -1,5 +1,5 @@
 /*
-Copyright 2019 The Kubernetes Authors.
+Copyright The Kubernetes Authors.
 
 Licensed under the Apache License, Version 2.0 (the "License");
 you may not use this file except in compliance with the License.
@@ -14,12 +14,8 @@ See the License for the specific language governing permissions and
 limitations under the License.
 */
 
-// Package nerdctl implements the nerdctl kind provider.
-package nerdctl
+package common
 
-// clusterLabelKey is applied to each "node" container for identification
-const clusterLabelKey = "io.x-k8s.kind.cluster"
-
-// nodeRoleLabelKey is applied to each "node" container for categorization
-// of nodes by role
-const nodeRoleLabelKey = "io.x-k8s.kind.role"
+// PortForwarderTargetLabelKey is applied to each port forwarder container to
+// record the node the port is forwarded to
+const PortForwarderTargetLabelKey = "io.x-k8s.kind.port-forwarder.target"
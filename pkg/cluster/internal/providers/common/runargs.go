@@ -1,5 +1,5 @@
 /*
-Copyright 2019 The Kubernetes Authors.
+Copyright The Kubernetes Authors.
 
 Licensed under the Apache License, Version 2.0 (the "License");
 you may not use this file except in compliance with the License.
@@ -14,31 +14,27 @@ See the License for the specific language governing permissions and
 limitations under the License.
 */
 
-package docker
+package common
 
 import (
-	"context"
 	"fmt"
-	"net"
-	"path/filepath"
 	"strings"
-	"time"
 
 	"sigs.k8s.io/kind/pkg/cluster/constants"
 	"sigs.k8s.io/kind/pkg/errors"
 	"sigs.k8s.io/kind/pkg/exec"
-	"sigs.k8s.io/kind/pkg/fs"
 
 	"sigs.k8s.io/kind/pkg/cluster/internal/loadbalancer"
-	"sigs.k8s.io/kind/pkg/cluster/internal/providers/common"
+	"sigs.k8s.io/kind/pkg/cluster/internal/providers"
 	"sigs.k8s.io/kind/pkg/internal/apis/config"
 )
 
-// planCreation creates a slice of funcs that will create the containers
-func planCreation(cfg *config.Cluster, networkName string) (createContainerFuncs []func() error, err error) {
+// PlanCreation creates a slice of funcs that will create the containers of
+// cfg with the docker CLI compatible binaryName
+func PlanCreation(binaryName string, dialect *Dialect, cfg *config.Cluster, networkName string) (createContainerFuncs []func() error, err error) {
 	// we need to know all the names for NO_PROXY
 	// compute the names first before any actual node details
-	nodeNamer := common.MakeNodeNamer(cfg.Name)
+	nodeNamer := MakeNodeNamer(cfg.Name)
 	names := make([]string, len(cfg.Nodes))
 	for i, node := range cfg.Nodes {
 		name := nodeNamer(string(node.Role)) // name the node
@@ -50,7 +46,7 @@ func planCreation(cfg *config.Cluster, networkName string) (createContainerFuncs
 	}
 
 	// these apply to all container creation
-	genericArgs, err := commonArgs(cfg.Name, cfg, networkName, names)
+	genericArgs, err := commonArgs(cfg.Name, cfg, networkName, names, binaryName, dialect)
 	if err != nil {
 		return nil, err
 	}
@@ -76,7 +72,7 @@ func planCreation(cfg *config.Cluster, networkName string) (createContainerFuncs
 			if err != nil {
 				return err
 			}
-			return createContainer(name, args)
+			return CreateContainer(binaryName, name, args)
 		})
 	}
 
@@ -86,15 +82,8 @@ func planCreation(cfg *config.Cluster, networkName string) (createContainerFuncs
 		name := names[i]
 
 		// fixup relative paths, docker can only handle absolute paths
-		for m := range node.ExtraMounts {
-			hostPath := node.ExtraMounts[m].HostPath
-			if !fs.IsAbs(hostPath) {
-				absHostPath, err := filepath.Abs(hostPath)
-				if err != nil {
-					return nil, errors.Wrapf(err, "unable to resolve absolute path for hostPath: %q", hostPath)
-				}
-				node.ExtraMounts[m].HostPath = absHostPath
-			}
+		if err := MakeMountsAbsolute(node.ExtraMounts); err != nil {
+			return nil, err
 		}
 
 		// plan actual creation based on role
@@ -105,22 +94,28 @@ func planCreation(cfg *config.Cluster, networkName string) (createContainerFuncs
 					config.PortMapping{
 						ListenAddress: apiServerAddress,
 						HostPort:      apiServerPort,
-						ContainerPort: common.APIServerInternalPort,
+						ContainerPort: APIServerInternalPort,
 					},
 				)
-				args, err := runArgsForNode(node, cfg.Networking.IPFamily, name, genericArgs)
+				args, err := runArgsForNode(node, cfg.Networking.IPFamily, name, genericArgs, dialect)
 				if err != nil {
 					return err
 				}
-				return createContainerWithWaitUntilSystemdReachesMultiUserSystem(name, args)
+				if err := CreateContainerWithWaitUntilSystemdReachesMultiUserSystem(binaryName, name, args); err != nil {
+					return err
+				}
+				return connectExtraNetworks(binaryName, name, node, dialect)
 			})
 		case config.WorkerRole:
 			createContainerFuncs = append(createContainerFuncs, func() error {
-				args, err := runArgsForNode(node, cfg.Networking.IPFamily, name, genericArgs)
+				args, err := runArgsForNode(node, cfg.Networking.IPFamily, name, genericArgs, dialect)
 				if err != nil {
 					return err
 				}
-				return createContainerWithWaitUntilSystemdReachesMultiUserSystem(name, args)
+				if err := CreateContainerWithWaitUntilSystemdReachesMultiUserSystem(binaryName, name, args); err != nil {
+					return err
+				}
+				return connectExtraNetworks(binaryName, name, node, dialect)
 			})
 		default:
 			return nil, errors.Errorf("unknown node role: %q", node.Role)
@@ -130,13 +125,15 @@ func planCreation(cfg *config.Cluster, networkName string) (createContainerFuncs
 }
 
 // commonArgs computes static arguments that apply to all containers
-func commonArgs(cluster string, cfg *config.Cluster, networkName string, nodeNames []string) ([]string, error) {
+func commonArgs(cluster string, cfg *config.Cluster, networkName string, nodeNames []string, binaryName string, dialect *Dialect) ([]string, error) {
 	// standard arguments all nodes containers need, computed once
 	args := []string{
 		"--detach", // run the container detached
 		"--tty",    // allocate a tty for entrypoint logs
 		// label the node with the cluster ID
-		"--label", fmt.Sprintf("%s=%s", clusterLabelKey, cluster),
+		"--label", fmt.Sprintf("%s=%s", ClusterLabelKey, cluster),
+		// label the node with its primary network, nodes may have extra networks
+		"--label", fmt.Sprintf("%s=%s", NetworkLabelKey, networkName),
 		// user a user defined docker network so we get embedded DNS
 		"--net", networkName,
 		// Docker supports the following restart modes:
@@ -167,11 +164,14 @@ func commonArgs(cluster string, cfg *config.Cluster, networkName string, nodeNam
 		// this can be enabled by default in docker daemon.json, so we explicitly
 		// disable it, we want our entrypoint to be PID1, not docker-init / tini
 		"--init=false",
-		// note: requires API v1.41+ from Dec 2020 in Docker 20.10.0
-		// this is the default with cgroups v2 but not with cgroups v1, unless
-		// overridden in the daemon --default-cgroupns-mode
-		// https://github.com/docker/cli/pull/3699#issuecomment-1191675788
-		"--cgroupns=private",
+	}
+
+	// note: requires API v1.41+ from Dec 2020 in Docker 20.10.0
+	// this is the default with cgroups v2 but not with cgroups v1, unless
+	// overridden in the daemon --default-cgroupns-mode
+	// https://github.com/docker/cli/pull/3699#issuecomment-1191675788
+	if dialect.SupportsCgroupNS {
+		args = append(args, "--cgroupns=private")
 	}
 
 	// enable IPv6 if necessary
@@ -180,7 +180,7 @@ func commonArgs(cluster string, cfg *config.Cluster, networkName string, nodeNam
 	}
 
 	// pass proxy environment variables
-	proxyEnv, err := getProxyEnv(cfg, networkName, nodeNames)
+	proxyEnv, err := getProxyEnv(cfg, networkName, nodeNames, binaryName)
 	if err != nil {
 		return nil, errors.Wrap(err, "proxy setup error")
 	}
@@ -188,20 +188,22 @@ func commonArgs(cluster string, cfg *config.Cluster, networkName string, nodeNam
 		args = append(args, "-e", fmt.Sprintf("%s=%s", key, val))
 	}
 
-	// handle hosts that have user namespace remapping enabled
-	if usernsRemap() {
-		args = append(args, "--userns=host")
-	}
+	if dialect.HostStorageQuirks {
+		// handle hosts that have user namespace remapping enabled
+		if usernsRemap(binaryName) {
+			args = append(args, "--userns=host")
+		}
 
-	// handle Docker on Btrfs or ZFS
-	// https://github.com/kubernetes-sigs/kind/issues/1416#issuecomment-606514724
-	if mountDevMapper() {
-		args = append(args, "--volume", "/dev/mapper:/dev/mapper")
+		// handle Docker on Btrfs or ZFS
+		// https://github.com/kubernetes-sigs/kind/issues/1416#issuecomment-606514724
+		if mountDevMapper(binaryName) {
+			args = append(args, "--volume", "/dev/mapper:/dev/mapper")
+		}
 	}
 
 	// enable /dev/fuse explicitly for fuse-overlayfs
 	// (Rootless Docker does not automatically mount /dev/fuse with --privileged)
-	if mountFuse() {
+	if mountFuse(binaryName) {
 		args = append(args, "--device", "/dev/fuse")
 	}
 
@@ -209,14 +211,20 @@ func commonArgs(cluster string, cfg *config.Cluster, networkName string, nodeNam
 		args = append(args, "-e", "KIND_DNS_SEARCH="+strings.Join(*cfg.Networking.DNSSearch, " "))
 	}
 
+	// store the cluster labels and annotations on every node
+	args = append(args, GenerateClusterMetadataArgs(cfg.Labels, cfg.Annotations)...)
+
+	// record the allocated subnets of multi-cluster clusters on every node
+	args = append(args, GenerateSubnetLabelArgs(&cfg.Networking)...)
+
 	return args, nil
 }
 
-func runArgsForNode(node *config.Node, clusterIPFamily config.ClusterIPFamily, name string, args []string) ([]string, error) {
+func runArgsForNode(node *config.Node, clusterIPFamily config.ClusterIPFamily, name string, args []string, dialect *Dialect) ([]string, error) {
 	args = append([]string{
 		"--hostname", name, // make hostname match container name
 		// label the node with the role ID
-		"--label", fmt.Sprintf("%s=%s", nodeRoleLabelKey, node.Role),
+		"--label", fmt.Sprintf("%s=%s", NodeRoleLabelKey, node.Role),
 		// running containers in a container requires privileged
 		// NOTE: we could try to replicate this with --cap-add, and use less
 		// privileges, but this flag also changes some mounts that are necessary
@@ -243,8 +251,16 @@ func runArgsForNode(node *config.Node, clusterIPFamily config.ClusterIPFamily, n
 	)
 
 	// convert mounts and port mappings to container run args
-	args = append(args, generateMountBindings(node.ExtraMounts...)...)
-	mappingArgs, err := generatePortMappings(clusterIPFamily, node.ExtraPortMappings...)
+	args = append(args, GenerateStaticAddressArgs(node)...)
+	if !dialect.SupportsNetworkConnect {
+		networkArgs, err := GenerateExtraNetworkArgs(node.ExtraNetworks)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, networkArgs...)
+	}
+	args = append(args, GenerateMountBindings(node.ExtraMounts...)...)
+	mappingArgs, err := GeneratePortMappings(clusterIPFamily, node.ExtraPortMappings...)
 	if err != nil {
 		return nil, err
 	}
@@ -263,17 +279,17 @@ func runArgsForLoadBalancer(cfg *config.Cluster, name string, args []string) ([]
 	args = append([]string{
 		"--hostname", name, // make hostname match container name
 		// label the node with the role ID
-		"--label", fmt.Sprintf("%s=%s", nodeRoleLabelKey, constants.ExternalLoadBalancerNodeRoleValue),
+		"--label", fmt.Sprintf("%s=%s", NodeRoleLabelKey, constants.ExternalLoadBalancerNodeRoleValue),
 	},
 		args...,
 	)
 
 	// load balancer port mapping
-	mappingArgs, err := generatePortMappings(cfg.Networking.IPFamily,
+	mappingArgs, err := GeneratePortMappings(cfg.Networking.IPFamily,
 		config.PortMapping{
 			ListenAddress: cfg.Networking.APIServerAddress,
 			HostPort:      cfg.Networking.APIServerPort,
-			ContainerPort: common.APIServerInternalPort,
+			ContainerPort: APIServerInternalPort,
 		},
 	)
 	if err != nil {
@@ -289,16 +305,50 @@ func runArgsForLoadBalancer(cfg *config.Cluster, name string, args []string) ([]
 	return args, nil
 }
 
-func getProxyEnv(cfg *config.Cluster, networkName string, nodeNames []string) (map[string]string, error) {
-	envs := common.GetProxyEnvs(cfg)
+// RunArgsForPortForwarder computes the arguments for a port forwarder, which
+// runs the load balancer image configured to forward a single port
+func RunArgsForPortForwarder(cluster, networkName string, fwd *providers.PortForwarder) ([]string, error) {
+	args := []string{
+		"--detach",             // run the container detached
+		"--tty",                // allocate a tty for entrypoint logs
+		"--hostname", fwd.Name, // make hostname match container name
+		// attach to the target node's network
+		"--net", networkName,
+		// label the node with the cluster ID, so it is deleted with the cluster
+		"--label", fmt.Sprintf("%s=%s", ClusterLabelKey, cluster),
+		"--label", fmt.Sprintf("%s=%s", NetworkLabelKey, networkName),
+		// label the node with the role ID and the node it forwards to
+		"--label", fmt.Sprintf("%s=%s", NodeRoleLabelKey, constants.PortForwarderNodeRoleValue),
+		"--label", fmt.Sprintf("%s=%s", PortForwarderTargetLabelKey, fwd.Target),
+		// restart on host / runtime reboot like the nodes, see commonArgs
+		"--restart=on-failure:1",
+	}
+
+	// the listen address has already been defaulted by the caller
+	mappingArgs, err := GeneratePortMappings(config.IPv4Family, fwd.Mapping)
+	if err != nil {
+		return nil, err
+	}
+	args = append(args, mappingArgs...)
+
+	// finally, specify the image to run
+	args = append(args, loadbalancer.Image)
+
+	args = append(args, loadbalancer.GenerateForwarderBootstrapCommand(cluster, fwd.Name, fwd.LDSConfig, fwd.CDSConfig)...)
+
+	return args, nil
+}
+
+func getProxyEnv(cfg *config.Cluster, networkName string, nodeNames []string, binaryName string) (map[string]string, error) {
+	envs := GetProxyEnvs(cfg)
 	// Specifically add the docker network subnets to NO_PROXY if we are using a proxy
 	if len(envs) > 0 {
-		subnets, err := getSubnets(networkName)
+		subnets, err := getSubnets(networkName, binaryName)
 		if err != nil {
 			return nil, err
 		}
 
-		noProxyList := append(subnets, envs[common.NOProxy])
+		noProxyList := append(subnets, envs[NOProxy])
 		noProxyList = append(noProxyList, nodeNames...)
 		// Add pod and service dns names to no_proxy to allow in cluster
 		// Note: this is best effort based on the default CoreDNS spec
@@ -307,15 +357,15 @@ func getProxyEnv(cfg *config.Cluster, networkName string, nodeNames []string) (m
 		// are expected to be no-proxied by the user explicitly.
 		noProxyList = append(noProxyList, ".svc", ".svc.cluster", ".svc.cluster.local")
 		noProxyJoined := strings.Join(noProxyList, ",")
-		envs[common.NOProxy] = noProxyJoined
-		envs[strings.ToLower(common.NOProxy)] = noProxyJoined
+		envs[NOProxy] = noProxyJoined
+		envs[strings.ToLower(NOProxy)] = noProxyJoined
 	}
 	return envs, nil
 }
 
-func getSubnets(networkName string) ([]string, error) {
+func getSubnets(networkName, binaryName string) ([]string, error) {
 	format := `{{range (index (index . "IPAM") "Config")}}{{index . "Subnet"}} {{end}}`
-	cmd := exec.Command("docker", "network", "inspect", "-f", format, networkName)
+	cmd := exec.Command(binaryName, "network", "inspect", "-f", format, networkName)
 	lines, err := exec.OutputLines(cmd)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to get subnets")
@@ -323,100 +373,11 @@ func getSubnets(networkName string) ([]string, error) {
 	return strings.Split(strings.TrimSpace(lines[0]), " "), nil
 }
 
-// generateMountBindings converts the mount list to a list of args for docker
-// '<HostPath>:<ContainerPath>[:options]', where 'options'
-// is a comma-separated list of the following strings:
-// 'ro', if the path is read only
-// 'Z', if the volume requires SELinux relabeling
-func generateMountBindings(mounts ...config.Mount) []string {
-	args := make([]string, 0, len(mounts))
-	for _, m := range mounts {
-		bind := fmt.Sprintf("%s:%s", m.HostPath, m.ContainerPath)
-		var attrs []string
-		if m.Readonly {
-			attrs = append(attrs, "ro")
-		}
-		// Only request relabeling if the pod provides an SELinux context. If the pod
-		// does not provide an SELinux context relabeling will label the volume with
-		// the container's randomly allocated MCS label. This would restrict access
-		// to the volume to the container which mounts it first.
-		if m.SelinuxRelabel {
-			attrs = append(attrs, "Z")
-		}
-		switch m.Propagation {
-		case config.MountPropagationNone:
-			// noop, private is default
-		case config.MountPropagationBidirectional:
-			attrs = append(attrs, "rshared")
-		case config.MountPropagationHostToContainer:
-			attrs = append(attrs, "rslave")
-		default: // Falls back to "private"
-		}
-		if len(attrs) > 0 {
-			bind = fmt.Sprintf("%s:%s", bind, strings.Join(attrs, ","))
-		}
-		args = append(args, fmt.Sprintf("--volume=%s", bind))
-	}
-	return args
-}
-
-// generatePortMappings converts the portMappings list to a list of args for docker
-func generatePortMappings(clusterIPFamily config.ClusterIPFamily, portMappings ...config.PortMapping) ([]string, error) {
-	args := make([]string, 0, len(portMappings))
-	for _, pm := range portMappings {
-		// do provider internal defaulting
-		// in a future API revision we will handle this at the API level and remove this
-		if pm.ListenAddress == "" {
-			switch clusterIPFamily {
-			case config.IPv4Family, config.DualStackFamily:
-				pm.ListenAddress = "0.0.0.0" // this is the docker default anyhow
-			case config.IPv6Family:
-				pm.ListenAddress = "::"
-			default:
-				return nil, errors.Errorf("unknown cluster IP family: %v", clusterIPFamily)
-			}
-		}
-		if string(pm.Protocol) == "" {
-			pm.Protocol = config.PortMappingProtocolTCP // TCP is the default
-		}
-
-		// validate that the provider can handle this binding
-		switch pm.Protocol {
-		case config.PortMappingProtocolTCP:
-		case config.PortMappingProtocolUDP:
-		case config.PortMappingProtocolSCTP:
-		default:
-			return nil, errors.Errorf("unknown port mapping protocol: %v", pm.Protocol)
-		}
-
-		// get a random port if necessary (port = 0)
-		hostPort, releaseHostPortFn, err := common.PortOrGetFreePort(pm.HostPort, pm.ListenAddress)
-		if err != nil {
-			return nil, errors.Wrap(err, "failed to get random host port for port mapping")
-		}
-		if releaseHostPortFn != nil {
-			defer releaseHostPortFn()
-		}
-
-		// generate the actual mapping arg
-		protocol := string(pm.Protocol)
-		hostPortBinding := net.JoinHostPort(pm.ListenAddress, fmt.Sprintf("%d", hostPort))
-		args = append(args, fmt.Sprintf("--publish=%s:%d/%s", hostPortBinding, pm.ContainerPort, protocol))
-	}
-	return args, nil
-}
-
-func createContainer(name string, args []string) error {
-	return exec.Command("docker", append([]string{"run", "--name", name}, args...)...).Run()
-}
-
-func createContainerWithWaitUntilSystemdReachesMultiUserSystem(name string, args []string) error {
-	if err := exec.Command("docker", append([]string{"run", "--name", name}, args...)...).Run(); err != nil {
-		return err
+// connectExtraNetworks connects the node to its extra networks, if the
+// dialect did not already attach them at creation
+func connectExtraNetworks(binaryName, name string, node *config.Node, dialect *Dialect) error {
+	if !dialect.SupportsNetworkConnect {
+		return nil
 	}
-
-	logCtx, logCancel := context.WithTimeout(context.Background(), 30*time.Second)
-	logCmd := exec.CommandContext(logCtx, "docker", "logs", "-f", name)
-	defer logCancel()
-	return common.WaitUntilLogRegexpMatches(logCtx, logCmd, common.NodeReachedCgroupsReadyRegexp())
+	return ConnectExtraNetworks(binaryName, name, node.ExtraNetworks)
 }
@@ -0,0 +1,65 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generic
+
+import (
+	"sigs.k8s.io/kind/pkg/errors"
+
+	"sigs.k8s.io/kind/pkg/cluster/internal/providers/common"
+)
+
+// DockerDialect returns the Dialect spoken by the docker CLI
+func DockerDialect() common.Dialect {
+	return common.Dialect{
+		Name:                 "docker",
+		LabelFilterSeparator: "=",
+		NetworkCreateArgs: []string{
+			"-o", "com.docker.network.bridge.enable_ip_masquerade=true",
+		},
+		NetworkMTUOption:        "com.docker.network.driver.mtu",
+		NetworkEnv:              "KIND_EXPERIMENTAL_DOCKER_NETWORK",
+		RemoveDuplicateNetworks: true,
+		SupportsCgroupNS:        true,
+		SupportsNetworkConnect:  true,
+		HostStorageQuirks:       true,
+	}
+}
+
+// NerdctlDialect returns the Dialect spoken by the nerdctl CLI
+func NerdctlDialect() common.Dialect {
+	return common.Dialect{
+		Name:                 "nerdctl",
+		LabelFilterSeparator: "=",
+		NetworkMTUOption:     "com.docker.network.driver.mtu",
+		// xref: https://github.com/containerd/nerdctl/issues/2908
+		SerialCreate:        true,
+		ContainerImageField: ".Image",
+		StopBeforeDelete:    true,
+	}
+}
+
+// DialectForName returns the known Dialect with name
+func DialectForName(name string) (common.Dialect, error) {
+	switch name {
+	case "", "docker":
+		return DockerDialect(), nil
+	case "nerdctl":
+		return NerdctlDialect(), nil
+	default:
+		return common.Dialect{}, errors.Errorf("unknown CLI dialect: %q", name)
+	}
+}
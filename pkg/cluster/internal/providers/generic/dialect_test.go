@@ -1,5 +1,5 @@
 /*
-Copyright 2019 The Kubernetes Authors.
+Copyright The Kubernetes Authors.
 
 Licensed under the Apache License, Version 2.0 (the "License");
 you may not use this file except in compliance with the License.
@@ -14,12 +14,19 @@ See the License for the specific language governing permissions and
 limitations under the License.
 */
 
-// Package docker is the implementation for the docker kind provider.
-package docker
+package generic
 
-// clusterLabelKey is applied to each "node" docker container for identification
-const clusterLabelKey = "io.x-k8s.kind.cluster"
+import (
+	"testing"
+)
 
-// nodeRoleLabelKey is applied to each "node" docker container for categorization
-// of nodes by role
-const nodeRoleLabelKey = "io.x-k8s.kind.role"
+func TestDialectForName(t *testing.T) {
+	for _, name := range []string{"", "docker", "nerdctl"} {
+		if _, err := DialectForName(name); err != nil {
+			t.Errorf("unexpected error for %q: %v", name, err)
+		}
+	}
+	if _, err := DialectForName("bogus"); err == nil {
+		t.Errorf("expected error for unknown dialect")
+	}
+}
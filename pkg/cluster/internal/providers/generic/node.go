@@ -1,5 +1,5 @@
 /*
-Copyright 2019 The Kubernetes Authors.
+Copyright The Kubernetes Authors.
 
 Licensed under the Apache License, Version 2.0 (the "License");
 you may not use this file except in compliance with the License.
@@ -14,19 +14,21 @@ See the License for the specific language governing permissions and
 limitations under the License.
 */
 
-package nerdctl
+package generic
 
 import (
 	"context"
 	"fmt"
 	"io"
-	"strings"
+	"time"
 
 	"sigs.k8s.io/kind/pkg/errors"
 	"sigs.k8s.io/kind/pkg/exec"
+
+	"sigs.k8s.io/kind/pkg/cluster/internal/providers/common"
 )
 
-// nodes.Node implementation for the docker provider
+// nodes.Node implementation for the generic provider
 type node struct {
 	name       string
 	binaryName string
@@ -38,7 +40,7 @@ func (n *node) String() string {
 
 func (n *node) Role() (string, error) {
 	cmd := exec.Command(n.binaryName, "inspect",
-		"--format", fmt.Sprintf(`{{ index .Config.Labels "%s"}}`, nodeRoleLabelKey),
+		"--format", fmt.Sprintf(`{{ index .Config.Labels "%s"}}`, common.NodeRoleLabelKey),
 		n.name,
 	)
 	lines, err := exec.OutputLines(cmd)
@@ -52,9 +54,9 @@ func (n *node) Role() (string, error) {
 }
 
 func (n *node) IP() (ipv4 string, ipv6 string, err error) {
-	// retrieve the IP address of the node using docker inspect
+	// retrieve the IP address of the node's primary network using docker inspect
 	cmd := exec.Command(n.binaryName, "inspect",
-		"-f", "{{range .NetworkSettings.Networks}}{{.IPAddress}},{{.GlobalIPv6Address}}{{end}}",
+		"-f", common.NodeIPInspectFormat(common.NetworkLabelKey),
 		n.name, // ... against the "node" container
 	)
 	lines, err := exec.OutputLines(cmd)
@@ -64,11 +66,7 @@ func (n *node) IP() (ipv4 string, ipv6 string, err error) {
 	if len(lines) != 1 {
 		return "", "", errors.Errorf("file should only be one line, got %d lines", len(lines))
 	}
-	ips := strings.Split(lines[0], ",")
-	if len(ips) != 2 {
-		return "", "", errors.Errorf("container addresses should have 2 values, got %d values", len(ips))
-	}
-	return ips[0], ips[1], nil
+	return common.ParseNodeIPs(lines[0])
 }
 
 func (n *node) Command(command string, args ...string) exec.Cmd {
@@ -90,7 +88,7 @@ func (n *node) CommandContext(ctx context.Context, command string, args ...strin
 	}
 }
 
-// nodeCmd implements exec.Cmd for docker nodes
+// nodeCmd implements exec.Cmd for generic provider nodes
 type nodeCmd struct {
 	binaryName string
 	nameOrID   string // the container name or ID
@@ -98,6 +96,7 @@ type nodeCmd struct {
 	args       []string
 	env        []string
 	stdin      io.Reader
+	tty        bool
 	stdout     io.Writer
 	stderr     io.Writer
 	ctx        context.Context
@@ -116,6 +115,11 @@ func (c *nodeCmd) Run() error {
 			"-i", // interactive so we can supply input
 		)
 	}
+	if c.tty {
+		args = append(args,
+			"-t", // allocate a pseudo-TTY, E.G. for interactive shells
+		)
+	}
 	// set env
 	for _, env := range c.env {
 		args = append(args, "-e", env)
@@ -170,6 +174,21 @@ func (c *nodeCmd) SetStderr(w io.Writer) exec.Cmd {
 	return c
 }
 
+// SetTTY sets whether to allocate a pseudo-TTY for the command
+func (c *nodeCmd) SetTTY(tty bool) exec.Cmd {
+	c.tty = tty
+	return c
+}
+
 func (n *node) SerialLogs(w io.Writer) error {
 	return exec.Command(n.binaryName, "logs", n.name).SetStdout(w).SetStderr(w).Run()
 }
+
+// StreamSerialLogs writes the node container logs with timestamps to w,
+// following them if follow is true, starting since ago if non-zero
+func (n *node) StreamSerialLogs(w io.Writer, follow bool, since time.Duration) error {
+	args := common.SerialLogsArgs(n.name, follow, since)
+	// the logs may be followed indefinitely, so do not keep a copy of them
+	out := exec.Uncaptured(w)
+	return exec.Command(n.binaryName, args...).SetStdout(out).SetStderr(out).Run()
+}
@@ -0,0 +1,447 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generic
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"sigs.k8s.io/kind/pkg/cluster/nodes"
+	"sigs.k8s.io/kind/pkg/errors"
+	"sigs.k8s.io/kind/pkg/exec"
+	"sigs.k8s.io/kind/pkg/log"
+
+	"sigs.k8s.io/kind/pkg/cluster/internal/providers"
+	"sigs.k8s.io/kind/pkg/cluster/internal/providers/common"
+	"sigs.k8s.io/kind/pkg/cluster/nodeutils"
+	"sigs.k8s.io/kind/pkg/internal/apis/config"
+	"sigs.k8s.io/kind/pkg/internal/cli"
+	"sigs.k8s.io/kind/pkg/internal/sets"
+)
+
+// NewProvider returns a new provider based on executing the docker CLI
+// compatible binary at binaryPath, speaking dialect
+func NewProvider(logger log.Logger, binaryPath string, dialect common.Dialect) providers.Provider {
+	return &provider{
+		logger:     logger,
+		binaryName: binaryPath,
+		dialect:    dialect,
+	}
+}
+
+// Provider implements provider.Provider
+// see NewProvider
+type provider struct {
+	logger     log.Logger
+	binaryName string
+	dialect    common.Dialect
+	info       *providers.ProviderInfo
+}
+
+// String implements fmt.Stringer
+// NOTE: the value of this should not currently be relied upon for anything!
+// This is only used for setting the Node's providerID
+func (p *provider) String() string {
+	if p.dialect.Name == "" {
+		return "generic"
+	}
+	return p.dialect.Name
+}
+
+// Binary returns the docker CLI compatible binary used by this provider
+func (p *provider) Binary() string {
+	return p.binaryName
+}
+
+// Provision is part of the providers.Provider interface
+func (p *provider) Provision(status *cli.Status, cfg *config.Cluster) (err error) {
+	// TODO: validate cfg
+	// ensure node images are pulled before actually provisioning
+	if err := ensureNodeImages(p.logger, status, cfg, p.Binary()); err != nil {
+		return err
+	}
+
+	// ensure the pre-requisite network exists
+	networkName := p.NetworkName(cfg)
+	if cfg.Networking.ContainerNetwork.Name == "" && networkName != fixedNetworkName {
+		p.logger.Warnf("WARNING: Overriding network due to %s", p.dialect.NetworkEnv)
+		p.logger.Warn("WARNING: Here be dragons! This is not supported currently.")
+	}
+	if err := common.EnsureNetwork(p.Binary(), &p.dialect, networkName, &cfg.Networking.ContainerNetwork, config.ClusterHasIPv6(cfg)); err != nil {
+		return errors.Wrap(err, "failed to ensure network")
+	}
+
+	// static node addresses must fit the network, which may already have existed
+	if common.HasStaticAddresses(cfg.Nodes) {
+		settings, err := common.GetNetworkSettings(p.Binary(), &p.dialect, networkName)
+		if err != nil {
+			return err
+		}
+		if err := common.ValidateNodeAddresses(networkName, cfg.Nodes, settings); err != nil {
+			return err
+		}
+	}
+
+	// as must static extraNetworks addresses, which kind does not create
+	if err := common.ValidateExtraNetworkAddresses(cfg.Nodes, func(name string) (*common.NetworkSettings, error) {
+		return common.GetNetworkSettings(p.Binary(), &p.dialect, name)
+	}); err != nil {
+		return err
+	}
+
+	// actually provision the cluster
+	icons := strings.Repeat("📦 ", len(cfg.Nodes))
+	status.Start(fmt.Sprintf("Preparing nodes %s", icons))
+	defer func() { status.End(err == nil) }()
+
+	// plan creating the containers
+	createContainerFuncs, err := common.PlanCreation(p.Binary(), &p.dialect, cfg, networkName)
+	if err != nil {
+		return err
+	}
+
+	// actually create nodes
+	if p.dialect.SerialCreate {
+		for _, f := range createContainerFuncs {
+			if err := f(); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return errors.UntilErrorConcurrent(createContainerFuncs)
+}
+
+// ListClusters is part of the providers.Provider interface
+func (p *provider) ListClusters() ([]string, error) {
+	cmd := exec.Command(p.Binary(),
+		"ps",
+		"-a", // show stopped nodes
+		// filter for nodes with the cluster label
+		"--filter", p.dialect.LabelFilter(common.ClusterLabelKey, ""),
+		// format to include the cluster name
+		"--format", fmt.Sprintf(`{{.Label "%s"}}`, common.ClusterLabelKey),
+	)
+	lines, err := exec.OutputLines(cmd)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list clusters")
+	}
+	return sets.NewString(lines...).List(), nil
+}
+
+// ListNodes is part of the providers.Provider interface
+func (p *provider) ListNodes(cluster string) ([]nodes.Node, error) {
+	cmd := exec.Command(p.Binary(),
+		"ps",
+		"-a", // show stopped nodes
+		// filter for nodes with the cluster label
+		"--filter", p.dialect.LabelFilter(common.ClusterLabelKey, cluster),
+		// format to include the cluster name
+		"--format", `{{.Names}}`,
+	)
+	lines, err := exec.OutputLines(cmd)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list nodes")
+	}
+	length := len(lines)
+	// convert names to node handles
+	ret := make([]nodes.Node, 0, length)
+	for _, name := range lines {
+		if name != "" {
+			ret = append(ret, p.node(name))
+		}
+	}
+	return ret, nil
+}
+
+// GetClusterMetadata is part of the providers.Provider interface
+func (p *provider) GetClusterMetadata(cluster string) (*providers.ClusterMetadata, error) {
+	n, err := p.ListNodes(cluster)
+	if err != nil {
+		return nil, err
+	}
+	if len(n) == 0 {
+		return nil, errors.Errorf("no nodes found for cluster %q", cluster)
+	}
+	args := []string{"inspect", "--format", common.ClusterMetadataInspectFormat}
+	for _, node := range n {
+		args = append(args, node.String())
+	}
+	lines, err := exec.OutputLines(exec.Command(p.Binary(), args...))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to inspect nodes")
+	}
+	return common.ParseClusterMetadata(lines)
+}
+
+// ListImages is part of the providers.Provider interface
+func (p *provider) ListImages(reference string) ([]string, error) {
+	return common.ListImages(p.Binary(), reference)
+}
+
+// ImageID is part of the providers.Provider interface
+func (p *provider) ImageID(image string) (string, error) {
+	return common.ImageID(p.Binary(), image)
+}
+
+// SaveImages is part of the providers.Provider interface
+func (p *provider) SaveImages(w io.Writer, images ...string) error {
+	return common.SaveImages(p.Binary(), w, images...)
+}
+
+// GetNodeContainers is part of the providers.Provider interface
+func (p *provider) GetNodeContainers(n []nodes.Node) ([]providers.NodeContainer, error) {
+	if len(n) == 0 {
+		return []providers.NodeContainer{}, nil
+	}
+	args := []string{"inspect", "--format", common.NodeContainerInspectFormat(p.dialect.ImageField())}
+	names := make([]string, 0, len(n))
+	for _, node := range n {
+		args = append(args, node.String())
+		names = append(names, node.String())
+	}
+	lines, err := exec.OutputLines(exec.Command(p.Binary(), args...))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to inspect nodes")
+	}
+	return common.ParseNodeContainers(lines, names)
+}
+
+// CreatePortForwarder is part of the providers.Provider interface
+func (p *provider) CreatePortForwarder(cluster string, fwd *providers.PortForwarder) (nodes.Node, error) {
+	networkName, err := common.PrimaryNetwork(p.Binary(), common.NetworkLabelKey, fwd.Target)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get network of node %q", fwd.Target)
+	}
+	args, err := common.RunArgsForPortForwarder(cluster, networkName, fwd)
+	if err != nil {
+		return nil, err
+	}
+	if err := common.CreateContainer(p.Binary(), fwd.Name, args); err != nil {
+		return nil, errors.Wrap(err, "failed to create port forwarder")
+	}
+	return p.node(fwd.Name), nil
+}
+
+// DeleteNodes is part of the providers.Provider interface
+func (p *provider) DeleteNodes(n []nodes.Node) error {
+	if len(n) == 0 {
+		return nil
+	}
+	if !p.dialect.StopBeforeDelete {
+		args := make([]string, 0, len(n)+3) // allocate once
+		args = append(args,
+			"rm",
+			"-f", // force the container to be delete now
+			"-v", // delete volumes
+		)
+		for _, node := range n {
+			args = append(args, node.String())
+		}
+		if err := exec.Command(p.Binary(), args...).Run(); err != nil {
+			return errors.Wrap(err, "failed to delete nodes")
+		}
+		return nil
+	}
+	argsNoRestart := make([]string, 0, len(n)+2)
+	argsNoRestart = append(argsNoRestart,
+		"update",
+		"--restart=no",
+	)
+	argsStop := make([]string, 0, len(n)+1)
+	argsStop = append(argsStop, "stop")
+	argsWait := make([]string, 0, len(n)+1)
+	argsWait = append(argsWait, "wait")
+
+	argsRm := make([]string, 0, len(n)+3) // allocate once
+	argsRm = append(argsRm,
+		"rm",
+		"-f",
+		"-v", // delete volumes
+	)
+	for _, node := range n {
+		argsRm = append(argsRm, node.String())
+		argsStop = append(argsStop, node.String())
+		argsWait = append(argsWait, node.String())
+		argsNoRestart = append(argsNoRestart, node.String())
+	}
+	if err := exec.Command(p.Binary(), argsNoRestart...).Run(); err != nil {
+		return errors.Wrap(err, "failed to update restart policy to 'no'")
+	}
+	if err := exec.Command(p.Binary(), argsStop...).Run(); err != nil {
+		return errors.Wrap(err, "failed to stop nodes")
+	}
+	if err := exec.Command(p.Binary(), argsWait...).Run(); err != nil {
+		return errors.Wrap(err, "failed to wait for node exit")
+	}
+	if err := exec.Command(p.Binary(), argsRm...).Run(); err != nil {
+		return errors.Wrap(err, "failed to delete nodes")
+	}
+	return nil
+}
+
+// GetAPIServerEndpoint is part of the providers.Provider interface
+func (p *provider) GetAPIServerEndpoint(cluster string) (string, error) {
+	// locate the node that hosts this
+	allNodes, err := p.ListNodes(cluster)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to list nodes")
+	}
+	n, err := nodeutils.APIServerEndpointNode(allNodes)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get api server endpoint")
+	}
+
+	// if the 'desktop.docker.io/ports/<PORT>/tcp' label is present,
+	// defer to its value for the api server endpoint
+	//
+	// For example:
+	// "Labels": {
+	// 	"desktop.docker.io/ports/6443/tcp": "10.0.1.7:6443",
+	// }
+	cmd := exec.Command(
+		p.Binary(), "inspect",
+		"--format", fmt.Sprintf(
+			"{{ index .Config.Labels \"desktop.docker.io/ports/%d/tcp\" }}", common.APIServerInternalPort,
+		),
+		n.String(),
+	)
+	lines, err := exec.OutputLines(cmd)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get api server port")
+	}
+	if len(lines) == 1 && lines[0] != "" {
+		return lines[0], nil
+	}
+
+	// else, retrieve the specific port mapping via NetworkSettings.Ports
+	cmd = exec.Command(
+		p.Binary(), "inspect",
+		"--format", fmt.Sprintf(
+			"{{ with (index (index .NetworkSettings.Ports \"%d/tcp\") 0) }}{{ printf \"%%s\t%%s\" .HostIp .HostPort }}{{ end }}", common.APIServerInternalPort,
+		),
+		n.String(),
+	)
+	lines, err = exec.OutputLines(cmd)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get api server port")
+	}
+	if len(lines) != 1 {
+		return "", errors.Errorf("network details should only be one line, got %d lines", len(lines))
+	}
+	parts := strings.Split(lines[0], "\t")
+	if len(parts) != 2 {
+		return "", errors.Errorf("network details should only be two parts, got %d", len(parts))
+	}
+
+	// join host and port
+	return net.JoinHostPort(parts[0], parts[1]), nil
+}
+
+// GetAPIServerInternalEndpoint is part of the providers.Provider interface
+func (p *provider) GetAPIServerInternalEndpoint(cluster string) (string, error) {
+	// locate the node that hosts this
+	allNodes, err := p.ListNodes(cluster)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to list nodes")
+	}
+	n, err := nodeutils.APIServerEndpointNode(allNodes)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get api server endpoint")
+	}
+	// NOTE: we're using the nodes's hostnames which are their names
+	return net.JoinHostPort(n.String(), fmt.Sprintf("%d", common.APIServerInternalPort)), nil
+}
+
+// node returns a new node handle for this provider
+func (p *provider) node(name string) nodes.Node {
+	return &node{
+		binaryName: p.binaryName,
+		name:       name,
+	}
+}
+
+// CollectLogs will populate dir with cluster logs and other debug files
+func (p *provider) CollectLogs(dir string, nodes []nodes.Node) error {
+	execToPathFn := func(cmd exec.Cmd, path string) func() error {
+		return func() error {
+			f, err := common.FileOnHost(path)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			return cmd.SetStdout(f).SetStderr(f).Run()
+		}
+	}
+	// construct a slice of methods to collect logs
+	fns := []func() error{
+		// record info about the host runtime
+		execToPathFn(
+			exec.Command(p.Binary(), "info"),
+			filepath.Join(dir, "docker-info.txt"),
+		),
+	}
+	// inspect each node
+	for _, n := range nodes {
+		node := n // https://golang.org/doc/faq#closures_and_goroutines
+		name := node.String()
+		path := filepath.Join(dir, name)
+		fns = append(fns,
+			execToPathFn(exec.Command(p.Binary(), "inspect", name), filepath.Join(path, "inspect.json")),
+		)
+	}
+	// run and collect up all errors
+	return errors.AggregateConcurrent(fns)
+}
+
+// NetworkName is part of the providers.Provider interface
+func (p *provider) NetworkName(cfg *config.Cluster) string {
+	if n := cfg.Networking.ContainerNetwork.Name; n != "" {
+		return n
+	}
+	if n := os.Getenv(p.dialect.NetworkEnv); p.dialect.NetworkEnv != "" && n != "" {
+		return n
+	}
+	return fixedNetworkName
+}
+
+// NetworkSubnets is part of the providers.Provider interface
+func (p *provider) NetworkSubnets(cfg *config.Cluster) ([]string, error) {
+	settings, err := common.GetNetworkSettings(p.Binary(), &p.dialect, p.NetworkName(cfg))
+	if err != nil {
+		return nil, err
+	}
+	subnets := []string{}
+	for _, subnet := range settings.Subnets {
+		subnets = append(subnets, subnet.Subnet)
+	}
+	return subnets, nil
+}
+
+// Info returns the provider info.
+// The info is cached on the first time of the execution.
+func (p *provider) Info() (*providers.ProviderInfo, error) {
+	var err error
+	if p.info == nil {
+		p.info, err = common.Info(p.Binary())
+	}
+	return p.info, err
+}
@@ -37,16 +37,3 @@ func IsAvailable() bool {
 	}
 	return strings.HasPrefix(lines[0], "nerdctl version")
 }
-
-// rootless: use fuse-overlayfs by default
-// https://github.com/kubernetes-sigs/kind/issues/2275
-func mountFuse(binaryName string) bool {
-	i, err := info(binaryName)
-	if err != nil {
-		return false
-	}
-	if i != nil && i.Rootless {
-		return true
-	}
-	return false
-}
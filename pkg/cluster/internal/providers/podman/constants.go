@@ -23,3 +23,7 @@ const clusterLabelKey = "io.x-k8s.kind.cluster"
 // nodeRoleLabelKey is applied to each "node" podman container for categorization
 // of nodes by role
 const nodeRoleLabelKey = "io.x-k8s.kind.role"
+
+// networkLabelKey is applied to each "node" podman container to record the primary
+// network, which is used for the node's addresses
+const networkLabelKey = "io.x-k8s.kind.network"
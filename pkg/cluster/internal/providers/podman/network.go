@@ -17,17 +17,19 @@ limitations under the License.
 package podman
 
 import (
-	"crypto/sha1"
-	"encoding/binary"
-	"net"
+	"fmt"
 	"regexp"
 	"strings"
 
 	"sigs.k8s.io/kind/pkg/errors"
 	"sigs.k8s.io/kind/pkg/exec"
+
+	"sigs.k8s.io/kind/pkg/cluster/internal/providers/common"
+	"sigs.k8s.io/kind/pkg/internal/apis/config"
 )
 
-// This may be overridden by KIND_EXPERIMENTAL_PODMAN_NETWORK env,
+// This may be overridden by networking.containerNetwork.name in the cluster
+// config, or by KIND_EXPERIMENTAL_PODMAN_NETWORK env,
 // experimentally...
 //
 // By default currently picking a single network is equivalent to the previous
@@ -40,19 +42,25 @@ import (
 // networks.
 const fixedNetworkName = "kind"
 
-// ensureNetwork creates a new network
+// ensureNetwork creates a new network with the requested settings, or
+// validates the settings of the existing network
 // podman only creates IPv6 networks for versions >= 2.2.0
-func ensureNetwork(name string) error {
-	// network already exists
+func ensureNetwork(name string, requested *config.ContainerNetwork, requireIPv6 bool) error {
+	// network already exists, we're good if it matches
 	if checkIfNetworkExists(name) {
-		return nil
+		return validateExistingNetwork(name, requested, requireIPv6)
+	}
+
+	// an explicitly requested IPv6 subnet gets exactly one attempt
+	if requested.IPv6Subnet != "" {
+		return createNetwork(name, requested, requested.IPv6Subnet)
 	}
 
 	// generate unique subnet per network based on the name
 	// obtained from the ULA fc00::/8 range
 	// Make N attempts with "probing" in case we happen to collide
-	subnet := generateULASubnetFromName(name, 0)
-	err := createNetwork(name, subnet)
+	subnet := common.GenerateULASubnetFromName(name, 0)
+	err := createNetwork(name, requested, subnet)
 	if err == nil {
 		// Success!
 		return nil
@@ -60,7 +68,7 @@ func ensureNetwork(name string) error {
 
 	if isUnknownIPv6FlagError(err) ||
 		isIPv6DisabledError(err) {
-		return createNetwork(name, "")
+		return createNetwork(name, requested, "")
 	}
 
 	// Only continue if the error is because of the subnet range
@@ -72,8 +80,8 @@ func ensureNetwork(name string) error {
 	// keep trying for ipv6 subnets
 	const maxAttempts = 5
 	for attempt := int32(1); attempt < maxAttempts; attempt++ {
-		subnet := generateULASubnetFromName(name, attempt)
-		err = createNetwork(name, subnet)
+		subnet := common.GenerateULASubnetFromName(name, attempt)
+		err = createNetwork(name, requested, subnet)
 		if err == nil {
 			// success!
 			return nil
@@ -86,12 +94,24 @@ func ensureNetwork(name string) error {
 
 }
 
-func createNetwork(name, ipv6Subnet string) error {
-	if ipv6Subnet == "" {
-		return exec.Command("podman", "network", "create", "-d=bridge", name).Run()
+// validateExistingNetwork checks that the existing network by name is
+// compatible with the requested settings
+func validateExistingNetwork(name string, requested *config.ContainerNetwork, requireIPv6 bool) error {
+	existing, err := getNetworkSettings(name)
+	if err != nil {
+		return err
+	}
+	return common.ValidateExistingNetwork(name, requested, existing, requireIPv6)
+}
+
+func createNetwork(name string, requested *config.ContainerNetwork, ipv6Subnet string) error {
+	args := []string{"network", "create", "-d=bridge"}
+	if requested.MTU > 0 {
+		args = append(args, "-o", fmt.Sprintf("mtu=%d", requested.MTU))
 	}
-	return exec.Command("podman", "network", "create", "-d=bridge",
-		"--ipv6", "--subnet", ipv6Subnet, name).Run()
+	args = append(args, common.NetworkSubnetArgs(requested, ipv6Subnet)...)
+	args = append(args, name)
+	return exec.Command("podman", args...).Run()
 }
 
 func checkIfNetworkExists(name string) bool {
@@ -124,23 +144,3 @@ func isPoolOverlapError(err error) bool {
 		strings.Contains(output, "is being used by a network interface") ||
 		strings.Contains(output, "is already being used by a cni configuration")
 }
-
-// generateULASubnetFromName generate an IPv6 subnet based on the
-// name and Nth probing attempt
-func generateULASubnetFromName(name string, attempt int32) string {
-	ip := make([]byte, 16)
-	ip[0] = 0xfc
-	ip[1] = 0x00
-	h := sha1.New()
-	_, _ = h.Write([]byte(name))
-	_ = binary.Write(h, binary.LittleEndian, attempt)
-	bs := h.Sum(nil)
-	for i := 2; i < 8; i++ {
-		ip[i] = bs[i]
-	}
-	subnet := &net.IPNet{
-		IP:   net.IP(ip),
-		Mask: net.CIDRMask(64, 128),
-	}
-	return subnet.String()
-}
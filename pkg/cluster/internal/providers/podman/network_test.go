@@ -0,0 +1,64 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package podman
+
+import (
+	"testing"
+
+	"sigs.k8s.io/kind/pkg/cluster/internal/providers/common"
+	"sigs.k8s.io/kind/pkg/internal/assert"
+)
+
+func Test_parseNetworkInspect(t *testing.T) {
+	cases := []struct {
+		name     string
+		out      string
+		expected *common.NetworkSettings
+	}{
+		{
+			name: "v4+",
+			out:  `[{"name":"kind","subnets":[{"subnet":"10.89.0.0/24","gateway":"10.89.0.1","lease_range":{"start_ip":"10.89.0.128","end_ip":"10.89.0.255"}},{"subnet":"fc00:f853:ccd:e793::/64","gateway":"fc00:f853:ccd:e793::1"}],"options":{"mtu":"1400"}}]`,
+			expected: &common.NetworkSettings{
+				Subnets: []common.NetworkSubnet{
+					{Subnet: "10.89.0.0/24", Gateway: "10.89.0.1", RangeStart: "10.89.0.128", RangeEnd: "10.89.0.255"},
+					{Subnet: "fc00:f853:ccd:e793::/64", Gateway: "fc00:f853:ccd:e793::1"},
+				},
+				MTU: 1400,
+			},
+		},
+		{
+			name: "v3 CNI",
+			out:  `[{"cniVersion":"0.4.0","name":"kind","plugins":[{"type":"bridge","mtu":1500,"ipam":{"ranges":[[{"gateway":"10.89.0.1","subnet":"10.89.0.0/24","rangeStart":"10.89.0.2","rangeEnd":"10.89.0.127"}]]}}]}]`,
+			expected: &common.NetworkSettings{
+				Subnets: []common.NetworkSubnet{
+					{Subnet: "10.89.0.0/24", Gateway: "10.89.0.1", RangeStart: "10.89.0.2", RangeEnd: "10.89.0.127"},
+				},
+				MTU: 1500,
+			},
+		},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			settings, err := parseNetworkInspect([]byte(tc.out))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			assert.DeepEqual(t, tc.expected, settings)
+		})
+	}
+}
@@ -20,10 +20,12 @@ import (
 	"context"
 	"fmt"
 	"io"
-	"strings"
+	"time"
 
 	"sigs.k8s.io/kind/pkg/errors"
 	"sigs.k8s.io/kind/pkg/exec"
+
+	"sigs.k8s.io/kind/pkg/cluster/internal/providers/common"
 )
 
 // nodes.Node implementation for the podman provider
@@ -51,9 +53,9 @@ func (n *node) Role() (string, error) {
 }
 
 func (n *node) IP() (ipv4 string, ipv6 string, err error) {
-	// retrieve the IP address of the node using podman inspect
+	// retrieve the IP address of the node's primary network using podman inspect
 	cmd := exec.Command("podman", "inspect",
-		"-f", "{{range .NetworkSettings.Networks}}{{.IPAddress}},{{.GlobalIPv6Address}}{{end}}",
+		"-f", common.NodeIPInspectFormat(networkLabelKey),
 		n.name, // ... against the "node" container
 	)
 	lines, err := exec.OutputLines(cmd)
@@ -63,11 +65,7 @@ func (n *node) IP() (ipv4 string, ipv6 string, err error) {
 	if len(lines) != 1 {
 		return "", "", errors.Errorf("file should only be one line, got %d lines", len(lines))
 	}
-	ips := strings.Split(lines[0], ",")
-	if len(ips) != 2 {
-		return "", "", errors.Errorf("container addresses should have 2 values, got %d values", len(ips))
-	}
-	return ips[0], ips[1], nil
+	return common.ParseNodeIPs(lines[0])
 }
 
 func (n *node) Command(command string, args ...string) exec.Cmd {
@@ -94,6 +92,7 @@ type nodeCmd struct {
 	args     []string
 	env      []string
 	stdin    io.Reader
+	tty      bool
 	stdout   io.Writer
 	stderr   io.Writer
 	ctx      context.Context
@@ -112,6 +111,11 @@ func (c *nodeCmd) Run() error {
 			"-i", // interactive so we can supply input
 		)
 	}
+	if c.tty {
+		args = append(args,
+			"-t", // allocate a pseudo-TTY, E.G. for interactive shells
+		)
+	}
 	// set env
 	for _, env := range c.env {
 		args = append(args, "-e", env)
@@ -166,6 +170,21 @@ func (c *nodeCmd) SetStderr(w io.Writer) exec.Cmd {
 	return c
 }
 
+// SetTTY sets whether to allocate a pseudo-TTY for the command
+func (c *nodeCmd) SetTTY(tty bool) exec.Cmd {
+	c.tty = tty
+	return c
+}
+
 func (n *node) SerialLogs(w io.Writer) error {
 	return exec.Command("podman", "logs", n.name).SetStdout(w).SetStderr(w).Run()
 }
+
+// StreamSerialLogs writes the node container logs with timestamps to w,
+// following them if follow is true, starting since ago if non-zero
+func (n *node) StreamSerialLogs(w io.Writer, follow bool, since time.Duration) error {
+	args := common.SerialLogsArgs(n.name, follow, since)
+	// the logs may be followed indefinitely, so do not keep a copy of them
+	out := exec.Uncaptured(w)
+	return exec.Command("podman", args...).SetStdout(out).SetStderr(out).Run()
+}
@@ -19,6 +19,7 @@ package podman
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"net"
 	"os"
 	"path/filepath"
@@ -74,16 +75,31 @@ func (p *provider) Provision(status *cli.Status, cfg *config.Cluster) (err error
 	}
 
 	// ensure the pre-requisite network exists
-	networkName := fixedNetworkName
-	if n := os.Getenv("KIND_EXPERIMENTAL_PODMAN_NETWORK"); n != "" {
+	networkName := p.NetworkName(cfg)
+	if cfg.Networking.ContainerNetwork.Name == "" && networkName != fixedNetworkName {
 		p.logger.Warn("WARNING: Overriding podman network due to KIND_EXPERIMENTAL_PODMAN_NETWORK")
 		p.logger.Warn("WARNING: Here be dragons! This is not supported currently.")
-		networkName = n
 	}
-	if err := ensureNetwork(networkName); err != nil {
+	if err := ensureNetwork(networkName, &cfg.Networking.ContainerNetwork, config.ClusterHasIPv6(cfg)); err != nil {
 		return errors.Wrap(err, "failed to ensure podman network")
 	}
 
+	// static node addresses must fit the network, which may already have existed
+	if common.HasStaticAddresses(cfg.Nodes) {
+		settings, err := getNetworkSettings(networkName)
+		if err != nil {
+			return err
+		}
+		if err := common.ValidateNodeAddresses(networkName, cfg.Nodes, settings); err != nil {
+			return err
+		}
+	}
+
+	// as must static extraNetworks addresses, which kind does not create
+	if err := common.ValidateExtraNetworkAddresses(cfg.Nodes, getNetworkSettings); err != nil {
+		return err
+	}
+
 	// actually provision the cluster
 	icons := strings.Repeat("📦 ", len(cfg.Nodes))
 	status.Start(fmt.Sprintf("Preparing nodes %s", icons))
@@ -138,6 +154,75 @@ func (p *provider) ListNodes(cluster string) ([]nodes.Node, error) {
 	return ret, nil
 }
 
+// GetClusterMetadata is part of the providers.Provider interface
+func (p *provider) GetClusterMetadata(cluster string) (*providers.ClusterMetadata, error) {
+	n, err := p.ListNodes(cluster)
+	if err != nil {
+		return nil, err
+	}
+	if len(n) == 0 {
+		return nil, errors.Errorf("no nodes found for cluster %q", cluster)
+	}
+	args := []string{"inspect", "--format", common.ClusterMetadataInspectFormat}
+	for _, node := range n {
+		args = append(args, node.String())
+	}
+	lines, err := exec.OutputLines(exec.Command("podman", args...))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to inspect nodes")
+	}
+	return common.ParseClusterMetadata(lines)
+}
+
+// ListImages is part of the providers.Provider interface
+func (p *provider) ListImages(reference string) ([]string, error) {
+	return common.ListImages("podman", reference)
+}
+
+// ImageID is part of the providers.Provider interface
+func (p *provider) ImageID(image string) (string, error) {
+	return common.ImageID("podman", image)
+}
+
+// SaveImages is part of the providers.Provider interface
+func (p *provider) SaveImages(w io.Writer, images ...string) error {
+	return common.SaveImages("podman", w, images...)
+}
+
+// GetNodeContainers is part of the providers.Provider interface
+func (p *provider) GetNodeContainers(n []nodes.Node) ([]providers.NodeContainer, error) {
+	if len(n) == 0 {
+		return []providers.NodeContainer{}, nil
+	}
+	args := []string{"inspect", "--format", common.NodeContainerInspectFormat(".Config.Image")}
+	names := make([]string, 0, len(n))
+	for _, node := range n {
+		args = append(args, node.String())
+		names = append(names, node.String())
+	}
+	lines, err := exec.OutputLines(exec.Command("podman", args...))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to inspect nodes")
+	}
+	return common.ParseNodeContainers(lines, names)
+}
+
+// CreatePortForwarder is part of the providers.Provider interface
+func (p *provider) CreatePortForwarder(cluster string, fwd *providers.PortForwarder) (nodes.Node, error) {
+	networkName, err := common.PrimaryNetwork("podman", networkLabelKey, fwd.Target)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get network of node %q", fwd.Target)
+	}
+	args, err := runArgsForPortForwarder(cluster, networkName, fwd)
+	if err != nil {
+		return nil, err
+	}
+	if err := common.CreateContainer("podman", fwd.Name, args); err != nil {
+		return nil, errors.Wrap(err, "failed to create port forwarder")
+	}
+	return p.node(fwd.Name), nil
+}
+
 // DeleteNodes is part of the providers.Provider interface
 func (p *provider) DeleteNodes(n []nodes.Node) error {
 	if len(n) == 0 {
@@ -348,6 +433,30 @@ func (p *provider) CollectLogs(dir string, nodes []nodes.Node) error {
 	return errors.AggregateConcurrent(fns)
 }
 
+// NetworkName is part of the providers.Provider interface
+func (p *provider) NetworkName(cfg *config.Cluster) string {
+	if n := cfg.Networking.ContainerNetwork.Name; n != "" {
+		return n
+	}
+	if n := os.Getenv("KIND_EXPERIMENTAL_PODMAN_NETWORK"); n != "" {
+		return n
+	}
+	return fixedNetworkName
+}
+
+// NetworkSubnets is part of the providers.Provider interface
+func (p *provider) NetworkSubnets(cfg *config.Cluster) ([]string, error) {
+	settings, err := getNetworkSettings(p.NetworkName(cfg))
+	if err != nil {
+		return nil, err
+	}
+	subnets := []string{}
+	for _, subnet := range settings.Subnets {
+		subnets = append(subnets, subnet.Subnet)
+	}
+	return subnets, nil
+}
+
 // Info returns the provider info.
 // The info is cached on the first time of the execution.
 func (p *provider) Info() (*providers.ProviderInfo, error) {
@@ -372,6 +481,9 @@ type podmanInfo struct {
 			Rootless bool `json:"rootless,omitempty"`
 		} `json:"security"`
 	} `json:"host"`
+	Store struct {
+		GraphRoot string `json:"graphRoot,omitempty"`
+	} `json:"store"`
 }
 
 // info detects ProviderInfo by executing `podman info --format json`.
@@ -423,6 +535,7 @@ func info(logger log.Logger) (*providers.ProviderInfo, error) {
 		SupportsMemoryLimit: cgroupSupportsMemoryLimit,
 		SupportsPidsLimit:   cgroupSupportsPidsLimit,
 		SupportsCPUShares:   cgroupSupportsCPUShares,
+		StorageRoot:         pInfo.Store.GraphRoot,
 	}
 	if info.Rootless && !v.AtLeast(version.MustParseSemantic("4.0.0")) {
 		if logger != nil {
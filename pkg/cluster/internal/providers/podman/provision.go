@@ -21,7 +21,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net"
-	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
@@ -30,6 +30,7 @@ import (
 	"sigs.k8s.io/kind/pkg/exec"
 
 	"sigs.k8s.io/kind/pkg/cluster/internal/loadbalancer"
+	"sigs.k8s.io/kind/pkg/cluster/internal/providers"
 	"sigs.k8s.io/kind/pkg/cluster/internal/providers/common"
 	"sigs.k8s.io/kind/pkg/internal/apis/config"
 )
@@ -84,13 +85,8 @@ func planCreation(cfg *config.Cluster, networkName string) (createContainerFuncs
 		name := names[i]
 
 		// fixup relative paths, podman can only handle absolute paths
-		for i := range node.ExtraMounts {
-			hostPath := node.ExtraMounts[i].HostPath
-			absHostPath, err := filepath.Abs(hostPath)
-			if err != nil {
-				return nil, errors.Wrapf(err, "unable to resolve absolute path for hostPath: %q", hostPath)
-			}
-			node.ExtraMounts[i].HostPath = absHostPath
+		if err := common.MakeMountsAbsolute(node.ExtraMounts); err != nil {
+			return nil, err
 		}
 
 		// plan actual creation based on role
@@ -108,7 +104,10 @@ func planCreation(cfg *config.Cluster, networkName string) (createContainerFuncs
 				if err != nil {
 					return err
 				}
-				return createContainerWithWaitUntilSystemdReachesMultiUserSystem(name, args)
+				if err := createContainerWithWaitUntilSystemdReachesMultiUserSystem(name, args); err != nil {
+					return err
+				}
+				return common.ConnectExtraNetworks("podman", name, node.ExtraNetworks)
 			})
 		case config.WorkerRole:
 			createContainerFuncs = append(createContainerFuncs, func() error {
@@ -116,7 +115,10 @@ func planCreation(cfg *config.Cluster, networkName string) (createContainerFuncs
 				if err != nil {
 					return err
 				}
-				return createContainerWithWaitUntilSystemdReachesMultiUserSystem(name, args)
+				if err := createContainerWithWaitUntilSystemdReachesMultiUserSystem(name, args); err != nil {
+					return err
+				}
+				return common.ConnectExtraNetworks("podman", name, node.ExtraNetworks)
 			})
 		default:
 			return nil, errors.Errorf("unknown node role: %q", node.Role)
@@ -134,6 +136,8 @@ func commonArgs(cfg *config.Cluster, networkName string, nodeNames []string) ([]
 		"--net", networkName, // attach to its own network
 		// label the node with the cluster ID
 		"--label", fmt.Sprintf("%s=%s", clusterLabelKey, cfg.Name),
+		// label the node with its primary network, nodes may have extra networks
+		"--label", fmt.Sprintf("%s=%s", networkLabelKey, networkName),
 		// specify container implementation to systemd
 		"-e", "container=podman",
 		// this is the default in cgroupsv2 but not in v1
@@ -170,6 +174,12 @@ func commonArgs(cfg *config.Cluster, networkName string, nodeNames []string) ([]
 		args = append(args, "-e", "KIND_DNS_SEARCH="+strings.Join(*cfg.Networking.DNSSearch, " "))
 	}
 
+	// store the cluster labels and annotations on every node
+	args = append(args, common.GenerateClusterMetadataArgs(cfg.Labels, cfg.Annotations)...)
+
+	// record the allocated subnets of multi-cluster clusters on every node
+	args = append(args, common.GenerateSubnetLabelArgs(&cfg.Networking)...)
+
 	return args, nil
 }
 
@@ -213,7 +223,8 @@ func runArgsForNode(node *config.Node, clusterIPFamily config.ClusterIPFamily, n
 	)
 
 	// convert mounts and port mappings to container run args
-	args = append(args, generateMountBindings(node.ExtraMounts...)...)
+	args = append(args, common.GenerateStaticAddressArgs(node)...)
+	args = append(args, common.GenerateMountBindings(node.ExtraMounts...)...)
 	mappingArgs, err := generatePortMappings(clusterIPFamily, node.ExtraPortMappings...)
 	if err != nil {
 		return nil, err
@@ -261,6 +272,39 @@ func runArgsForLoadBalancer(cfg *config.Cluster, name string, args []string) ([]
 	return args, nil
 }
 
+// runArgsForPortForwarder computes the arguments for a port forwarder, which
+// runs the load balancer image configured to forward a single port
+func runArgsForPortForwarder(cluster, networkName string, fwd *providers.PortForwarder) ([]string, error) {
+	args := []string{
+		"--detach",             // run the container detached
+		"--tty",                // allocate a tty for entrypoint logs
+		"--hostname", fwd.Name, // make hostname match container name
+		// attach to the target node's network
+		"--net", networkName,
+		// label the node with the cluster ID, so it is deleted with the cluster
+		"--label", fmt.Sprintf("%s=%s", clusterLabelKey, cluster),
+		"--label", fmt.Sprintf("%s=%s", networkLabelKey, networkName),
+		// label the node with the role ID and the node it forwards to
+		"--label", fmt.Sprintf("%s=%s", nodeRoleLabelKey, constants.PortForwarderNodeRoleValue),
+		"--label", fmt.Sprintf("%s=%s", common.PortForwarderTargetLabelKey, fwd.Target),
+	}
+
+	// the listen address has already been defaulted by the caller
+	mappingArgs, err := generatePortMappings(config.IPv4Family, fwd.Mapping)
+	if err != nil {
+		return nil, err
+	}
+	args = append(args, mappingArgs...)
+
+	// finally, specify the image to run
+	_, image := sanitizeImage(loadbalancer.Image)
+	args = append(args, image)
+
+	args = append(args, loadbalancer.GenerateForwarderBootstrapCommand(cluster, fwd.Name, fwd.LDSConfig, fwd.CDSConfig)...)
+
+	return args, nil
+}
+
 func getProxyEnv(cfg *config.Cluster, networkName string, nodeNames []string) (map[string]string, error) {
 	envs := common.GetProxyEnvs(cfg)
 	// Specifically add the podman network subnets to NO_PROXY if we are using a proxy
@@ -289,88 +333,88 @@ func getProxyEnv(cfg *config.Cluster, networkName string, nodeNames []string) (m
 type podmanNetworks []struct {
 	// v4+
 	Subnets []struct {
-		Subnet  string `json:"subnet"`
-		Gateway string `json:"gateway"`
+		Subnet     string `json:"subnet"`
+		Gateway    string `json:"gateway"`
+		LeaseRange *struct {
+			StartIP string `json:"start_ip"`
+			EndIP   string `json:"end_ip"`
+		} `json:"lease_range,omitempty"`
 	} `json:"subnets"`
+	Options map[string]string `json:"options"`
 	// v3 and anything still using CNI/IPAM
 	Plugins []struct {
+		MTU  int `json:"mtu"`
 		Ipam struct {
 			Ranges [][]struct {
-				Gateway string `json:"gateway"`
-				Subnet  string `json:"subnet"`
+				Gateway    string `json:"gateway"`
+				Subnet     string `json:"subnet"`
+				RangeStart string `json:"rangeStart"`
+				RangeEnd   string `json:"rangeEnd"`
 			} `json:"ranges"`
 		} `json:"ipam,omitempty"`
 	} `json:"plugins"`
 }
 
 func getSubnets(networkName string) ([]string, error) {
+	settings, err := getNetworkSettings(networkName)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get subnets")
+	}
+	subnets := []string{}
+	for _, subnet := range settings.Subnets {
+		subnets = append(subnets, subnet.Subnet)
+	}
+	return subnets, nil
+}
+
+// getNetworkSettings returns the settings of the existing network by name
+func getNetworkSettings(networkName string) (*common.NetworkSettings, error) {
 	cmd := exec.Command("podman", "network", "inspect", networkName)
 	out, err := exec.Output(cmd)
-
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to get subnets")
+		return nil, errors.Wrapf(err, "failed to inspect network %q", networkName)
 	}
+	return parseNetworkInspect(out)
+}
 
+// parseNetworkInspect parses `podman network inspect` output
+func parseNetworkInspect(inspectOut []byte) (*common.NetworkSettings, error) {
 	networks := podmanNetworks{}
-	jsonErr := json.Unmarshal([]byte(out), &networks)
-	if jsonErr != nil {
-		return nil, errors.Wrap(jsonErr, "failed to get subnets")
+	if err := json.Unmarshal(inspectOut, &networks); err != nil {
+		return nil, errors.Wrap(err, "failed to decode network inspect output")
 	}
-	subnets := []string{}
+	settings := &common.NetworkSettings{}
 	for _, network := range networks {
-		if len(network.Subnets) > 0 {
-			for _, subnet := range network.Subnets {
-				subnets = append(subnets, subnet.Subnet)
+		for _, subnet := range network.Subnets {
+			s := common.NetworkSubnet{
+				Subnet:  subnet.Subnet,
+				Gateway: subnet.Gateway,
 			}
-		}
-		if len(network.Plugins) > 0 {
-			for _, plugin := range network.Plugins {
-				for _, r := range plugin.Ipam.Ranges {
-					for _, rr := range r {
-						subnets = append(subnets, rr.Subnet)
-					}
-				}
+			if subnet.LeaseRange != nil {
+				s.RangeStart, s.RangeEnd = subnet.LeaseRange.StartIP, subnet.LeaseRange.EndIP
 			}
+			settings.Subnets = append(settings.Subnets, s)
 		}
-	}
-	return subnets, nil
-}
-
-// generateMountBindings converts the mount list to a list of args for podman
-// '<HostPath>:<ContainerPath>[:options]', where 'options'
-// is a comma-separated list of the following strings:
-// 'ro', if the path is read only
-// 'Z', if the volume requires SELinux relabeling
-func generateMountBindings(mounts ...config.Mount) []string {
-	args := make([]string, 0, len(mounts))
-	for _, m := range mounts {
-		bind := fmt.Sprintf("%s:%s", m.HostPath, m.ContainerPath)
-		var attrs []string
-		if m.Readonly {
-			attrs = append(attrs, "ro")
-		}
-		// Only request relabeling if the pod provides an SELinux context. If the pod
-		// does not provide an SELinux context relabeling will label the volume with
-		// the container's randomly allocated MCS label. This would restrict access
-		// to the volume to the container which mounts it first.
-		if m.SelinuxRelabel {
-			attrs = append(attrs, "Z")
-		}
-		switch m.Propagation {
-		case config.MountPropagationNone:
-			// noop, private is default
-		case config.MountPropagationBidirectional:
-			attrs = append(attrs, "rshared")
-		case config.MountPropagationHostToContainer:
-			attrs = append(attrs, "rslave")
-		default: // Falls back to "private"
+		if mtu, err := strconv.Atoi(network.Options["mtu"]); err == nil {
+			settings.MTU = mtu
 		}
-		if len(attrs) > 0 {
-			bind = fmt.Sprintf("%s:%s", bind, strings.Join(attrs, ","))
+		for _, plugin := range network.Plugins {
+			for _, r := range plugin.Ipam.Ranges {
+				for _, rr := range r {
+					settings.Subnets = append(settings.Subnets, common.NetworkSubnet{
+						Subnet:     rr.Subnet,
+						Gateway:    rr.Gateway,
+						RangeStart: rr.RangeStart,
+						RangeEnd:   rr.RangeEnd,
+					})
+				}
+			}
+			if plugin.MTU != 0 {
+				settings.MTU = plugin.MTU
+			}
 		}
-		args = append(args, fmt.Sprintf("--volume=%s", bind))
 	}
-	return args
+	return settings, nil
 }
 
 // generatePortMappings converts the portMappings list to a list of args for podman
@@ -19,6 +19,9 @@ limitations under the License.
 package providers
 
 import (
+	"io"
+	"time"
+
 	"sigs.k8s.io/kind/pkg/cluster/nodes"
 
 	"sigs.k8s.io/kind/pkg/internal/apis/config"
@@ -37,6 +40,22 @@ type Provider interface {
 	// ListNodes returns the nodes under this provider for the given
 	// cluster name, they may or may not be running correctly
 	ListNodes(cluster string) ([]nodes.Node, error)
+	// GetClusterMetadata returns the metadata stored on the cluster's nodes
+	GetClusterMetadata(cluster string) (*ClusterMetadata, error)
+	// GetNodeContainers returns the container details of the provided nodes,
+	// in the same order
+	GetNodeContainers([]nodes.Node) ([]NodeContainer, error)
+	// ListImages returns the tagged images in the local image store
+	// matching the reference filter, E.G. "kindest/node"
+	ListImages(reference string) ([]string, error)
+	// ImageID returns the ID of image in the local image store
+	ImageID(image string) (string, error)
+	// SaveImages writes an archive of images in the local image store to w,
+	// as in `docker save`
+	SaveImages(w io.Writer, images ...string) error
+	// CreatePortForwarder creates and starts a node forwarding a host port
+	// to a node of the cluster, the forwarding is configured separately
+	CreatePortForwarder(cluster string, fwd *PortForwarder) (nodes.Node, error)
 	// DeleteNodes deletes the provided list of nodes
 	// These should be from results previously returned by this provider
 	// E.G. by ListNodes()
@@ -49,6 +68,54 @@ type Provider interface {
 	CollectLogs(dir string, nodes []nodes.Node) error
 	// Info returns the provider info
 	Info() (*ProviderInfo, error)
+	// NetworkName returns the name of the container network the nodes of a
+	// cluster with cfg are attached to
+	NetworkName(cfg *config.Cluster) string
+	// NetworkSubnets returns the subnets of the existing container network
+	// the nodes of a cluster with cfg are attached to
+	NetworkSubnets(cfg *config.Cluster) ([]string, error)
+}
+
+// ClusterMetadata is the metadata of a cluster, stored on its nodes
+type ClusterMetadata struct {
+	Labels      map[string]string
+	Annotations map[string]string
+	// CreationTimestamp is the creation time of the oldest node
+	CreationTimestamp time.Time
+}
+
+// NodeContainer describes the container implementing a node
+type NodeContainer struct {
+	Image string
+	// State is the container state, E.G. "running" or "exited"
+	State string
+	Ports []PortMapping
+	// Labels are the container labels
+	Labels map[string]string
+}
+
+// PortForwarder describes a node forwarding a host port to another node
+type PortForwarder struct {
+	// Name is the name of the forwarding node
+	Name string
+	// Target is the name of the node the port is forwarded to
+	Target string
+	// Mapping is the host port to publish, the forwarding node listens on
+	// the same ContainerPort as it forwards to on Target
+	Mapping config.PortMapping
+	// LDSConfig and CDSConfig are the Envoy listener and cluster configs,
+	// written by the forwarding node each time it starts
+	LDSConfig string
+	CDSConfig string
+}
+
+// PortMapping is a container port published on the host
+type PortMapping struct {
+	HostIP        string
+	HostPort      int32
+	ContainerPort int32
+	// Protocol is lowercase, E.G. "tcp"
+	Protocol string
 }
 
 // ProviderInfo is the info of the provider
@@ -58,4 +125,7 @@ type ProviderInfo struct {
 	SupportsMemoryLimit bool
 	SupportsPidsLimit   bool
 	SupportsCPUShares   bool
+	// StorageRoot is the runtime's storage directory, which may be inside
+	// a VM rather than on the host, empty if unknown
+	StorageRoot string
 }
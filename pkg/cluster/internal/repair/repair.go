@@ -0,0 +1,347 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package repair contains the logic for repairing a cluster after the node
+// containers have been restarted, E.G. following a host reboot.
+package repair
+
+import (
+	"bytes"
+	"sort"
+	"strings"
+
+	"sigs.k8s.io/kind/pkg/cluster/constants"
+	"sigs.k8s.io/kind/pkg/cluster/nodes"
+	"sigs.k8s.io/kind/pkg/cluster/nodeutils"
+	"sigs.k8s.io/kind/pkg/errors"
+	"sigs.k8s.io/kind/pkg/internal/apis/config"
+	"sigs.k8s.io/kind/pkg/internal/cli"
+	"sigs.k8s.io/kind/pkg/log"
+
+	"sigs.k8s.io/kind/pkg/cluster/internal/create/actions"
+	"sigs.k8s.io/kind/pkg/cluster/internal/create/actions/loadbalancer"
+	"sigs.k8s.io/kind/pkg/cluster/internal/create/actions/multicluster"
+	"sigs.k8s.io/kind/pkg/cluster/internal/kubeconfig"
+	"sigs.k8s.io/kind/pkg/cluster/internal/providers"
+)
+
+const (
+	// kubeletFlagsPath is where kubeadm records the kubelet flags,
+	// including --node-ip
+	kubeletFlagsPath = "/var/lib/kubelet/kubeadm-flags.env"
+	// kubeadmConfigPath is where the config action writes the kubeadm config
+	kubeadmConfigPath = "/kind/kubeadm.conf"
+	// staticPodManifestsDir contains the control plane static pod manifests
+	staticPodManifestsDir = "/etc/kubernetes/manifests"
+)
+
+// Cluster repairs the cluster identified by name, after the node containers
+// have been restarted and may have been assigned new addresses.
+// explicitKubeconfigPath is --kubeconfig, following the rules from
+// https://kubernetes.io/docs/reference/generated/kubectl/kubectl-commands
+// and kubeconfigStrategy is the kubeconfig.Strategy
+func Cluster(logger log.Logger, p providers.Provider, name, explicitKubeconfigPath string, kubeconfigStrategy kubeconfig.Strategy) error {
+	allNodes, err := p.ListNodes(name)
+	if err != nil {
+		return errors.Wrap(err, "error listing nodes")
+	}
+	if len(allNodes) == 0 {
+		return errors.Errorf("no nodes found for cluster %q", name)
+	}
+	kubeNodes, err := nodeutils.InternalNodes(allNodes)
+	if err != nil {
+		return err
+	}
+
+	status := cli.StatusForLogger(logger)
+
+	// detect address drift by comparing the current node addresses against
+	// the addresses the kubelets were configured with
+	status.Start("Detecting node address changes 🔎")
+	drifted, replacements, err := detectDrift(kubeNodes)
+	status.End(err == nil)
+	if err != nil {
+		return err
+	}
+
+	if len(drifted) == 0 {
+		logger.V(0).Info("No node address changes detected")
+	} else {
+		for _, d := range drifted {
+			logger.V(0).Infof("Node %q address changed from %q to %q", d.node, d.configured, d.current)
+		}
+		controlPlanes, err := nodeutils.ControlPlaneNodes(kubeNodes)
+		if err != nil {
+			return err
+		}
+		if len(controlPlanes) > 1 {
+			logger.Warn("WARNING: etcd peer addresses are not updated for clusters with multiple control-plane nodes, etcd may need to be repaired manually")
+		}
+		status.Start("Rewriting node configuration 📜")
+		err = rewriteNodes(kubeNodes, replacements)
+		status.End(err == nil)
+		if err != nil {
+			return err
+		}
+	}
+
+	// regenerate the load balancer config, backends may have new addresses
+	actionsContext := actions.NewActionContext(logger, status, p, &config.Cluster{
+		Name:       name,
+		Networking: config.Networking{IPFamily: ipFamily(kubeNodes)},
+	})
+	if err := loadbalancer.NewAction().Execute(actionsContext); err != nil {
+		return err
+	}
+
+	// routes between multi-cluster clusters use the node addresses as well
+	status.Start("Routing to other clusters 🔗")
+	err = multicluster.Reroute(logger, p, name)
+	status.End(err == nil)
+	if err != nil {
+		return err
+	}
+
+	// the API server host port may have changed as well, so re-export
+	return kubeconfig.Export(p, name, explicitKubeconfigPath, true, kubeconfigStrategy)
+}
+
+// nodeDrift records a node whose current address(es) differ from the ones
+// the kubelet was configured with
+type nodeDrift struct {
+	node       nodes.Node
+	configured string
+	current    string
+}
+
+// detectDrift returns the nodes with changed addresses, and a mapping from
+// previous to current addresses across all of them
+func detectDrift(kubeNodes []nodes.Node) ([]nodeDrift, map[string]string, error) {
+	drifted := []nodeDrift{}
+	replacements := map[string]string{}
+	for _, n := range kubeNodes {
+		var buff bytes.Buffer
+		if err := n.Command("cat", kubeletFlagsPath).SetStdout(&buff).Run(); err != nil {
+			return nil, nil, errors.Wrapf(err, "failed to read kubelet flags from node %q", n.String())
+		}
+		configured := parseNodeIP(buff.String())
+		if configured == "" {
+			return nil, nil, errors.Errorf("failed to find configured --node-ip for node %q", n.String())
+		}
+		ipv4, ipv6, err := n.IP()
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "failed to get IP for node %q", n.String())
+		}
+		changed := false
+		currentAddrs := []string{}
+		for _, old := range strings.Split(configured, ",") {
+			current := ipv4
+			if strings.Contains(old, ":") {
+				current = ipv6
+			}
+			if current == "" {
+				return nil, nil, errors.Errorf("node %q no longer has an address of the same family as %q", n.String(), old)
+			}
+			currentAddrs = append(currentAddrs, current)
+			if current != old {
+				replacements[old] = current
+				changed = true
+			}
+		}
+		if changed {
+			drifted = append(drifted, nodeDrift{
+				node:       n,
+				configured: configured,
+				current:    strings.Join(currentAddrs, ","),
+			})
+		}
+	}
+	return drifted, replacements, nil
+}
+
+// parseNodeIP extracts the --node-ip value from the kubeadm kubelet flags file
+func parseNodeIP(kubeletFlags string) string {
+	// the file looks like: KUBELET_KUBEADM_ARGS="--node-ip=172.18.0.2 ..."
+	for _, field := range strings.Fields(kubeletFlags) {
+		field = strings.TrimPrefix(field, `KUBELET_KUBEADM_ARGS=`)
+		field = strings.Trim(field, `"`)
+		if strings.HasPrefix(field, "--node-ip=") {
+			return strings.TrimPrefix(field, "--node-ip=")
+		}
+	}
+	return ""
+}
+
+// rewriteNodes replaces stale addresses in the kubeadm derived configuration
+// on every node and restarts the components consuming it
+func rewriteNodes(kubeNodes []nodes.Node, replacements map[string]string) error {
+	fns := make([]func() error, 0, len(kubeNodes))
+	for _, n := range kubeNodes {
+		node := n // capture loop variable
+		fns = append(fns, func() error {
+			return rewriteNode(node, replacements)
+		})
+	}
+	return errors.UntilErrorConcurrent(fns)
+}
+
+func rewriteNode(node nodes.Node, replacements map[string]string) error {
+	role, err := node.Role()
+	if err != nil {
+		return err
+	}
+	isControlPlane := role == constants.ControlPlaneNodeRoleValue
+
+	paths := []string{kubeletFlagsPath, kubeadmConfigPath}
+	if isControlPlane {
+		var buff bytes.Buffer
+		if err := node.Command("find", staticPodManifestsDir, "-maxdepth", "1", "-name", "*.yaml").SetStdout(&buff).Run(); err != nil {
+			return errors.Wrapf(err, "failed to list static pod manifests on node %q", node.String())
+		}
+		manifests := strings.Fields(buff.String())
+		sort.Strings(manifests)
+		paths = append(paths, manifests...)
+	}
+	for _, path := range paths {
+		if err := rewriteFile(node, path, replacements); err != nil {
+			return err
+		}
+	}
+
+	// the serving certificates include the node addresses as SANs
+	if isControlPlane {
+		const pki = "/etc/kubernetes/pki"
+		for _, c := range []struct {
+			phase string
+			base  string
+		}{
+			{"apiserver", pki + "/apiserver"},
+			{"etcd-server", pki + "/etcd/server"},
+			{"etcd-peer", pki + "/etcd/peer"},
+		} {
+			if err := node.Command("rm", "-f", c.base+".crt", c.base+".key").Run(); err != nil {
+				return errors.Wrapf(err, "failed to remove stale %s certificate on node %q", c.phase, node.String())
+			}
+			if err := node.Command("kubeadm", "init", "phase", "certs", c.phase, "--config", kubeadmConfigPath).Run(); err != nil {
+				return errors.Wrapf(err, "failed to regenerate %s certificate on node %q", c.phase, node.String())
+			}
+		}
+	}
+
+	// restart the kubelet to pick up the new flags, it will then restart
+	// any static pods with changed manifests
+	if err := node.Command("systemctl", "restart", "kubelet").Run(); err != nil {
+		return errors.Wrapf(err, "failed to restart kubelet on node %q", node.String())
+	}
+	return nil
+}
+
+func rewriteFile(node nodes.Node, path string, replacements map[string]string) error {
+	var buff bytes.Buffer
+	if err := node.Command("cat", path).SetStdout(&buff).Run(); err != nil {
+		return errors.Wrapf(err, "failed to read %s from node %q", path, node.String())
+	}
+	updated := replaceAddresses(buff.String(), replacements)
+	if updated == buff.String() {
+		return nil
+	}
+	if err := nodeutils.WriteFile(node, path, updated); err != nil {
+		return errors.Wrapf(err, "failed to write %s to node %q", path, node.String())
+	}
+	return nil
+}
+
+// ipFamily infers the cluster IP family from the node addresses
+//
+// The original config is not available when repairing, however only
+// IPv6-only clusters need to be distinguished for the load balancer
+func ipFamily(kubeNodes []nodes.Node) config.ClusterIPFamily {
+	if len(kubeNodes) == 0 {
+		return config.IPv4Family
+	}
+	ipv4, _, err := kubeNodes[0].IP()
+	if err == nil && ipv4 == "" {
+		return config.IPv6Family
+	}
+	return config.IPv4Family
+}
+
+// replaceAddresses replaces all occurrences of the keys of replacements in
+// content with their values, in a single pass so that swapped addresses are
+// handled correctly. Only complete addresses are replaced, E.G. 172.18.0.2
+// will not match the prefix of 172.18.0.20
+func replaceAddresses(content string, replacements map[string]string) string {
+	if len(replacements) == 0 {
+		return content
+	}
+	// try longer addresses first
+	olds := make([]string, 0, len(replacements))
+	for old := range replacements {
+		olds = append(olds, old)
+	}
+	sort.Slice(olds, func(i, j int) bool {
+		if len(olds[i]) != len(olds[j]) {
+			return len(olds[i]) > len(olds[j])
+		}
+		return olds[i] < olds[j]
+	})
+
+	var out strings.Builder
+	for i := 0; i < len(content); {
+		matched := false
+		if i == 0 || !isAddressChar(content[i-1]) {
+			for _, old := range olds {
+				if strings.HasPrefix(content[i:], old) && isAddressEnd(content[i+len(old):], strings.Contains(old, ":")) {
+					out.WriteString(replacements[old])
+					i += len(old)
+					matched = true
+					break
+				}
+			}
+		}
+		if !matched {
+			out.WriteByte(content[i])
+			i++
+		}
+	}
+	return out.String()
+}
+
+// isAddressChar returns true if c may appear in an IPv4 or IPv6 address
+func isAddressChar(c byte) bool {
+	return isHex(c) || c == '.' || c == ':'
+}
+
+// isAddressEnd returns true if rest does not continue an address
+func isAddressEnd(rest string, ipv6 bool) bool {
+	if rest == "" {
+		return true
+	}
+	if isHex(rest[0]) {
+		return false
+	}
+	// separators only continue an address if followed by more of it,
+	// E.G. "172.18.0.2:6443" is a complete IPv4 address followed by a port
+	sep := byte('.')
+	if ipv6 {
+		sep = ':'
+	}
+	return !(rest[0] == sep && len(rest) > 1 && isHex(rest[1]))
+}
+
+func isHex(c byte) bool {
+	return ('0' <= c && c <= '9') || ('a' <= c && c <= 'f') || ('A' <= c && c <= 'F')
+}
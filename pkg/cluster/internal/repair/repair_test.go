@@ -0,0 +1,109 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package repair
+
+import (
+	"testing"
+
+	"sigs.k8s.io/kind/pkg/internal/assert"
+)
+
+func TestParseNodeIP(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name     string
+		flags    string
+		expected string
+	}{
+		{
+			name:     "first flag",
+			flags:    `KUBELET_KUBEADM_ARGS="--node-ip=172.18.0.2 --node-labels= --pod-infra-container-image=registry.k8s.io/pause:3.10"` + "\n",
+			expected: "172.18.0.2",
+		},
+		{
+			name:     "dual stack last flag",
+			flags:    `KUBELET_KUBEADM_ARGS="--container-runtime-endpoint=unix:///run/containerd/containerd.sock --node-ip=172.18.0.2,fc00:f853:ccd:e793::2"`,
+			expected: "172.18.0.2,fc00:f853:ccd:e793::2",
+		},
+		{
+			name:     "missing",
+			flags:    `KUBELET_KUBEADM_ARGS="--node-labels="`,
+			expected: "",
+		},
+	}
+	for _, tc := range cases {
+		tc := tc // capture range variable
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			assert.StringEqual(t, tc.expected, parseNodeIP(tc.flags))
+		})
+	}
+}
+
+func TestReplaceAddresses(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name         string
+		content      string
+		replacements map[string]string
+		expected     string
+	}{
+		{
+			name:         "no replacements",
+			content:      "--advertise-address=172.18.0.2",
+			replacements: nil,
+			expected:     "--advertise-address=172.18.0.2",
+		},
+		{
+			name:         "address with port",
+			content:      "- --listen-peer-urls=https://172.18.0.2:2380\n",
+			replacements: map[string]string{"172.18.0.2": "172.18.0.4"},
+			expected:     "- --listen-peer-urls=https://172.18.0.4:2380\n",
+		},
+		{
+			name:         "does not replace prefixes",
+			content:      "172.18.0.2 172.18.0.20 172.18.0.2.1",
+			replacements: map[string]string{"172.18.0.2": "172.18.0.3"},
+			expected:     "172.18.0.3 172.18.0.20 172.18.0.2.1",
+		},
+		{
+			name:    "swapped addresses",
+			content: "a=172.18.0.2,b=172.18.0.3",
+			replacements: map[string]string{
+				"172.18.0.2": "172.18.0.3",
+				"172.18.0.3": "172.18.0.2",
+			},
+			expected: "a=172.18.0.3,b=172.18.0.2",
+		},
+		{
+			name:    "dual stack",
+			content: `--node-ip=172.18.0.2,fc00:f853:ccd:e793::2"` + "\n" + "[fc00:f853:ccd:e793::2]:6443 fc00:f853:ccd:e793::22",
+			replacements: map[string]string{
+				"172.18.0.2":            "172.18.0.5",
+				"fc00:f853:ccd:e793::2": "fc00:f853:ccd:e793::5",
+			},
+			expected: `--node-ip=172.18.0.5,fc00:f853:ccd:e793::5"` + "\n" + "[fc00:f853:ccd:e793::5]:6443 fc00:f853:ccd:e793::22",
+		},
+	}
+	for _, tc := range cases {
+		tc := tc // capture range variable
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			assert.StringEqual(t, tc.expected, replaceAddresses(tc.content, tc.replacements))
+		})
+	}
+}
@@ -0,0 +1,52 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package state locates the host directory kind keeps state in, such as
+// per-cluster kubeconfig files
+package state
+
+import (
+	"os"
+	"path/filepath"
+
+	"sigs.k8s.io/kind/pkg/errors"
+)
+
+// DirEnv overrides the state directory, E.G. to isolate the kubeconfigs of
+// parallel CI jobs
+const DirEnv = "KIND_STATE_DIR"
+
+// Dir returns the kind state directory joined with elem, creating it
+func Dir(elem ...string) (string, error) {
+	dir := filepath.Join(append([]string{base(os.Getenv, os.UserCacheDir)}, elem...)...)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", errors.Wrap(err, "failed to create kind state directory")
+	}
+	return dir, nil
+}
+
+// base returns $KIND_STATE_DIR, or the kind directory in the user cache
+// directory, falling back to the temporary directory
+func base(getEnv func(string) string, userCacheDir func() (string, error)) string {
+	if dir := getEnv(DirEnv); dir != "" {
+		return dir
+	}
+	dir, err := userCacheDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+	return filepath.Join(dir, "kind")
+}
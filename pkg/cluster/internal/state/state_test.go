@@ -0,0 +1,61 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package state
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"sigs.k8s.io/kind/pkg/internal/assert"
+)
+
+func TestBase(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		Name         string
+		Env          map[string]string
+		UserCacheDir func() (string, error)
+		Expected     string
+	}{
+		{
+			Name:         "env override",
+			Env:          map[string]string{DirEnv: "/ci/job-1/kind"},
+			UserCacheDir: func() (string, error) { return "/home/user/.cache", nil },
+			Expected:     "/ci/job-1/kind",
+		},
+		{
+			Name:         "user cache dir",
+			UserCacheDir: func() (string, error) { return "/home/user/.cache", nil },
+			Expected:     filepath.Join("/home/user/.cache", "kind"),
+		},
+		{
+			Name:         "no user cache dir",
+			UserCacheDir: func() (string, error) { return "", errors.New("$HOME is not defined") },
+			Expected:     filepath.Join(os.TempDir(), "kind"),
+		},
+	}
+	for _, tc := range cases {
+		tc := tc // capture range variable
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+			getEnv := func(key string) string { return tc.Env[key] }
+			assert.StringEqual(t, tc.Expected, base(getEnv, tc.UserCacheDir))
+		})
+	}
+}
@@ -0,0 +1,135 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"strings"
+
+	"sigs.k8s.io/kind/pkg/cluster/constants"
+	"sigs.k8s.io/kind/pkg/cluster/nodes"
+	"sigs.k8s.io/kind/pkg/cluster/nodeutils"
+	"sigs.k8s.io/kind/pkg/errors"
+	"sigs.k8s.io/kind/pkg/exec"
+)
+
+// NodeInfo describes a node of a cluster, see Provider.ListNodesWithInfo
+type NodeInfo struct {
+	Name    string `json:"name"`
+	Cluster string `json:"cluster"`
+	// Role is the node role, including the external load balancer and
+	// port forwarders
+	Role string `json:"role"`
+	// State is the container state, E.G. "running" or "exited"
+	State string `json:"state"`
+	// Ready is the status of the Kubernetes Ready condition, E.G. "True",
+	// or empty if it could not be determined or the node is not a
+	// Kubernetes node
+	Ready             string     `json:"ready,omitempty"`
+	KubernetesVersion string     `json:"kubernetesVersion,omitempty"`
+	IPv4              string     `json:"ipv4,omitempty"`
+	IPv6              string     `json:"ipv6,omitempty"`
+	Image             string     `json:"image"`
+	Ports             []NodePort `json:"ports,omitempty"`
+}
+
+// NodePort is a node container port published on the host
+type NodePort struct {
+	HostIP        string `json:"hostIP,omitempty"`
+	HostPort      int32  `json:"hostPort"`
+	ContainerPort int32  `json:"containerPort"`
+	Protocol      string `json:"protocol"`
+}
+
+// ListNodesWithInfo returns info about each node of the cluster.
+// Fields that cannot be determined, E.G. because a node is not running,
+// are left empty rather than failing the entire list.
+func (p *Provider) ListNodesWithInfo(name string) ([]NodeInfo, error) {
+	name = defaultName(name)
+	allNodes, err := p.provider.ListNodes(name)
+	if err != nil {
+		return nil, err
+	}
+	containers, err := p.provider.GetNodeContainers(allNodes)
+	if err != nil {
+		return nil, err
+	}
+	ready := p.nodesReady(name, allNodes)
+
+	infos := make([]NodeInfo, len(allNodes))
+	fns := make([]func() error, 0, len(allNodes))
+	for i, n := range allNodes {
+		i, n := i, n // capture loop variables
+		fns = append(fns, func() error {
+			info := NodeInfo{
+				Name:    n.String(),
+				Cluster: name,
+				State:   containers[i].State,
+				Ready:   ready[n.String()],
+				Image:   containers[i].Image,
+			}
+			for _, port := range containers[i].Ports {
+				info.Ports = append(info.Ports, NodePort(port))
+			}
+			role, err := n.Role()
+			if err != nil {
+				return errors.Wrapf(err, "failed to get role of node %q", n.String())
+			}
+			info.Role = role
+			if info.State == "running" {
+				if info.IPv4, info.IPv6, err = n.IP(); err != nil {
+					p.logger.V(1).Infof("Failed to get IP of node %q: %v", n.String(), err)
+				}
+				if role == constants.ControlPlaneNodeRoleValue || role == constants.WorkerNodeRoleValue {
+					if info.KubernetesVersion, err = nodeutils.KubeVersion(n); err != nil {
+						p.logger.V(1).Infof("Failed to get Kubernetes version of node %q: %v", n.String(), err)
+					}
+				}
+			}
+			infos[i] = info
+			return nil
+		})
+	}
+	if err := errors.AggregateConcurrent(fns); err != nil {
+		return nil, err
+	}
+	return infos, nil
+}
+
+// nodesReady returns the status of the Ready condition by node name, it is
+// empty if the API server cannot be reached
+func (p *Provider) nodesReady(name string, allNodes []nodes.Node) map[string]string {
+	ready := map[string]string{}
+	controlPlane, err := nodeutils.BootstrapControlPlaneNode(allNodes)
+	if err != nil {
+		return ready
+	}
+	lines, err := exec.OutputLines(controlPlane.Command(
+		"kubectl", "--kubeconfig=/etc/kubernetes/admin.conf", "--request-timeout=5s", "get", "nodes",
+		"-o", `jsonpath={range .items[*]}{.metadata.name}{" "}{.status.conditions[?(@.type=="Ready")].status}{"\n"}{end}`,
+	))
+	if err != nil {
+		p.logger.V(1).Infof("Failed to get Ready condition of the nodes of cluster %q: %v", name, err)
+		return ready
+	}
+	for _, line := range lines {
+		fields := strings.Fields(line)
+		if len(fields) == 2 {
+			ready[fields[0]] = fields[1]
+		}
+	}
+	return ready
+}
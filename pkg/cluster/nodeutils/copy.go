@@ -0,0 +1,257 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeutils
+
+import (
+	"archive/tar"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"sigs.k8s.io/kind/pkg/cluster/nodes"
+	"sigs.k8s.io/kind/pkg/errors"
+	"sigs.k8s.io/kind/pkg/exec"
+)
+
+// CopyToNode copies src on the host to dest on the node, recursively if src
+// is a directory. Like `docker cp`, if dest is an existing directory src is
+// copied into it, otherwise src is copied to dest.
+// Files are streamed as a tar archive, preserving modes but not ownership.
+func CopyToNode(n nodes.Node, src, dest string) error {
+	if _, err := os.Lstat(src); err != nil {
+		return errors.Wrapf(err, "failed to read %q", src)
+	}
+	// determine the directory to extract to, and the name to extract as
+	dir, name := dest, filepath.Base(src)
+	if err := n.Command("test", "-d", dest).Run(); err != nil {
+		dir, name = splitNodePath(dest)
+		if err := n.Command("mkdir", "-p", dir).Run(); err != nil {
+			return errors.Wrapf(err, "failed to create directory %q", dir)
+		}
+	}
+	cmd := n.Command("tar", "--extract", "--no-same-owner", "--directory", dir, "--file", "-")
+	if err := exec.RunWithStdinWriter(cmd, func(w io.Writer) error {
+		return writeArchive(w, src, name)
+	}); err != nil {
+		return errors.Wrapf(err, "failed to copy %q to %q on node %q", src, dest, n.String())
+	}
+	return nil
+}
+
+// CopyFromNode copies src on the node to dest on the host, recursively if src
+// is a directory. Like `docker cp`, if dest is an existing directory src is
+// copied into it, otherwise src is copied to dest.
+// Files are streamed as a tar archive, preserving modes but not ownership.
+func CopyFromNode(n nodes.Node, src, dest string) error {
+	dir, name := splitNodePath(src)
+	if info, err := os.Stat(dest); err == nil && info.IsDir() {
+		dest = filepath.Join(dest, name)
+	} else if err := os.MkdirAll(filepath.Dir(dest), os.ModePerm); err != nil {
+		return errors.Wrapf(err, "failed to create directory %q", filepath.Dir(dest))
+	}
+	cmd := n.Command("tar", "--create", "--directory", dir, "--file", "-", name)
+	if err := exec.RunWithStdoutReader(cmd, func(r io.Reader) error {
+		return extractArchive(r, name, dest)
+	}); err != nil {
+		return errors.Wrapf(err, "failed to copy %q from node %q to %q", src, n.String(), dest)
+	}
+	return nil
+}
+
+// splitNodePath splits a path on a node into its directory and name, ignoring
+// trailing slashes
+func splitNodePath(p string) (dir, name string) {
+	p = path.Clean(p)
+	return path.Dir(p), path.Base(p)
+}
+
+// writeArchive writes src to w as a tar archive, with src named name
+func writeArchive(w io.Writer, src, name string) error {
+	tw := tar.NewWriter(w)
+	err := filepath.Walk(src, func(file string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, file)
+		if err != nil {
+			return err
+		}
+		link := ""
+		if info.Mode()&os.ModeSymlink != 0 {
+			if link, err = os.Readlink(file); err != nil {
+				return err
+			}
+		}
+		header, err := tar.FileInfoHeader(info, link)
+		if err != nil {
+			return err
+		}
+		header.Name = path.Join(name, filepath.ToSlash(rel))
+		if info.IsDir() {
+			header.Name += "/"
+		}
+		// ownership on the host is meaningless on the node
+		header.Uid, header.Gid = 0, 0
+		header.Uname, header.Gname = "", ""
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		f, err := os.Open(file)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = io.Copy(tw, f)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return tw.Close()
+}
+
+// extractArchive extracts the tar archive entries under name to dest
+func extractArchive(r io.Reader, name, dest string) error {
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if header.Typeflag == tar.TypeXGlobalHeader {
+			continue
+		}
+		entry := strings.TrimSuffix(path.Clean(header.Name), "/")
+		if entry != name && !strings.HasPrefix(entry, name+"/") {
+			return errors.Errorf("unexpected archive entry %q", header.Name)
+		}
+		target := filepath.Join(dest, filepath.FromSlash(strings.TrimPrefix(entry, name)))
+		// symlinks extracted earlier must not redirect writes outside of dest
+		if err := checkNoSymlinkParents(dest, target); err != nil {
+			return err
+		}
+		if err := removeSymlink(target); err != nil {
+			return err
+		}
+		mode := os.FileMode(header.Mode).Perm()
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, mode); err != nil {
+				return err
+			}
+			// MkdirAll is subject to the umask and ignores existing dirs
+			if err := os.Chmod(target, mode); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := extractFile(tr, target, mode); err != nil {
+				return err
+			}
+		case tar.TypeLink:
+			linkEntry := path.Clean(header.Linkname)
+			if linkEntry != name && !strings.HasPrefix(linkEntry, name+"/") {
+				return errors.Errorf("unexpected archive link target %q", header.Linkname)
+			}
+			source := filepath.Join(dest, filepath.FromSlash(strings.TrimPrefix(linkEntry, name)))
+			if err := checkNoSymlinkParents(dest, source); err != nil {
+				return err
+			}
+			_ = os.Remove(target)
+			if err := os.Link(source, target); err != nil {
+				return err
+			}
+		case tar.TypeSymlink:
+			// only relative symlinks staying within the copied tree are
+			// allowed, so that following them later cannot escape dest
+			if path.IsAbs(header.Linkname) {
+				return errors.Errorf("archive entry %q is a symlink to absolute path %q", header.Name, header.Linkname)
+			}
+			resolved := path.Join(path.Dir(entry), header.Linkname)
+			if resolved != name && !strings.HasPrefix(resolved, name+"/") {
+				return errors.Errorf("archive entry %q is a symlink to %q outside of the copied tree", header.Name, header.Linkname)
+			}
+			_ = os.Remove(target)
+			if err := os.Symlink(header.Linkname, target); err != nil {
+				return err
+			}
+		default:
+			// devices, fifos etc. are not supported
+			return errors.Errorf("unsupported archive entry type for %q", header.Name)
+		}
+	}
+}
+
+// checkNoSymlinkParents returns an error if any existing directory between
+// dest and target is a symlink, dest itself may be a symlink
+func checkNoSymlinkParents(dest, target string) error {
+	rel, err := filepath.Rel(dest, filepath.Dir(target))
+	if err != nil {
+		return err
+	}
+	if rel == "." {
+		return nil
+	}
+	current := dest
+	for _, part := range strings.Split(rel, string(filepath.Separator)) {
+		current = filepath.Join(current, part)
+		info, err := os.Lstat(current)
+		if os.IsNotExist(err) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if info.Mode()&os.ModeSymlink != 0 {
+			return errors.Errorf("refusing to write %q through symlink %q", target, current)
+		}
+	}
+	return nil
+}
+
+// removeSymlink removes target if it is a symlink, so that it is replaced
+// rather than written through
+func removeSymlink(target string) error {
+	info, err := os.Lstat(target)
+	if err != nil || info.Mode()&os.ModeSymlink == 0 {
+		return nil
+	}
+	return os.Remove(target)
+}
+
+func extractFile(r io.Reader, target string, mode os.FileMode) error {
+	f, err := os.OpenFile(target, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, mode)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	// OpenFile is subject to the umask and ignores existing files
+	return os.Chmod(target, mode)
+}
@@ -0,0 +1,176 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeutils
+
+import (
+	"archive/tar"
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"sigs.k8s.io/kind/pkg/internal/assert"
+)
+
+func TestSplitNodePath(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		Path string
+		Dir  string
+		Name string
+	}{
+		{Path: "/var/log/pods", Dir: "/var/log", Name: "pods"},
+		{Path: "/var/log/pods/", Dir: "/var/log", Name: "pods"},
+		{Path: "/var/log//pods//", Dir: "/var/log", Name: "pods"},
+		{Path: "/etc/hosts", Dir: "/etc", Name: "hosts"},
+	}
+	for _, tc := range cases {
+		tc := tc // capture range variable
+		t.Run(tc.Path, func(t *testing.T) {
+			t.Parallel()
+			dir, name := splitNodePath(tc.Path)
+			assert.StringEqual(t, tc.Dir, dir)
+			assert.StringEqual(t, tc.Name, name)
+		})
+	}
+}
+
+func TestArchiveRoundTrip(t *testing.T) {
+	t.Parallel()
+	src := filepath.Join(t.TempDir(), "src")
+	mustWrite := func(name, content string, mode os.FileMode) {
+		t.Helper()
+		file := filepath.Join(src, name)
+		if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := os.WriteFile(file, []byte(content), mode); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := os.Chmod(file, mode); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	mustWrite("config.yaml", "a: b\n", 0644)
+	mustWrite("scripts/run.sh", "#!/bin/sh\n", 0755)
+	if err := os.Symlink("scripts/run.sh", filepath.Join(src, "run")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var archive bytes.Buffer
+	if err := writeArchive(&archive, src, "copy"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	dest := filepath.Join(t.TempDir(), "dest")
+	if err := extractArchive(&archive, "copy", dest); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(dest, "config.yaml"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.StringEqual(t, "a: b\n", string(content))
+	info, err := os.Stat(filepath.Join(dest, "scripts", "run.sh"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if info.Mode().Perm() != 0755 {
+		t.Errorf("expected mode 0755, got %v", info.Mode().Perm())
+	}
+	link, err := os.Readlink(filepath.Join(dest, "run"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.StringEqual(t, "scripts/run.sh", link)
+}
+
+func TestExtractArchiveRejectsOtherEntries(t *testing.T) {
+	t.Parallel()
+	src := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(src, []byte("content"), 0644); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var archive bytes.Buffer
+	if err := writeArchive(&archive, src, "../escape"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := extractArchive(&archive, "file", filepath.Join(t.TempDir(), "dest")); err == nil {
+		t.Errorf("expected error for entry outside of the copied path")
+	}
+}
+
+func TestExtractArchiveRejectsUnsafeSymlinks(t *testing.T) {
+	t.Parallel()
+	type entry struct {
+		name     string
+		typeflag byte
+		linkname string
+	}
+	cases := []struct {
+		name    string
+		entries []entry
+	}{
+		{
+			name: "absolute symlink",
+			entries: []entry{
+				{name: "copy/", typeflag: tar.TypeDir},
+				{name: "copy/passwd", typeflag: tar.TypeSymlink, linkname: "/etc/passwd"},
+			},
+		},
+		{
+			name: "symlink escaping the copied tree",
+			entries: []entry{
+				{name: "copy/", typeflag: tar.TypeDir},
+				{name: "copy/up", typeflag: tar.TypeSymlink, linkname: "../../outside"},
+			},
+		},
+		{
+			name: "write through a symlinked parent",
+			entries: []entry{
+				{name: "copy/", typeflag: tar.TypeDir},
+				{name: "copy/inner/", typeflag: tar.TypeDir},
+				{name: "copy/link", typeflag: tar.TypeSymlink, linkname: "inner"},
+				{name: "copy/link/file", typeflag: tar.TypeReg},
+			},
+		},
+	}
+	for _, tc := range cases {
+		tc := tc // capture range variable
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			var archive bytes.Buffer
+			tw := tar.NewWriter(&archive)
+			for _, e := range tc.entries {
+				if err := tw.WriteHeader(&tar.Header{
+					Name:     e.name,
+					Typeflag: e.typeflag,
+					Linkname: e.linkname,
+					Mode:     0755,
+				}); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			}
+			if err := tw.Close(); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if err := extractArchive(&archive, "copy", filepath.Join(t.TempDir(), "dest")); err == nil {
+				t.Errorf("expected error for unsafe symlink")
+			}
+		})
+	}
+}
@@ -64,14 +64,11 @@ func CopyNodeToNode(a, b nodes.Node, file string) error {
 		return errors.Wrapf(err, "failed to create directory %q", path.Dir(file))
 	}
 
-	// TODO: experiment with streaming instead to avoid the copy
-	// for now we only use this for small files so it's not worth the complexity
-	var buff bytes.Buffer
-	if err := a.Command("cat", file).SetStdout(&buff).Run(); err != nil {
-		return errors.Wrapf(err, "failed to read %q from node", file)
-	}
-	if err := b.Command("cp", "/dev/stdin", file).SetStdin(&buff).Run(); err != nil {
-		return errors.Wrapf(err, "failed to write %q to node", file)
+	// stream the file rather than buffering it
+	if err := exec.RunWithStdoutReader(a.Command("cat", file), func(r io.Reader) error {
+		return b.Command("cp", "/dev/stdin", file).SetStdin(r).Run()
+	}); err != nil {
+		return errors.Wrapf(err, "failed to copy %q between nodes", file)
 	}
 
 	return nil
@@ -79,17 +76,70 @@ func CopyNodeToNode(a, b nodes.Node, file string) error {
 
 // LoadImageArchive loads image onto the node, where image is a Reader over an image archive
 func LoadImageArchive(n nodes.Node, image io.Reader) error {
+	return loadImageArchive(n, image, "--all-platforms")
+}
+
+// LoadImageArchiveForPlatform loads only the platform, E.G. linux/arm64, of
+// image onto the node, where image is a Reader over an image archive
+func LoadImageArchiveForPlatform(n nodes.Node, image io.Reader, platform string) error {
+	return loadImageArchive(n, image, "--platform="+platform)
+}
+
+func loadImageArchive(n nodes.Node, image io.Reader, platformFlag string) error {
 	snapshotter, err := getSnapshotter(n)
 	if err != nil {
 		return err
 	}
-	cmd := n.Command("ctr", "--namespace=k8s.io", "images", "import", "--all-platforms", "--digests", "--snapshotter="+snapshotter, "-").SetStdin(image)
+	cmd := n.Command("ctr", "--namespace=k8s.io", "images", "import", platformFlag, "--digests", "--snapshotter="+snapshotter, "-").SetStdin(image)
 	if err := cmd.Run(); err != nil {
 		return errors.Wrap(err, "failed to load image")
 	}
 	return nil
 }
 
+// Platform returns the platform of the node, E.G. linux/arm64
+func Platform(n nodes.Node) (string, error) {
+	lines, err := exec.OutputLines(n.Command("uname", "-m"))
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get node architecture")
+	}
+	if len(lines) != 1 {
+		return "", errors.Errorf("expected one line of architecture output, got %d", len(lines))
+	}
+	return "linux/" + parseArchitecture(lines[0]), nil
+}
+
+// parseArchitecture converts a kernel machine name to an OCI architecture
+func parseArchitecture(machine string) string {
+	switch machine = strings.TrimSpace(machine); machine {
+	case "x86_64":
+		return "amd64"
+	case "aarch64":
+		return "arm64"
+	case "armv7l":
+		return "arm/v7"
+	case "armv6l":
+		return "arm/v6"
+	}
+	return machine
+}
+
+// ContentDigests returns the digests of the blobs in the node's containerd
+// content store, E.G. image layers
+func ContentDigests(n nodes.Node) (map[string]bool, error) {
+	lines, err := exec.OutputLines(n.Command("ctr", "--namespace=k8s.io", "content", "ls", "--quiet"))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list content")
+	}
+	digests := make(map[string]bool, len(lines))
+	for _, line := range lines {
+		if line = strings.TrimSpace(line); line != "" {
+			digests[line] = true
+		}
+	}
+	return digests, nil
+}
+
 func getSnapshotter(n nodes.Node) (string, error) {
 	out, err := exec.Output(n.Command("containerd", "config", "dump"))
 	if err != nil {
@@ -170,3 +220,43 @@ func ReTagImage(n nodes.Node, imageID, imageName string) error {
 	var out bytes.Buffer
 	return n.Command("ctr", "--namespace=k8s.io", "images", "tag", "--force", imageID, imageName).SetStdout(&out).Run()
 }
+
+// Image is an image in a node's CRI image store
+type Image struct {
+	ID          string   `json:"id"`
+	RepoTags    []string `json:"repoTags"`
+	RepoDigests []string `json:"repoDigests"`
+	Size        uint64   `json:"size,string"`
+	// Pinned images are exempt from kubelet image garbage collection, such
+	// as the images preloaded into node images
+	Pinned bool `json:"pinned"`
+}
+
+// ListImages returns the images in the node's CRI image store
+func ListImages(n nodes.Node) ([]Image, error) {
+	var out bytes.Buffer
+	if err := n.Command("crictl", "images", "--output=json").SetStdout(&out).Run(); err != nil {
+		return nil, errors.Wrap(err, "failed to list images")
+	}
+	return parseImages(out.Bytes())
+}
+
+func parseImages(crictlOut []byte) ([]Image, error) {
+	images := struct {
+		Images []Image `json:"images"`
+	}{}
+	if err := json.Unmarshal(crictlOut, &images); err != nil {
+		return nil, errors.Wrap(err, "failed to parse images")
+	}
+	return images.Images, nil
+}
+
+// RemoveImages removes image references from the node, E.G. tags, images are
+// deleted once no references remain
+func RemoveImages(n nodes.Node, refs ...string) error {
+	args := append([]string{"--namespace=k8s.io", "images", "rm"}, refs...)
+	if err := n.Command("ctr", args...).Run(); err != nil {
+		return errors.Wrap(err, "failed to remove images")
+	}
+	return nil
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package nodeutils
 
 import (
+	"reflect"
 	"testing"
 )
 
@@ -288,3 +289,66 @@ func TestParseSnapshotter(t *testing.T) {
 		t.Fatalf(`unexpected parsed snapshotter for v4: %q, expected "btrfs"`, snapshotter)
 	}
 }
+
+func TestParseImages(t *testing.T) {
+	out := `{
+  "images": [
+    {
+      "id": "sha256:9f1c",
+      "repoTags": ["docker.io/kindest/kindnetd:v20230511-dc714da8"],
+      "repoDigests": [],
+      "size": "27731571",
+      "uid": null,
+      "username": "",
+      "spec": null,
+      "pinned": true
+    },
+    {
+      "id": "sha256:a416",
+      "repoTags": [],
+      "repoDigests": ["docker.io/library/busybox@sha256:3fbc"],
+      "size": "2156416",
+      "uid": null,
+      "username": "",
+      "spec": null,
+      "pinned": false
+    }
+  ]
+}`
+	images, err := parseImages([]byte(out))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []Image{
+		{
+			ID:          "sha256:9f1c",
+			RepoTags:    []string{"docker.io/kindest/kindnetd:v20230511-dc714da8"},
+			RepoDigests: []string{},
+			Size:        27731571,
+			Pinned:      true,
+		},
+		{
+			ID:          "sha256:a416",
+			RepoTags:    []string{},
+			RepoDigests: []string{"docker.io/library/busybox@sha256:3fbc"},
+			Size:        2156416,
+		},
+	}
+	if !reflect.DeepEqual(images, expected) {
+		t.Errorf("expected %+v, got %+v", expected, images)
+	}
+}
+
+func TestParseArchitecture(t *testing.T) {
+	for machine, expected := range map[string]string{
+		"x86_64\n": "amd64",
+		"aarch64":  "arm64",
+		"armv7l":   "arm/v7",
+		"ppc64le":  "ppc64le",
+		"s390x":    "s390x",
+	} {
+		if arch := parseArchitecture(machine); arch != expected {
+			t.Errorf("parseArchitecture(%q) = %q, expected %q", machine, arch, expected)
+		}
+	}
+}
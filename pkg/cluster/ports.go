@@ -0,0 +1,99 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"sort"
+
+	"sigs.k8s.io/kind/pkg/apis/config/v1alpha4"
+	"sigs.k8s.io/kind/pkg/cluster/nodes"
+	"sigs.k8s.io/kind/pkg/errors"
+	"sigs.k8s.io/kind/pkg/internal/apis/config"
+
+	"sigs.k8s.io/kind/pkg/cluster/internal/expose"
+	"sigs.k8s.io/kind/pkg/cluster/internal/providers/common"
+)
+
+// ClusterPort is a port of a cluster published on the host, see
+// Provider.ListPorts
+type ClusterPort struct {
+	// Node is the node the port reaches
+	Node string `json:"node"`
+	// Forwarder is the node forwarding the port to Node, it is empty if the
+	// port is published by Node itself
+	Forwarder string `json:"forwarder,omitempty"`
+	NodePort
+}
+
+// ExposePort publishes a host port on the running cluster name, forwarding
+// it to mapping.ContainerPort on node, or on the control plane if node is
+// empty. Unlike extraPortMappings this does not require recreating the
+// cluster. The port is removed when the cluster is deleted.
+func (p *Provider) ExposePort(name, node string, mapping v1alpha4.PortMapping) ([]ClusterPort, error) {
+	name = defaultName(name)
+	fwd, err := expose.Port(p.provider, name, node, config.PortMapping{
+		ContainerPort: mapping.ContainerPort,
+		HostPort:      mapping.HostPort,
+		ListenAddress: mapping.ListenAddress,
+		Protocol:      config.PortMappingProtocol(mapping.Protocol),
+	})
+	if err != nil {
+		return nil, err
+	}
+	// the host port may have been picked at random
+	return p.listPorts([]nodes.Node{fwd})
+}
+
+// ListPorts returns the ports of the cluster published on the host,
+// including the API server port and extraPortMappings, sorted by node
+func (p *Provider) ListPorts(name string) ([]ClusterPort, error) {
+	allNodes, err := p.provider.ListNodes(defaultName(name))
+	if err != nil {
+		return nil, err
+	}
+	ports, err := p.listPorts(allNodes)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(ports, func(i, j int) bool {
+		if ports[i].Node != ports[j].Node {
+			return ports[i].Node < ports[j].Node
+		}
+		return ports[i].ContainerPort < ports[j].ContainerPort
+	})
+	return ports, nil
+}
+
+func (p *Provider) listPorts(allNodes []nodes.Node) ([]ClusterPort, error) {
+	containers, err := p.provider.GetNodeContainers(allNodes)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get node containers")
+	}
+	ports := []ClusterPort{}
+	for i, n := range allNodes {
+		port := ClusterPort{Node: n.String()}
+		if target := containers[i].Labels[common.PortForwarderTargetLabelKey]; target != "" {
+			port.Node = target
+			port.Forwarder = n.String()
+		}
+		for _, mapping := range containers[i].Ports {
+			port.NodePort = NodePort(mapping)
+			ports = append(ports, port)
+		}
+	}
+	return ports, nil
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package cluster
 
 import (
+	"io"
 	"os"
 	"path/filepath"
 	"sort"
@@ -37,8 +38,10 @@ import (
 	internalproviders "sigs.k8s.io/kind/pkg/cluster/internal/providers"
 	"sigs.k8s.io/kind/pkg/cluster/internal/providers/common"
 	"sigs.k8s.io/kind/pkg/cluster/internal/providers/docker"
+	"sigs.k8s.io/kind/pkg/cluster/internal/providers/generic"
 	"sigs.k8s.io/kind/pkg/cluster/internal/providers/nerdctl"
 	"sigs.k8s.io/kind/pkg/cluster/internal/providers/podman"
+	internalrepair "sigs.k8s.io/kind/pkg/cluster/internal/repair"
 )
 
 // DefaultName is the default cluster name
@@ -56,6 +59,8 @@ func defaultName(name string) string {
 type Provider struct {
 	provider internalproviders.Provider
 	logger   log.Logger
+	// kubeconfigStrategy is the default KubeConfigStrategy, if set
+	kubeconfigStrategy kubeconfig.Strategy
 }
 
 // NewProvider returns a new provider based on the supplied options
@@ -181,11 +186,62 @@ func ProviderWithNerdctl(binaryName string) ProviderOption {
 	})
 }
 
+// CLIDialect declares how a docker CLI compatible binary differs from the
+// docker CLI, see ProviderWithCLI
+type CLIDialect = common.Dialect
+
+// CLIDialectForName returns a known CLIDialect by name, currently one of
+// "docker" or "nerdctl"
+func CLIDialectForName(name string) (CLIDialect, error) {
+	return generic.DialectForName(name)
+}
+
+// ProviderWithCLI configures the provider to use an arbitrary docker CLI
+// compatible binary, such as a wrapper around docker
+func ProviderWithCLI(binaryPath string, dialect CLIDialect) ProviderOption {
+	return providerRuntimeOption(func(p *Provider) {
+		p.provider = generic.NewProvider(p.logger, binaryPath, dialect)
+	})
+}
+
+// KubeConfigStrategy is how KUBECONFIGs are exported without an explicit
+// path, see ProviderWithKubeConfigStrategy
+type KubeConfigStrategy = kubeconfig.Strategy
+
+const (
+	// KubeConfigStrategyIsolated exports each cluster to its own file,
+	// see KubeConfigPath
+	KubeConfigStrategyIsolated = kubeconfig.StrategyIsolated
+	// KubeConfigStrategyMerged merges clusters into the file selected by
+	// the rules from
+	// https://kubernetes.io/docs/reference/generated/kubectl/kubectl-commands#config
+	KubeConfigStrategyMerged = kubeconfig.StrategyMerged
+)
+
+// providerKubeConfigOption is a trivial ProviderOption adapter
+type providerKubeConfigOption func(p *Provider)
+
+func (a providerKubeConfigOption) apply(p *Provider) {
+	a(p)
+}
+
+var _ ProviderOption = providerKubeConfigOption(nil)
+
+// ProviderWithKubeConfigStrategy configures the provider to export
+// KUBECONFIGs without an explicit path with strategy, rather than
+// KubeConfigStrategyMerged.
+func ProviderWithKubeConfigStrategy(strategy KubeConfigStrategy) ProviderOption {
+	return providerKubeConfigOption(func(p *Provider) {
+		p.kubeconfigStrategy = strategy
+	})
+}
+
 // Create provisions and starts a kubernetes-in-docker cluster
 func (p *Provider) Create(name string, options ...CreateOption) error {
 	// apply options
 	opts := &internalcreate.ClusterOptions{
-		NameOverride: name,
+		NameOverride:       name,
+		KubeconfigStrategy: p.kubeconfigStrategy,
 	}
 	for _, o := range options {
 		if err := o.apply(opts); err != nil {
@@ -195,9 +251,33 @@ func (p *Provider) Create(name string, options ...CreateOption) error {
 	return internalcreate.Cluster(p.logger, p.provider, opts)
 }
 
-// Delete tears down a kubernetes-in-docker cluster
+// Delete tears down a kubernetes-in-docker cluster.
+// If another process is creating or deleting the cluster, Delete waits up to
+// five minutes for it to finish, see DeleteWithOptions to change this.
 func (p *Provider) Delete(name, explicitKubeconfigPath string) error {
-	return internaldelete.Cluster(p.logger, p.provider, defaultName(name), explicitKubeconfigPath)
+	return p.DeleteWithOptions(name, explicitKubeconfigPath)
+}
+
+// DeleteWithOptions is Delete with options, see DeleteOption
+func (p *Provider) DeleteWithOptions(name, explicitKubeconfigPath string, options ...DeleteOption) error {
+	opts := &internaldelete.Options{
+		LockTimeout: internaldelete.DefaultLockTimeout,
+	}
+	for _, o := range options {
+		if err := o.apply(opts); err != nil {
+			return err
+		}
+	}
+	return internaldelete.Cluster(p.logger, p.provider, defaultName(name), explicitKubeconfigPath, opts)
+}
+
+// Repair reconfigures a kubernetes-in-docker cluster after the node
+// containers have been restarted, E.G. following a host reboot, updating
+// any configuration that depends on node addresses that have changed
+// and re-exporting the KUBECONFIG.
+// explicitKubeconfigPath is the --kubeconfig value.
+func (p *Provider) Repair(name, explicitKubeconfigPath string) error {
+	return internalrepair.Cluster(p.logger, p.provider, defaultName(name), explicitKubeconfigPath, p.kubeconfigStrategy)
 }
 
 // List returns a list of clusters for which nodes exist
@@ -213,11 +293,59 @@ func (p *Provider) KubeConfig(name string, internal bool) (string, error) {
 }
 
 // ExportKubeConfig exports the KUBECONFIG for the cluster, merging
-// it into the selected file, following the rules from
+// it into the selected file, where explicitPath is the --kubeconfig value.
+// Without explicitPath the file is selected by the KubeConfigStrategy, by
+// default the file selected by the rules from
 // https://kubernetes.io/docs/reference/generated/kubectl/kubectl-commands#config
-// where explicitPath is the --kubeconfig value.
+// see KubeConfigPath
 func (p *Provider) ExportKubeConfig(name string, explicitPath string, internal bool) error {
-	return kubeconfig.Export(p.provider, defaultName(name), explicitPath, !internal)
+	return kubeconfig.Export(p.provider, defaultName(name), explicitPath, !internal, p.kubeconfigStrategy)
+}
+
+// KubeConfigPath returns the path ExportKubeConfig writes to given the
+// --kubeconfig value explicitPath, empty if this is selected by kubectl's rules
+func (p *Provider) KubeConfigPath(name, explicitPath string) (string, error) {
+	return kubeconfig.ResolvePath(defaultName(name), explicitPath, p.kubeconfigStrategy)
+}
+
+// ExportIsolatedKubeConfig exports the KUBECONFIG for the cluster to the
+// cluster's own file regardless of the KubeConfigStrategy, returning its path
+func (p *Provider) ExportIsolatedKubeConfig(name string, internal bool) (string, error) {
+	return kubeconfig.ExportIsolated(p.provider, defaultName(name), !internal)
+}
+
+// KubeConfigUser describes a user authenticating with a client certificate
+// signed by the cluster CA, see ExportKubeConfigForUser
+type KubeConfigUser = kubeconfig.User
+
+// ExportKubeConfigForUser exports a KUBECONFIG for user, rather than the
+// kubeadm admin, merging it into the selected file like ExportKubeConfig.
+// The user gets their own context, see UserContext.
+func (p *Provider) ExportKubeConfigForUser(name string, explicitPath string, internal bool, user KubeConfigUser) error {
+	return kubeconfig.ExportUser(p.provider, defaultName(name), explicitPath, !internal, p.kubeconfigStrategy, &user)
+}
+
+// UserContext returns the KUBECONFIG context name of user for the cluster,
+// see ExportKubeConfigForUser
+func UserContext(name, user string) string {
+	return kubeconfig.UserContextForCluster(defaultName(name), user)
+}
+
+// ListImages returns the tagged images in the provider's local image store
+// matching the reference filter, E.G. "kindest/node" for node images
+func (p *Provider) ListImages(reference string) ([]string, error) {
+	return p.provider.ListImages(reference)
+}
+
+// ImageID returns the ID of image in the provider's local image store
+func (p *Provider) ImageID(image string) (string, error) {
+	return p.provider.ImageID(image)
+}
+
+// SaveImages writes an archive of images in the provider's local image store
+// to w, as in `docker save`
+func (p *Provider) SaveImages(w io.Writer, images ...string) error {
+	return p.provider.SaveImages(w, images...)
 }
 
 // ListNodes returns the list of container IDs for the "nodes" in the cluster
@@ -18,20 +18,25 @@ limitations under the License.
 package nodeimage
 
 import (
+	"strings"
+
 	"github.com/spf13/cobra"
 
 	"sigs.k8s.io/kind/pkg/build/nodeimage"
 	"sigs.k8s.io/kind/pkg/cmd"
 	"sigs.k8s.io/kind/pkg/errors"
 	"sigs.k8s.io/kind/pkg/log"
+
+	"sigs.k8s.io/kind/pkg/internal/completion"
 )
 
 type flagpole struct {
-	Source    string
-	BuildType string
-	Image     string
-	BaseImage string
-	Arch      string
+	Source     string
+	BuildType  string
+	Image      string
+	BaseImage  string
+	Arch       string
+	OCIArchive string
 }
 
 // NewCommand returns a new cobra.Command for building the node image
@@ -49,7 +54,13 @@ You can also build from:
 - A local Kubernetes release tarball path (e.g., /path/to/kubernetes-server-linux-amd64.tar.gz)
 - A URL to a Kubernetes release tarball (e.g., https://dl.k8s.io/v1.30.0/kubernetes-server-linux-amd64.tar.gz)
 - A release version (e.g., v1.36.1)
-- A Kubernetes CI build version marker prefixed with 'ci/' (e.g., ci/latest, ci/latest-1.36)`,
+- A Kubernetes CI build version marker prefixed with 'ci/' (e.g., ci/latest, ci/latest-1.36)
+
+Multiple comma separated architectures (e.g., --arch amd64,arm64) build an
+image per architecture and assemble them into an OCI image index tagged as
+--image, which is pushed to the registry of the image unless --oci-archive
+is set. The push does not use credentials, so the registry must be on a
+loopback address (e.g., localhost:5001/kindest/node:latest).`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return runE(logger, flags, args)
 		},
@@ -76,8 +87,16 @@ You can also build from:
 		&flags.Arch,
 		"arch",
 		"",
-		"architecture to build for, defaults to the host architecture",
+		"comma separated architectures to build for, defaults to the host architecture",
+	)
+	cmd.Flags().StringVar(
+		&flags.OCIArchive,
+		"oci-archive",
+		"",
+		"save the image index to an OCI image layout archive at this path instead of pushing it",
 	)
+	completion.RegisterFlag(cmd, "type", completion.Values("url", "file", "release", "ci", "source"))
+	completion.RegisterFlag(cmd, "arch", completion.ValueList("amd64", "arm64"))
 	return cmd
 }
 
@@ -91,7 +110,8 @@ func runE(logger log.Logger, flags *flagpole, args []string) error {
 		nodeimage.WithBaseImage(flags.BaseImage),
 		nodeimage.WithKubeParam(sourceSpec),
 		nodeimage.WithLogger(logger),
-		nodeimage.WithArch(flags.Arch),
+		nodeimage.WithArchitectures(strings.Split(flags.Arch, ",")...),
+		nodeimage.WithOCIArchive(flags.OCIArchive),
 		nodeimage.WithBuildType(flags.BuildType),
 	); err != nil {
 		return errors.Wrap(err, "error building node image")
@@ -0,0 +1,108 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package cp implements the `cp` command
+package cp
+
+import (
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/kind/pkg/cluster"
+	"sigs.k8s.io/kind/pkg/cluster/nodes"
+	"sigs.k8s.io/kind/pkg/cluster/nodeutils"
+	"sigs.k8s.io/kind/pkg/cmd"
+	"sigs.k8s.io/kind/pkg/errors"
+	"sigs.k8s.io/kind/pkg/log"
+
+	"sigs.k8s.io/kind/pkg/internal/cli"
+	"sigs.k8s.io/kind/pkg/internal/completion"
+	"sigs.k8s.io/kind/pkg/internal/runtime"
+)
+
+type flagpole struct {
+	Name string
+}
+
+// NewCommand returns a new cobra.Command for copying files to and from nodes
+func NewCommand(logger log.Logger, streams cmd.IOStreams) *cobra.Command {
+	flags := &flagpole{}
+	cmd := &cobra.Command{
+		Args:  cobra.ExactArgs(2),
+		Use:   "cp SRC NODE:DEST | NODE:SRC DEST",
+		Short: "Copies files and directories between the host and a node",
+		Long: `Copies files and directories between the host and a node, recursively.
+
+Like "docker cp", if DEST is an existing directory SRC is copied into it,
+otherwise SRC is copied to DEST. File modes are preserved.
+
+  kind cp ./manifests kind-control-plane:/etc/manifests
+  kind cp kind-control-plane:/var/log/pods ./pod-logs
+`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cli.OverrideDefaultName(cmd.Flags())
+			return runE(logger, flags, args[0], args[1])
+		},
+	}
+	cmd.Flags().StringVarP(
+		&flags.Name,
+		"name",
+		"n",
+		cluster.DefaultName,
+		"the cluster context name",
+	)
+	completion.RegisterFlag(cmd, "name", completion.ClusterNames)
+	return cmd
+}
+
+func runE(logger log.Logger, flags *flagpole, src, dest string) error {
+	provider := cluster.NewProvider(
+		cluster.ProviderWithLogger(logger),
+		runtime.GetDefault(logger),
+	)
+	allNodes, err := provider.ListNodes(flags.Name)
+	if err != nil {
+		return err
+	}
+	if len(allNodes) == 0 {
+		return errors.Errorf("no nodes found for cluster %q", flags.Name)
+	}
+
+	srcNode, srcPath := splitNodePath(allNodes, src)
+	destNode, destPath := splitNodePath(allNodes, dest)
+	switch {
+	case srcNode != nil && destNode != nil:
+		return errors.New("copying between nodes is not supported, copy to the host first")
+	case srcNode != nil:
+		return nodeutils.CopyFromNode(srcNode, srcPath, destPath)
+	case destNode != nil:
+		return nodeutils.CopyToNode(destNode, srcPath, destPath)
+	default:
+		return errors.Errorf("one of SRC or DEST must be NODE:PATH, where NODE is a node of cluster %q", flags.Name)
+	}
+}
+
+// splitNodePath splits NODE:PATH into the node and path, returning a nil
+// node if arg does not start with the name of one of allNodes
+func splitNodePath(allNodes []nodes.Node, arg string) (nodes.Node, string) {
+	for _, n := range allNodes {
+		if p := strings.TrimPrefix(arg, n.String()+":"); p != arg {
+			return n, p
+		}
+	}
+	return nil, arg
+}
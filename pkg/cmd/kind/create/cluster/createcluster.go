@@ -22,6 +22,7 @@ import (
 	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 
 	"sigs.k8s.io/kind/pkg/cluster"
 	"sigs.k8s.io/kind/pkg/cmd"
@@ -29,16 +30,25 @@ import (
 	"sigs.k8s.io/kind/pkg/log"
 
 	"sigs.k8s.io/kind/pkg/internal/cli"
+	"sigs.k8s.io/kind/pkg/internal/completion"
 	"sigs.k8s.io/kind/pkg/internal/runtime"
+	"sigs.k8s.io/kind/pkg/internal/settings"
 )
 
 type flagpole struct {
-	Name       string
-	Config     string
-	ImageName  string
-	Retain     bool
-	Wait       time.Duration
-	Kubeconfig string
+	Name              string
+	Config            string
+	ImageName         string
+	Retain            bool
+	Wait              time.Duration
+	WaitFor           []string
+	FailOnWaitTimeout bool
+	Kubeconfig        string
+	LockTimeout       time.Duration
+	Labels            []string
+	Annotations       []string
+	// DefaultImage is the node image from the kind settings
+	DefaultImage string
 }
 
 // NewCommand returns a new cobra.Command for cluster creation
@@ -51,6 +61,14 @@ func NewCommand(logger log.Logger, streams cmd.IOStreams) *cobra.Command {
 		Long:  "Creates a local Kubernetes cluster using Docker container 'nodes'",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			cli.OverrideDefaultName(cmd.Flags())
+			applySettings(cmd.Flags(), flags)
+			if flags.Wait == 0 {
+				for _, name := range []string{"wait-for", "fail-on-wait-timeout"} {
+					if cmd.Flags().Changed(name) {
+						return errors.Errorf("--%s requires --wait", name)
+					}
+				}
+			}
 			return runE(logger, streams, flags)
 		},
 	}
@@ -85,12 +103,44 @@ func NewCommand(logger log.Logger, streams cmd.IOStreams) *cobra.Command {
 		time.Duration(0),
 		"wait for control plane node to be ready (default 0s)",
 	)
+	cmd.Flags().StringSliceVar(
+		&flags.WaitFor,
+		"wait-for",
+		nil,
+		"what --wait waits for, any of control-plane,nodes,system-pods,cni,storage,dns (default control-plane)",
+	)
+	cmd.Flags().BoolVar(
+		&flags.FailOnWaitTimeout,
+		"fail-on-wait-timeout",
+		false,
+		"fail cluster creation if --wait times out, instead of warning",
+	)
 	cmd.Flags().StringVar(
 		&flags.Kubeconfig,
 		"kubeconfig",
 		"",
 		"sets kubeconfig path instead of $KUBECONFIG or $HOME/.kube/config",
 	)
+	cmd.Flags().DurationVar(
+		&flags.LockTimeout,
+		"lock-timeout",
+		time.Duration(0),
+		"wait for another kind process creating or deleting a cluster with the same name (default 0s)",
+	)
+	cmd.Flags().StringArrayVar(
+		&flags.Labels,
+		"label",
+		nil,
+		"key=value label to add to the cluster, may be repeated, overrides labels in the config",
+	)
+	cmd.Flags().StringArrayVar(
+		&flags.Annotations,
+		"annotation",
+		nil,
+		"key=value annotation to add to the cluster, may be repeated, overrides annotations in the config",
+	)
+	completion.RegisterFlag(cmd, "image", completion.NodeImages)
+	completion.RegisterFlag(cmd, "wait-for", completion.ValueList("control-plane", "nodes", "system-pods", "cni", "storage", "dns"))
 	return cmd
 }
 
@@ -98,6 +148,7 @@ func runE(logger log.Logger, streams cmd.IOStreams, flags *flagpole) error {
 	provider := cluster.NewProvider(
 		cluster.ProviderWithLogger(logger),
 		runtime.GetDefault(logger),
+		runtime.GetKubeConfigStrategy(logger),
 	)
 
 	// handle config flag, we might need to read from stdin
@@ -106,14 +157,29 @@ func runE(logger log.Logger, streams cmd.IOStreams, flags *flagpole) error {
 		return err
 	}
 
+	labels, err := cli.ParseKeyValues("label", flags.Labels)
+	if err != nil {
+		return err
+	}
+	annotations, err := cli.ParseKeyValues("annotation", flags.Annotations)
+	if err != nil {
+		return err
+	}
+
 	// create the cluster
 	if err = provider.Create(
 		flags.Name,
 		withConfig,
 		cluster.CreateWithNodeImage(flags.ImageName),
+		cluster.CreateWithDefaultNodeImage(flags.DefaultImage),
 		cluster.CreateWithRetain(flags.Retain),
 		cluster.CreateWithWaitForReady(flags.Wait),
+		cluster.CreateWithWaitFor(flags.WaitFor),
+		cluster.CreateWithFailOnWaitTimeout(flags.FailOnWaitTimeout),
 		cluster.CreateWithKubeconfigPath(flags.Kubeconfig),
+		cluster.CreateWithLockTimeout(flags.LockTimeout),
+		cluster.CreateWithLabels(labels),
+		cluster.CreateWithAnnotations(annotations),
 		cluster.CreateWithDisplayUsage(true),
 		cluster.CreateWithDisplaySalutation(true),
 	); err != nil {
@@ -123,6 +189,20 @@ func runE(logger log.Logger, streams cmd.IOStreams, flags *flagpole) error {
 	return nil
 }
 
+// applySettings defaults flags that were not set from the kind settings file
+func applySettings(fs *pflag.FlagSet, flags *flagpole) {
+	s := settings.Get()
+	if !fs.Changed("config") && s.Config != "" {
+		flags.Config = s.Config
+	}
+	if !fs.Changed("wait") && s.Wait != "" {
+		flags.Wait = s.WaitDuration()
+	}
+	if !fs.Changed("image") {
+		flags.DefaultImage = s.NodeImage
+	}
+}
+
 // configOption converts the raw --config flag value to a cluster creation
 // option matching it. it will read from stdin if the flag value is `-`
 func configOption(rawConfigFlag string, stdin io.Reader) (cluster.CreateOption, error) {
@@ -18,6 +18,8 @@ limitations under the License.
 package cluster
 
 import (
+	"time"
+
 	"github.com/spf13/cobra"
 
 	"sigs.k8s.io/kind/pkg/cluster"
@@ -26,12 +28,15 @@ import (
 	"sigs.k8s.io/kind/pkg/log"
 
 	"sigs.k8s.io/kind/pkg/internal/cli"
+	"sigs.k8s.io/kind/pkg/internal/completion"
 	"sigs.k8s.io/kind/pkg/internal/runtime"
 )
 
 type flagpole struct {
-	Name       string
-	Kubeconfig string
+	Name        string
+	Kubeconfig  string
+	LockTimeout time.Duration
+	Force       bool
 }
 
 // NewCommand returns a new cobra.Command for cluster deletion
@@ -48,6 +53,10 @@ failing (like "rm -f"). If the cluster resources exist they will be deleted, and
 if the cluster is already gone it will just return success.
 
 Errors will only occur if the cluster resources exist and are not able to be deleted.
+
+If another kind process is creating or deleting the cluster, this waits up to
+--lock-timeout for it to finish. With --force the cluster is deleted anyway
+after the timeout, which makes the other process fail.
 `,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			cli.OverrideDefaultName(cmd.Flags())
@@ -67,6 +76,19 @@ Errors will only occur if the cluster resources exist and are not able to be del
 		"",
 		"sets kubeconfig path instead of $KUBECONFIG or $HOME/.kube/config",
 	)
+	cmd.Flags().DurationVar(
+		&flags.LockTimeout,
+		"lock-timeout",
+		5*time.Minute,
+		"wait for another kind process creating or deleting the cluster",
+	)
+	cmd.Flags().BoolVar(
+		&flags.Force,
+		"force",
+		false,
+		"delete the cluster even if another kind process is still creating or deleting it after --lock-timeout",
+	)
+	completion.RegisterFlag(cmd, "name", completion.ClusterNames)
 	return cmd
 }
 
@@ -77,7 +99,11 @@ func deleteCluster(logger log.Logger, flags *flagpole) error {
 	)
 	// Delete individual cluster
 	logger.V(0).Infof("Deleting cluster %q ...", flags.Name)
-	if err := provider.Delete(flags.Name, flags.Kubeconfig); err != nil {
+	if err := provider.DeleteWithOptions(
+		flags.Name, flags.Kubeconfig,
+		cluster.DeleteWithLockTimeout(flags.LockTimeout),
+		cluster.DeleteWithForce(flags.Force),
+	); err != nil {
 		return errors.Wrapf(err, "failed to delete cluster %q", flags.Name)
 	}
 	return nil
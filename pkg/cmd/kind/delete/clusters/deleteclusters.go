@@ -18,6 +18,8 @@ limitations under the License.
 package clusters
 
 import (
+	"time"
+
 	"github.com/spf13/cobra"
 
 	"sigs.k8s.io/kind/pkg/cluster"
@@ -25,21 +27,31 @@ import (
 	"sigs.k8s.io/kind/pkg/errors"
 	"sigs.k8s.io/kind/pkg/log"
 
+	"sigs.k8s.io/kind/pkg/internal/cli"
+	"sigs.k8s.io/kind/pkg/internal/completion"
 	"sigs.k8s.io/kind/pkg/internal/runtime"
 )
 
 type flagpole struct {
 	Kubeconfig string
 	All        bool
+	OlderThan  time.Duration
+	Selector   string
+	SkipActive bool
+	DryRun     bool
+	// LockTimeout and Force are as for `kind delete cluster`
+	LockTimeout time.Duration
+	Force       bool
 }
 
 // NewCommand returns a new cobra.Command for cluster deletion
 func NewCommand(logger log.Logger, streams cmd.IOStreams) *cobra.Command {
 	flags := &flagpole{}
 	cmd := &cobra.Command{
-		Args:  cobra.MinimumNArgs(0),
-		Use:   "clusters",
-		Short: "Deletes one or more clusters",
+		Args:              cobra.MinimumNArgs(0),
+		Use:               "clusters",
+		ValidArgsFunction: completion.ClusterNameArgs(-1),
+		Short:             "Deletes one or more clusters",
 		Long: `Deletes one or more Kind clusters from the system.
 
 This is an idempotent operation, meaning it may be called multiple times without
@@ -47,9 +59,17 @@ failing (like "rm -f"). If the cluster resources exist they will be deleted, and
 if the cluster is already gone it will just return success.
 
 Errors will only occur if the cluster resources exist and are not able to be deleted.
+
+--older-than and --selector filter the named clusters, or all clusters if
+none are named, E.G. to clean up clusters leaked by cancelled CI jobs:
+
+  kind delete clusters --older-than 2h --selector owner=ci
+
+--skip-active only narrows down the clusters selected by name, --all or the
+filters above, on its own it does not select any clusters.
 `,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if !flags.All && len(args) == 0 {
+			if !flags.All && len(args) == 0 && !hasFilters(flags) {
 				return errors.New("no cluster names provided")
 			}
 
@@ -69,29 +89,146 @@ Errors will only occur if the cluster resources exist and are not able to be del
 		false,
 		"delete all clusters",
 	)
+	cmd.Flags().DurationVar(
+		&flags.OlderThan,
+		"older-than",
+		0,
+		"only delete clusters created longer ago than this duration (e.g. 2h)",
+	)
+	cmd.Flags().StringVarP(
+		&flags.Selector,
+		"selector",
+		"l",
+		"",
+		"only delete clusters matching this label selector (e.g. -l owner=ci,!keep)",
+	)
+	cmd.Flags().BoolVar(
+		&flags.SkipActive,
+		"skip-active",
+		false,
+		"skip clusters with clients connected to the API server from outside the cluster",
+	)
+	cmd.Flags().BoolVar(
+		&flags.DryRun,
+		"dry-run",
+		false,
+		"only list the clusters that would be deleted",
+	)
+	cmd.Flags().DurationVar(
+		&flags.LockTimeout,
+		"lock-timeout",
+		5*time.Minute,
+		"wait for other kind processes creating or deleting the clusters",
+	)
+	cmd.Flags().BoolVar(
+		&flags.Force,
+		"force",
+		false,
+		"delete clusters even if another kind process is still creating or deleting them after --lock-timeout",
+	)
 	return cmd
 }
 
+// hasFilters returns true if the flags select clusters by themselves,
+// --skip-active only narrows down the named or all clusters
+func hasFilters(flags *flagpole) bool {
+	return flags.OlderThan > 0 || flags.Selector != ""
+}
+
 func deleteClusters(logger log.Logger, flags *flagpole, clusters []string) error {
+	selector, err := cli.ParseSelector(flags.Selector)
+	if err != nil {
+		return err
+	}
+	if flags.OlderThan < 0 {
+		return errors.New("--older-than must not be negative")
+	}
 	provider := cluster.NewProvider(
 		cluster.ProviderWithLogger(logger),
 		runtime.GetDefault(logger),
 	)
-	var err error
-	if flags.All {
+	if flags.All || (len(clusters) == 0 && hasFilters(flags)) {
 		//Delete all clusters
 		if clusters, err = provider.List(); err != nil {
 			return errors.Wrap(err, "failed listing clusters for delete")
 		}
 	}
+	if flags.OlderThan > 0 || !selector.Empty() {
+		infos, err := provider.ListClustersWithInfo()
+		if err != nil {
+			return errors.Wrap(err, "failed listing clusters for delete")
+		}
+		clusters = selectClusters(logger, infos, clusters, flags.OlderThan, selector, time.Now())
+	}
+	if flags.SkipActive {
+		clusters = skipActive(logger, provider, clusters)
+	}
+
+	if flags.DryRun {
+		logger.V(0).Infof("Would delete clusters: %q", clusters)
+		return nil
+	}
 	var success []string
-	for _, cluster := range clusters {
-		if err = provider.Delete(cluster, flags.Kubeconfig); err != nil {
-			logger.V(0).Infof("%s\n", errors.Wrapf(err, "failed to delete cluster %q", cluster))
+	options := []cluster.DeleteOption{
+		cluster.DeleteWithLockTimeout(flags.LockTimeout),
+		cluster.DeleteWithForce(flags.Force),
+	}
+	for _, name := range clusters {
+		if err = provider.DeleteWithOptions(name, flags.Kubeconfig, options...); err != nil {
+			logger.V(0).Infof("%s\n", errors.Wrapf(err, "failed to delete cluster %q", name))
 			continue
 		}
-		success = append(success, cluster)
+		success = append(success, name)
 	}
 	logger.V(0).Infof("Deleted clusters: %q", success)
 	return nil
 }
+
+// selectClusters returns the names of the candidate clusters that are older
+// than olderThan, if non-zero, and match selector.
+// Clusters without info, E.G. because they no longer exist, are not selected.
+func selectClusters(logger log.Logger, infos []cluster.ClusterInfo, candidates []string, olderThan time.Duration, selector cli.Selector, now time.Time) []string {
+	byName := make(map[string]cluster.ClusterInfo, len(infos))
+	for _, info := range infos {
+		byName[info.Name] = info
+	}
+	selected := []string{}
+	for _, name := range candidates {
+		info, exists := byName[name]
+		if !exists {
+			logger.V(1).Infof("Skipping cluster %q without info", name)
+			continue
+		}
+		if olderThan > 0 {
+			if info.CreationTimestamp.IsZero() {
+				logger.V(1).Infof("Skipping cluster %q with unknown creation time", name)
+				continue
+			}
+			if now.Sub(info.CreationTimestamp) < olderThan {
+				continue
+			}
+		}
+		if !selector.Matches(info.Labels) {
+			continue
+		}
+		selected = append(selected, name)
+	}
+	return selected
+}
+
+// skipActive returns the clusters without external API server clients,
+// clusters that cannot be checked are kept to avoid leaking them
+func skipActive(logger log.Logger, provider *cluster.Provider, clusters []string) []string {
+	inactive := []string{}
+	for _, name := range clusters {
+		clients, err := provider.APIServerClients(name)
+		if err != nil {
+			logger.Warnf("Failed to check API server clients of cluster %q: %v", name, err)
+		} else if clients > 0 {
+			logger.V(0).Infof("Skipping cluster %q with %d active API server client(s)", name, clients)
+			continue
+		}
+		inactive = append(inactive, name)
+	}
+	return inactive
+}
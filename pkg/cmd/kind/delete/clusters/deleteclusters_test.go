@@ -0,0 +1,99 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusters
+
+import (
+	"testing"
+	"time"
+
+	"sigs.k8s.io/kind/pkg/cluster"
+	"sigs.k8s.io/kind/pkg/cmd"
+	"sigs.k8s.io/kind/pkg/internal/assert"
+	"sigs.k8s.io/kind/pkg/internal/cli"
+	"sigs.k8s.io/kind/pkg/log"
+)
+
+func TestSelectClusters(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2024, 5, 2, 12, 0, 0, 0, time.UTC)
+	infos := []cluster.ClusterInfo{
+		{Name: "old-ci", CreationTimestamp: now.Add(-3 * time.Hour), Labels: map[string]string{"owner": "ci"}},
+		{Name: "new-ci", CreationTimestamp: now.Add(-time.Hour), Labels: map[string]string{"owner": "ci"}},
+		{Name: "old-dev", CreationTimestamp: now.Add(-3 * time.Hour)},
+		{Name: "unknown-age", Labels: map[string]string{"owner": "ci"}},
+	}
+	all := []string{"old-ci", "new-ci", "old-dev", "unknown-age", "gone"}
+	cases := []struct {
+		name       string
+		candidates []string
+		olderThan  time.Duration
+		selector   string
+		expected   []string
+	}{
+		{
+			name:       "older than",
+			candidates: all,
+			olderThan:  2 * time.Hour,
+			expected:   []string{"old-ci", "old-dev"},
+		},
+		{
+			name:       "selector",
+			candidates: all,
+			selector:   "owner=ci",
+			expected:   []string{"old-ci", "new-ci", "unknown-age"},
+		},
+		{
+			name:       "older than and selector",
+			candidates: all,
+			olderThan:  2 * time.Hour,
+			selector:   "owner=ci",
+			expected:   []string{"old-ci"},
+		},
+		{
+			name:       "named candidates",
+			candidates: []string{"old-dev"},
+			olderThan:  2 * time.Hour,
+			expected:   []string{"old-dev"},
+		},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			selector, err := cli.ParseSelector(tc.selector)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			selected := selectClusters(log.NoopLogger{}, infos, tc.candidates, tc.olderThan, selector, now)
+			assert.DeepEqual(t, tc.expected, selected)
+		})
+	}
+}
+
+func TestSkipActiveAloneSelectsNothing(t *testing.T) {
+	t.Parallel()
+	assert.BoolEqual(t, false, hasFilters(&flagpole{SkipActive: true}))
+
+	// without names or --all, --skip-active must not select all clusters
+	c := NewCommand(log.NoopLogger{}, cmd.IOStreams{})
+	c.SetArgs([]string{"--skip-active", "--dry-run"})
+	c.SilenceUsage = true
+	c.SilenceErrors = true
+	if err := c.Execute(); err == nil {
+		t.Errorf("expected an error for --skip-active without cluster names")
+	}
+}
@@ -0,0 +1,221 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package images implements the `images` command
+package images
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/kind/pkg/cluster"
+	"sigs.k8s.io/kind/pkg/cluster/nodeutils"
+	"sigs.k8s.io/kind/pkg/cmd"
+	"sigs.k8s.io/kind/pkg/errors"
+	"sigs.k8s.io/kind/pkg/log"
+
+	"sigs.k8s.io/kind/pkg/internal/cli"
+	"sigs.k8s.io/kind/pkg/internal/completion"
+	"sigs.k8s.io/kind/pkg/internal/imageload"
+	"sigs.k8s.io/kind/pkg/internal/runtime"
+)
+
+type flagpole struct {
+	Name  string
+	Nodes []string
+	Force bool
+}
+
+// NewCommand returns a new cobra.Command for deleting images from nodes
+func NewCommand(logger log.Logger, streams cmd.IOStreams) *cobra.Command {
+	flags := &flagpole{}
+	cmd := &cobra.Command{
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) < 1 {
+				return errors.New("a list of images is required")
+			}
+			return nil
+		},
+		Use:   "images <IMAGE> [IMAGE...]",
+		Short: "Deletes images from the cluster's nodes",
+		Long: `Deletes images from the cluster's nodes by tag, digest or ID.
+
+Deleting a tag untags the image, the image is deleted once no tags remain.
+Deleting by digest or ID deletes the image with all of its tags.
+Pinned images, such as the images preloaded into the node image, are only
+deleted with --force.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cli.OverrideDefaultName(cmd.Flags())
+			return runE(logger, flags, args)
+		},
+	}
+	cmd.Flags().StringVarP(
+		&flags.Name,
+		"name",
+		"n",
+		cluster.DefaultName,
+		"the cluster context name",
+	)
+	cmd.Flags().StringSliceVar(
+		&flags.Nodes,
+		"node",
+		nil,
+		"the nodes to delete images from, default all nodes",
+	)
+	cmd.Flags().BoolVar(
+		&flags.Force,
+		"force",
+		false,
+		"also delete pinned images",
+	)
+	completion.RegisterFlag(cmd, "name", completion.ClusterNames)
+	completion.RegisterFlag(cmd, "node", completion.NodeNameList)
+	return cmd
+}
+
+func runE(logger log.Logger, flags *flagpole, refs []string) error {
+	provider := cluster.NewProvider(
+		cluster.ProviderWithLogger(logger),
+		runtime.GetDefault(logger),
+	)
+	nodeList, err := provider.ListInternalNodes(flags.Name)
+	if err != nil {
+		return err
+	}
+	if len(nodeList) == 0 {
+		return fmt.Errorf("no nodes found for cluster %q", flags.Name)
+	}
+	selected, err := imageload.SelectNodes(nodeList, flags.Nodes)
+	if err != nil {
+		return err
+	}
+
+	var mu sync.Mutex
+	found := map[string]bool{}
+	fns := []func() error{}
+	for _, node := range selected {
+		node := node // capture loop variable
+		fns = append(fns, func() error {
+			images, err := nodeutils.ListImages(node)
+			if err != nil {
+				return errors.Wrapf(err, "failed to list images of node %s", node)
+			}
+			p := planRemoval(images, refs, flags.Force)
+			mu.Lock()
+			for ref := range p.found {
+				found[ref] = true
+			}
+			mu.Unlock()
+			for _, ref := range p.pinned {
+				logger.Warnf("Not deleting pinned image %q from node %s, use --force to delete it", ref, node)
+			}
+			if len(p.remove) == 0 {
+				return nil
+			}
+			if err := nodeutils.RemoveImages(node, p.remove...); err != nil {
+				return errors.Wrapf(err, "failed to delete images from node %s", node)
+			}
+			logger.V(0).Infof("Deleted from node %s: %s", node, strings.Join(p.remove, ", "))
+			return nil
+		})
+	}
+	if err := errors.AggregateConcurrent(fns); err != nil {
+		return err
+	}
+
+	missing := []string{}
+	for _, ref := range refs {
+		if !found[ref] {
+			missing = append(missing, ref)
+		}
+	}
+	if len(missing) > 0 {
+		return errors.Errorf("images not found on any node: %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// plan is the references to remove from a node for the requested references
+type plan struct {
+	// remove are the image references to remove
+	remove []string
+	// pinned are the requested references of pinned images, which are kept
+	pinned []string
+	// found are the requested references matching an image
+	found map[string]bool
+}
+
+// planRemoval plans removing refs from images, untagging images by tag and
+// removing every reference of images that have no tags left or are
+// requested by digest or ID
+func planRemoval(images []nodeutils.Image, refs []string, force bool) *plan {
+	p := &plan{found: map[string]bool{}}
+	for _, img := range images {
+		whole := false
+		untag := map[string]bool{}
+		for _, ref := range refs {
+			sanitized := imageload.SanitizeImage(ref)
+			switch {
+			case matchesID(img.ID, ref) || contains(img.RepoDigests, sanitized):
+				whole = true
+			case contains(img.RepoTags, sanitized):
+				untag[sanitized] = true
+			default:
+				continue
+			}
+			p.found[ref] = true
+			if img.Pinned && !force {
+				p.pinned = append(p.pinned, ref)
+			}
+		}
+		if img.Pinned && !force {
+			continue
+		}
+		if whole || (len(untag) > 0 && len(untag) == len(img.RepoTags)) {
+			// no tags remain, so remove the image
+			p.remove = append(p.remove, img.RepoTags...)
+			p.remove = append(p.remove, img.RepoDigests...)
+			p.remove = append(p.remove, img.ID)
+			continue
+		}
+		for _, tag := range img.RepoTags {
+			if untag[tag] {
+				p.remove = append(p.remove, tag)
+			}
+		}
+	}
+	return p
+}
+
+// matchesID returns true if ref is id, or an unambiguous prefix of it as
+// printed by kind get images
+func matchesID(id, ref string) bool {
+	id = strings.TrimPrefix(id, "sha256:")
+	ref = strings.TrimPrefix(ref, "sha256:")
+	return ref == id || (len(ref) >= 12 && strings.HasPrefix(id, ref))
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,112 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package images
+
+import (
+	"testing"
+
+	"sigs.k8s.io/kind/pkg/cluster/nodeutils"
+	"sigs.k8s.io/kind/pkg/internal/assert"
+)
+
+func TestPlanRemoval(t *testing.T) {
+	t.Parallel()
+	images := []nodeutils.Image{
+		{
+			ID:          "sha256:9f1c2d3e4f5a6b7c8d9e",
+			RepoTags:    []string{"docker.io/kindest/kindnetd:v1"},
+			RepoDigests: []string{"docker.io/kindest/kindnetd@sha256:aaaa"},
+			Pinned:      true,
+		},
+		{
+			ID:          "sha256:a4160b1c2d3e4f5a6b7c",
+			RepoTags:    []string{"docker.io/library/app:1.0", "docker.io/library/app:latest"},
+			RepoDigests: []string{"docker.io/library/app@sha256:bbbb"},
+		},
+	}
+	cases := []struct {
+		Name     string
+		Refs     []string
+		Force    bool
+		Remove   []string
+		Pinned   []string
+		NotFound []string
+	}{
+		{
+			Name:   "untag one of several tags",
+			Refs:   []string{"app:1.0"},
+			Remove: []string{"docker.io/library/app:1.0"},
+		},
+		{
+			Name: "untag all tags",
+			Refs: []string{"app:1.0", "app"},
+			Remove: []string{
+				"docker.io/library/app:1.0",
+				"docker.io/library/app:latest",
+				"docker.io/library/app@sha256:bbbb",
+				"sha256:a4160b1c2d3e4f5a6b7c",
+			},
+		},
+		{
+			Name: "by short ID",
+			Refs: []string{"a4160b1c2d3e"},
+			Remove: []string{
+				"docker.io/library/app:1.0",
+				"docker.io/library/app:latest",
+				"docker.io/library/app@sha256:bbbb",
+				"sha256:a4160b1c2d3e4f5a6b7c",
+			},
+		},
+		{
+			Name:     "ambiguous short ID",
+			Refs:     []string{"a416"},
+			NotFound: []string{"a416"},
+		},
+		{
+			Name:   "pinned",
+			Refs:   []string{"kindest/kindnetd:v1"},
+			Pinned: []string{"kindest/kindnetd:v1"},
+		},
+		{
+			Name:  "pinned with force",
+			Refs:  []string{"kindest/kindnetd@sha256:aaaa"},
+			Force: true,
+			Remove: []string{
+				"docker.io/kindest/kindnetd:v1",
+				"docker.io/kindest/kindnetd@sha256:aaaa",
+				"sha256:9f1c2d3e4f5a6b7c8d9e",
+			},
+		},
+		{
+			Name:     "not found",
+			Refs:     []string{"app:2.0"},
+			NotFound: []string{"app:2.0"},
+		},
+	}
+	for _, tc := range cases {
+		tc := tc // capture range variable
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+			p := planRemoval(images, tc.Refs, tc.Force)
+			assert.DeepEqual(t, tc.Remove, p.remove)
+			assert.DeepEqual(t, tc.Pinned, p.pinned)
+			for _, ref := range tc.Refs {
+				assert.BoolEqual(t, !contains(tc.NotFound, ref), p.found[ref])
+			}
+		})
+	}
+}
@@ -0,0 +1,111 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package doctor implements the `doctor` command
+package doctor
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/kind/pkg/cluster"
+	"sigs.k8s.io/kind/pkg/cmd"
+	"sigs.k8s.io/kind/pkg/errors"
+	"sigs.k8s.io/kind/pkg/log"
+
+	"sigs.k8s.io/kind/pkg/internal/runtime"
+)
+
+type flagpole struct {
+	Config     string
+	FailOnWarn bool
+}
+
+// NewCommand returns a new cobra.Command for checking the host environment
+func NewCommand(logger log.Logger, streams cmd.IOStreams) *cobra.Command {
+	flags := &flagpole{}
+	cmd := &cobra.Command{
+		Args:  cobra.NoArgs,
+		Use:   "doctor",
+		Short: "Checks the host environment for common problems",
+		Long: `Checks the host environment for common causes of cluster creation failures,
+such as low inotify limits, cgroup v1, busy host ports or low disk space.
+
+Each check reports PASS, WARN or FAIL, with remediation for problems.
+The command exits non-zero if any check fails, or with --fail-on-warn if any
+check warns, so that it may be used to gate CI jobs.
+
+  kind doctor
+  kind doctor --config kind-config.yaml --fail-on-warn
+`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runE(logger, streams, flags)
+		},
+	}
+	cmd.Flags().StringVar(
+		&flags.Config,
+		"config",
+		"",
+		"path to the kind config file the cluster would be created with",
+	)
+	cmd.Flags().BoolVar(
+		&flags.FailOnWarn,
+		"fail-on-warn",
+		false,
+		"exit non-zero if any check warns",
+	)
+	return cmd
+}
+
+func runE(logger log.Logger, streams cmd.IOStreams, flags *flagpole) error {
+	provider := cluster.NewProvider(
+		cluster.ProviderWithLogger(logger),
+		runtime.GetDefault(logger),
+	)
+	results, err := provider.Doctor(flags.Config)
+	if err != nil {
+		return err
+	}
+	printResults(streams.Out, results)
+	failed, warned := 0, 0
+	for _, result := range results {
+		switch result.Status {
+		case cluster.DoctorFail:
+			failed++
+		case cluster.DoctorWarn:
+			warned++
+		}
+	}
+	if failed > 0 {
+		return errors.Errorf("%d check(s) failed", failed)
+	}
+	if flags.FailOnWarn && warned > 0 {
+		return errors.Errorf("%d check(s) warned", warned)
+	}
+	return nil
+}
+
+func printResults(w io.Writer, results []cluster.DoctorResult) {
+	for _, result := range results {
+		fmt.Fprintf(w, "[%s] %s: %s\n", strings.ToUpper(string(result.Status)), result.Check, result.Message)
+		if result.Remediation != "" {
+			fmt.Fprintf(w, "       %s\n", result.Remediation)
+		}
+	}
+}
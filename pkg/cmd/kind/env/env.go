@@ -0,0 +1,124 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package env implements the `env` command
+package env
+
+import (
+	"fmt"
+	"strings"
+
+	"al.essio.dev/pkg/shellescape"
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/kind/pkg/cluster"
+	"sigs.k8s.io/kind/pkg/cmd"
+	"sigs.k8s.io/kind/pkg/errors"
+	"sigs.k8s.io/kind/pkg/log"
+
+	"sigs.k8s.io/kind/pkg/internal/cli"
+	"sigs.k8s.io/kind/pkg/internal/completion"
+	"sigs.k8s.io/kind/pkg/internal/runtime"
+)
+
+type flagpole struct {
+	Name     string
+	Shell    string
+	Internal bool
+}
+
+// NewCommand returns a new cobra.Command for printing the cluster environment
+func NewCommand(logger log.Logger, streams cmd.IOStreams) *cobra.Command {
+	flags := &flagpole{}
+	cmd := &cobra.Command{
+		Args:              cobra.MaximumNArgs(1),
+		Use:               "env [cluster]",
+		ValidArgsFunction: completion.ClusterNameArgs(1),
+		Short:             "Prints shell commands pointing KUBECONFIG at a cluster",
+		Long: `Prints shell commands setting KUBECONFIG to the cluster's own kubeconfig file,
+exporting the cluster's kubeconfig to it first.
+
+The cluster is the argument if set, otherwise --name.
+
+  eval "$(kind env)"
+  eval "$(kind env kind-2)"
+  kind env --shell fish | source
+  kind env --shell powershell | Invoke-Expression
+`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cli.OverrideDefaultName(cmd.Flags())
+			if len(args) == 1 {
+				flags.Name = args[0]
+			}
+			return runE(logger, streams, flags)
+		},
+	}
+	cmd.Flags().StringVarP(
+		&flags.Name,
+		"name",
+		"n",
+		cluster.DefaultName,
+		"the cluster context name",
+	)
+	cmd.Flags().StringVar(
+		&flags.Shell,
+		"shell",
+		"bash",
+		"the shell syntax to print, one of: bash, zsh, fish, powershell",
+	)
+	cmd.Flags().BoolVar(
+		&flags.Internal,
+		"internal",
+		false,
+		"use internal address instead of external",
+	)
+	completion.RegisterFlag(cmd, "name", completion.ClusterNames)
+	return cmd
+}
+
+func runE(logger log.Logger, streams cmd.IOStreams, flags *flagpole) error {
+	// validate before exporting anything
+	if _, err := setEnv(flags.Shell, "KUBECONFIG", ""); err != nil {
+		return err
+	}
+	provider := cluster.NewProvider(
+		cluster.ProviderWithLogger(logger),
+		runtime.GetDefault(logger),
+	)
+	path, err := provider.ExportIsolatedKubeConfig(flags.Name, flags.Internal)
+	if err != nil {
+		return err
+	}
+	out, err := setEnv(flags.Shell, "KUBECONFIG", path)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(streams.Out, out)
+	return nil
+}
+
+// setEnv returns the shell command setting the environment variable key
+func setEnv(shell, key, value string) (string, error) {
+	switch shell {
+	case "bash", "zsh", "sh":
+		return fmt.Sprintf("export %s=%s", key, shellescape.Quote(value)), nil
+	case "fish":
+		return fmt.Sprintf("set -gx %s %s;", key, shellescape.Quote(value)), nil
+	case "powershell", "pwsh":
+		return fmt.Sprintf("$Env:%s = '%s'", key, strings.ReplaceAll(value, "'", "''")), nil
+	}
+	return "", errors.Errorf("unknown shell %q, must be one of: bash, zsh, fish, powershell", shell)
+}
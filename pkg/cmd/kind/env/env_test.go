@@ -0,0 +1,73 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package env
+
+import (
+	"testing"
+
+	"sigs.k8s.io/kind/pkg/internal/assert"
+)
+
+func TestSetEnv(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		Name        string
+		Shell       string
+		Value       string
+		Expected    string
+		ExpectError bool
+	}{
+		{
+			Name:     "bash",
+			Shell:    "bash",
+			Value:    "/home/user/.cache/kind/kubeconfigs/kind.kubeconfig",
+			Expected: "export KUBECONFIG=/home/user/.cache/kind/kubeconfigs/kind.kubeconfig",
+		},
+		{
+			Name:     "zsh with spaces",
+			Shell:    "zsh",
+			Value:    "/Users/some user/Library/Caches/kind/kubeconfigs/kind.kubeconfig",
+			Expected: "export KUBECONFIG='/Users/some user/Library/Caches/kind/kubeconfigs/kind.kubeconfig'",
+		},
+		{
+			Name:     "fish",
+			Shell:    "fish",
+			Value:    "/home/user/.cache/kind/kubeconfigs/kind.kubeconfig",
+			Expected: "set -gx KUBECONFIG /home/user/.cache/kind/kubeconfigs/kind.kubeconfig;",
+		},
+		{
+			Name:     "powershell",
+			Shell:    "powershell",
+			Value:    `C:\Users\o'brien\AppData\Local\kind\kubeconfigs\kind.kubeconfig`,
+			Expected: `$Env:KUBECONFIG = 'C:\Users\o''brien\AppData\Local\kind\kubeconfigs\kind.kubeconfig'`,
+		},
+		{
+			Name:        "unknown shell",
+			Shell:       "tcsh",
+			ExpectError: true,
+		},
+	}
+	for _, tc := range cases {
+		tc := tc // capture range variable
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+			out, err := setEnv(tc.Shell, "KUBECONFIG", tc.Value)
+			assert.ExpectError(t, tc.ExpectError, err)
+			assert.StringEqual(t, tc.Expected, out)
+		})
+	}
+}
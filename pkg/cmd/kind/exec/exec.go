@@ -0,0 +1,206 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package exec implements the `exec` command
+package exec
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/kind/pkg/cluster"
+	"sigs.k8s.io/kind/pkg/cluster/nodes"
+	"sigs.k8s.io/kind/pkg/cluster/nodeutils"
+	"sigs.k8s.io/kind/pkg/cmd"
+	"sigs.k8s.io/kind/pkg/errors"
+	kindexec "sigs.k8s.io/kind/pkg/exec"
+	"sigs.k8s.io/kind/pkg/log"
+
+	"sigs.k8s.io/kind/pkg/internal/cli"
+	"sigs.k8s.io/kind/pkg/internal/completion"
+	"sigs.k8s.io/kind/pkg/internal/runtime"
+)
+
+type flagpole struct {
+	Name     string
+	Node     string
+	AllNodes bool
+	Stdin    bool
+	TTY      bool
+}
+
+// NewCommand returns a new cobra.Command for running commands on nodes
+func NewCommand(logger log.Logger, streams cmd.IOStreams) *cobra.Command {
+	flags := &flagpole{}
+	cmd := &cobra.Command{
+		Args:  cobra.MinimumNArgs(1),
+		Use:   "exec [flags] -- COMMAND [ARG...]",
+		Short: "Runs a command on a node",
+		Long: `Runs a command on a node of the cluster, the bootstrap control plane node by default.
+
+This works with every node provider, E.G. for an interactive shell:
+
+  kind exec -it -- bash
+`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cli.OverrideDefaultName(cmd.Flags())
+			return runE(logger, streams, flags, args)
+		},
+	}
+	// everything after the command is an argument to it
+	cmd.Flags().SetInterspersed(false)
+	cmd.Flags().StringVarP(
+		&flags.Name,
+		"name",
+		"n",
+		cluster.DefaultName,
+		"the cluster context name",
+	)
+	cmd.Flags().StringVar(
+		&flags.Node,
+		"node",
+		"",
+		"the node to run the command on (default the bootstrap control plane node)",
+	)
+	cmd.Flags().BoolVarP(
+		&flags.AllNodes,
+		"all-nodes",
+		"A",
+		false,
+		"run the command on every Kubernetes node concurrently, prefixing output with the node name",
+	)
+	cmd.Flags().BoolVarP(
+		&flags.Stdin,
+		"stdin",
+		"i",
+		false,
+		"pass stdin to the command",
+	)
+	cmd.Flags().BoolVarP(
+		&flags.TTY,
+		"tty",
+		"t",
+		false,
+		"allocate a TTY for the command",
+	)
+	completion.RegisterFlag(cmd, "name", completion.ClusterNames)
+	completion.RegisterFlag(cmd, "node", completion.NodeNames)
+	return cmd
+}
+
+func runE(logger log.Logger, streams cmd.IOStreams, flags *flagpole, args []string) error {
+	if flags.AllNodes && flags.Node != "" {
+		return errors.New("--all-nodes and --node may not be used together")
+	}
+	if flags.AllNodes && (flags.Stdin || flags.TTY) {
+		return errors.New("--stdin and --tty may not be used with --all-nodes")
+	}
+
+	provider := cluster.NewProvider(
+		cluster.ProviderWithLogger(logger),
+		runtime.GetDefault(logger),
+	)
+	allNodes, err := provider.ListNodes(flags.Name)
+	if err != nil {
+		return err
+	}
+	if len(allNodes) == 0 {
+		return errors.Errorf("no nodes found for cluster %q", flags.Name)
+	}
+
+	if flags.AllNodes {
+		// only the Kubernetes nodes, not E.G. the load balancer
+		kubeNodes, err := nodeutils.InternalNodes(allNodes)
+		if err != nil {
+			return err
+		}
+		return execAll(streams, kubeNodes, args)
+	}
+
+	node, err := selectNode(allNodes, flags.Node)
+	if err != nil {
+		return err
+	}
+	// the output is not kept in memory, and the real terminal is handed to
+	// the command for --tty
+	c := node.Command(args[0], args[1:]...).
+		SetStdout(kindexec.Uncaptured(streams.Out)).
+		SetStderr(kindexec.Uncaptured(streams.ErrOut))
+	if flags.Stdin {
+		c.SetStdin(streams.In)
+	}
+	if flags.TTY {
+		if !maybeSetTTY(c) {
+			return errors.New("the node provider does not support --tty")
+		}
+	}
+	return c.Run()
+}
+
+// selectNode returns the node named name, or the bootstrap control plane
+// node if name is empty
+func selectNode(allNodes []nodes.Node, name string) (nodes.Node, error) {
+	if name == "" {
+		return nodeutils.BootstrapControlPlaneNode(allNodes)
+	}
+	for _, n := range allNodes {
+		if n.String() == name {
+			return n, nil
+		}
+	}
+	return nil, errors.Errorf("unknown node %q", name)
+}
+
+// execAll runs the command on all nodes concurrently, prefixing each line of
+// output with the node name
+func execAll(streams cmd.IOStreams, allNodes []nodes.Node, args []string) error {
+	var mu sync.Mutex
+	fns := []func() error{}
+	for _, n := range allNodes {
+		n := n // capture loop variable
+		fns = append(fns, func() error {
+			prefix := fmt.Sprintf("[%s] ", n.String())
+			stdout := cli.NewPrefixWriter(streams.Out, &mu, prefix)
+			stderr := cli.NewPrefixWriter(streams.ErrOut, &mu, prefix)
+			err := n.Command(args[0], args[1:]...).
+				SetStdout(kindexec.Uncaptured(stdout)).
+				SetStderr(kindexec.Uncaptured(stderr)).
+				Run()
+			_ = stdout.Flush()
+			_ = stderr.Flush()
+			if err != nil {
+				return errors.Wrapf(err, "command failed on node %q", n.String())
+			}
+			return nil
+		})
+	}
+	return errors.AggregateConcurrent(fns)
+}
+
+// maybeSetTTY will call c.SetTTY(true) if c has a SetTTY method,
+// returning false if it does not
+func maybeSetTTY(c kindexec.Cmd) bool {
+	type ttySetter interface {
+		SetTTY(bool) kindexec.Cmd
+	}
+	v, ok := c.(ttySetter)
+	if ok {
+		v.SetTTY(true)
+	}
+	return ok
+}
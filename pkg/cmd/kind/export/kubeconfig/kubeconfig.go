@@ -18,20 +18,28 @@ limitations under the License.
 package kubeconfig
 
 import (
+	"time"
+
 	"github.com/spf13/cobra"
 
 	"sigs.k8s.io/kind/pkg/cluster"
 	"sigs.k8s.io/kind/pkg/cmd"
+	"sigs.k8s.io/kind/pkg/errors"
 	"sigs.k8s.io/kind/pkg/log"
 
 	"sigs.k8s.io/kind/pkg/internal/cli"
+	"sigs.k8s.io/kind/pkg/internal/completion"
 	"sigs.k8s.io/kind/pkg/internal/runtime"
 )
 
 type flagpole struct {
-	Name       string
-	Kubeconfig string
-	Internal   bool
+	Name        string
+	Kubeconfig  string
+	Internal    bool
+	User        string
+	Groups      []string
+	TTL         time.Duration
+	ClusterRole string
 }
 
 // NewCommand returns a new cobra.Command for exporting the kubeconfig
@@ -41,9 +49,23 @@ func NewCommand(logger log.Logger, streams cmd.IOStreams) *cobra.Command {
 		Args:  cobra.NoArgs,
 		Use:   "kubeconfig",
 		Short: "Exports cluster kubeconfig",
-		Long:  "Exports cluster kubeconfig",
+		Long: `Exports cluster kubeconfig
+
+By default the kubeconfig uses the cluster admin identity. With --user, a client
+certificate for the user is signed by the cluster CA instead, and the user is
+added with their own context kind-<cluster>@<user>, for testing RBAC:
+
+  kind export kubeconfig --user alice --group dev --ttl 24h --cluster-role view
+`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			cli.OverrideDefaultName(cmd.Flags())
+			if flags.User == "" {
+				for _, name := range []string{"group", "ttl", "cluster-role"} {
+					if cmd.Flags().Changed(name) {
+						return errors.Errorf("--%s requires --user", name)
+					}
+				}
+			}
 			return runE(logger, flags)
 		},
 	}
@@ -66,6 +88,31 @@ func NewCommand(logger log.Logger, streams cmd.IOStreams) *cobra.Command {
 		false,
 		"use internal address instead of external",
 	)
+	cmd.Flags().StringVar(
+		&flags.User,
+		"user",
+		"",
+		"export a user signed by the cluster CA instead of the admin",
+	)
+	cmd.Flags().StringSliceVar(
+		&flags.Groups,
+		"group",
+		nil,
+		"a group of the user, may be repeated",
+	)
+	cmd.Flags().DurationVar(
+		&flags.TTL,
+		"ttl",
+		24*time.Hour,
+		"how long the user's certificate is valid for",
+	)
+	cmd.Flags().StringVar(
+		&flags.ClusterRole,
+		"cluster-role",
+		"",
+		"bind the user to this ClusterRole, E.G. view or edit",
+	)
+	completion.RegisterFlag(cmd, "name", completion.ClusterNames)
 	return cmd
 }
 
@@ -73,12 +120,38 @@ func runE(logger log.Logger, flags *flagpole) error {
 	provider := cluster.NewProvider(
 		cluster.ProviderWithLogger(logger),
 		runtime.GetDefault(logger),
+		runtime.GetKubeConfigStrategy(logger),
 	)
+	if flags.User != "" {
+		if err := provider.ExportKubeConfigForUser(flags.Name, flags.Kubeconfig, flags.Internal, cluster.KubeConfigUser{
+			Name:        flags.User,
+			Groups:      flags.Groups,
+			TTL:         flags.TTL,
+			ClusterRole: flags.ClusterRole,
+		}); err != nil {
+			return err
+		}
+		logger.V(0).Infof(`Set kubectl context to "%s"`, cluster.UserContext(flags.Name, flags.User))
+		return logIsolatedPath(logger, provider, flags)
+	}
 	if err := provider.ExportKubeConfig(flags.Name, flags.Kubeconfig, flags.Internal); err != nil {
 		return err
 	}
 	// TODO: get kind-name from a method? OTOH we probably want to keep this
 	// naming scheme stable anyhow...
 	logger.V(0).Infof(`Set kubectl context to "kind-%s"`, flags.Name)
+	return logIsolatedPath(logger, provider, flags)
+}
+
+// logIsolatedPath logs the cluster's own kubeconfig file if it was written to
+func logIsolatedPath(logger log.Logger, provider *cluster.Provider, flags *flagpole) error {
+	if flags.Kubeconfig != "" {
+		return nil
+	}
+	path, err := provider.KubeConfigPath(flags.Name, "")
+	if err != nil || path == "" {
+		return err
+	}
+	logger.V(0).Infof("in %s, use `kind env` to set KUBECONFIG", path)
 	return nil
 }
@@ -28,6 +28,7 @@ import (
 	"sigs.k8s.io/kind/pkg/log"
 
 	"sigs.k8s.io/kind/pkg/internal/cli"
+	"sigs.k8s.io/kind/pkg/internal/completion"
 	"sigs.k8s.io/kind/pkg/internal/runtime"
 )
 
@@ -56,6 +57,7 @@ func NewCommand(logger log.Logger, streams cmd.IOStreams) *cobra.Command {
 		cluster.DefaultName,
 		"the cluster context name",
 	)
+	completion.RegisterFlag(cmd, "name", completion.ClusterNames)
 	return cmd
 }
 
@@ -0,0 +1,130 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package expose implements the `expose` command
+package expose
+
+import (
+	"net"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/kind/pkg/apis/config/v1alpha4"
+	"sigs.k8s.io/kind/pkg/cluster"
+	"sigs.k8s.io/kind/pkg/cmd"
+	"sigs.k8s.io/kind/pkg/log"
+
+	"sigs.k8s.io/kind/pkg/internal/cli"
+	"sigs.k8s.io/kind/pkg/internal/completion"
+	"sigs.k8s.io/kind/pkg/internal/runtime"
+)
+
+type flagpole struct {
+	Name          string
+	Node          string
+	HostPort      int32
+	ContainerPort int32
+	ListenAddress string
+	Protocol      string
+}
+
+// NewCommand returns a new cobra.Command for exposing a node port on the host
+func NewCommand(logger log.Logger, streams cmd.IOStreams) *cobra.Command {
+	flags := &flagpole{}
+	cmd := &cobra.Command{
+		Args:  cobra.NoArgs,
+		Use:   "expose",
+		Short: "Publishes a host port on a running cluster",
+		Long: `Publishes a host port on a running cluster, forwarding it to a port of a node.
+
+Unlike extraPortMappings in the cluster config, this does not require
+recreating the cluster. Each port is forwarded by a small container on the
+cluster's network, which is deleted along with the cluster.
+
+  kind expose --node kind-worker --host-port 8080 --container-port 80
+  kind expose --host-port 5353 --container-port 53 --protocol UDP
+`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cli.OverrideDefaultName(cmd.Flags())
+			return runE(logger, streams, flags)
+		},
+	}
+	cmd.Flags().StringVarP(
+		&flags.Name,
+		"name",
+		"n",
+		cluster.DefaultName,
+		"the cluster context name",
+	)
+	cmd.Flags().StringVar(
+		&flags.Node,
+		"node",
+		"",
+		"the node to forward the port to (default the control plane node)",
+	)
+	cmd.Flags().Int32Var(
+		&flags.HostPort,
+		"host-port",
+		0,
+		"the port to publish on the host (default a random free port)",
+	)
+	cmd.Flags().Int32Var(
+		&flags.ContainerPort,
+		"container-port",
+		0,
+		"the port on the node to forward to",
+	)
+	cmd.Flags().StringVar(
+		&flags.ListenAddress,
+		"listen-address",
+		"",
+		"the host address to publish the port on (default all addresses)",
+	)
+	cmd.Flags().StringVar(
+		&flags.Protocol,
+		"protocol",
+		string(v1alpha4.PortMappingProtocolTCP),
+		"the protocol of the port, one of: TCP, UDP",
+	)
+	_ = cmd.MarkFlagRequired("container-port")
+	completion.RegisterFlag(cmd, "name", completion.ClusterNames)
+	completion.RegisterFlag(cmd, "node", completion.NodeNames)
+	return cmd
+}
+
+func runE(logger log.Logger, streams cmd.IOStreams, flags *flagpole) error {
+	provider := cluster.NewProvider(
+		cluster.ProviderWithLogger(logger),
+		runtime.GetDefault(logger),
+	)
+	status := cli.StatusForLogger(logger)
+	status.Start("Exposing port 🔌")
+	ports, err := provider.ExposePort(flags.Name, flags.Node, v1alpha4.PortMapping{
+		ContainerPort: flags.ContainerPort,
+		HostPort:      flags.HostPort,
+		ListenAddress: flags.ListenAddress,
+		Protocol:      v1alpha4.PortMappingProtocol(flags.Protocol),
+	})
+	status.End(err == nil)
+	if err != nil {
+		return err
+	}
+	for _, port := range ports {
+		logger.V(0).Infof("Forwarding %s to %s:%d/%s via %s", net.JoinHostPort(port.HostIP, strconv.Itoa(int(port.HostPort))), port.Node, port.ContainerPort, port.Protocol, port.Forwarder)
+	}
+	return nil
+}
@@ -18,47 +18,178 @@ limitations under the License.
 package clusters
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
 
 	"github.com/spf13/cobra"
 
 	"sigs.k8s.io/kind/pkg/cluster"
 	"sigs.k8s.io/kind/pkg/cmd"
+	"sigs.k8s.io/kind/pkg/errors"
 	"sigs.k8s.io/kind/pkg/log"
 
+	"sigs.k8s.io/kind/pkg/internal/cli"
 	"sigs.k8s.io/kind/pkg/internal/runtime"
 )
 
+type flagpole struct {
+	Output   string
+	Selector string
+}
+
 // NewCommand returns a new cobra.Command for getting the list of clusters
 func NewCommand(logger log.Logger, streams cmd.IOStreams) *cobra.Command {
+	flags := &flagpole{}
 	cmd := &cobra.Command{
 		Args: cobra.NoArgs,
 		// TODO(bentheelder): more detailed usage
 		Use:   "clusters",
 		Short: "Lists existing kind clusters by their name",
-		Long:  "Lists existing kind clusters by their name",
+		Long:  "Lists existing kind clusters by their name, or with details using --output",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runE(logger, streams)
+			return runE(logger, streams, flags)
 		},
 	}
+	cmd.Flags().StringVarP(
+		&flags.Output,
+		"output",
+		"o",
+		"",
+		"output format, one of: wide, json (default names only)",
+	)
+	cmd.Flags().StringVarP(
+		&flags.Selector,
+		"selector",
+		"l",
+		"",
+		"cluster label selector to filter on, supports '=', '==', '!=', 'key' and '!key' (e.g. -l owner=ci,!temporary)",
+	)
 	return cmd
 }
 
-func runE(logger log.Logger, streams cmd.IOStreams) error {
+func runE(logger log.Logger, streams cmd.IOStreams, flags *flagpole) error {
+	switch flags.Output {
+	case "", "wide", "json":
+	default:
+		return errors.Errorf("unknown output format %q, expected one of: wide, json", flags.Output)
+	}
+	selector, err := cli.ParseSelector(flags.Selector)
+	if err != nil {
+		return err
+	}
+
 	provider := cluster.NewProvider(
 		cluster.ProviderWithLogger(logger),
 		runtime.GetDefault(logger),
 	)
-	clusters, err := provider.List()
+
+	// names only, without a selector we don't need any details
+	if flags.Output == "" && selector.Empty() {
+		clusters, err := provider.List()
+		if err != nil {
+			return err
+		}
+		if len(clusters) == 0 {
+			logger.V(0).Info("No kind clusters found.")
+			return nil
+		}
+		for _, cluster := range clusters {
+			fmt.Fprintln(streams.Out, cluster)
+		}
+		return nil
+	}
+
+	infos, err := provider.ListClustersWithInfo()
 	if err != nil {
 		return err
 	}
-	if len(clusters) == 0 {
-		logger.V(0).Info("No kind clusters found.")
+	selected := []cluster.ClusterInfo{}
+	for _, info := range infos {
+		if selector.Matches(info.Labels) {
+			selected = append(selected, info)
+		}
+	}
+
+	switch flags.Output {
+	case "json":
+		// always output a valid list, even if empty
+		out, err := json.MarshalIndent(selected, "", "  ")
+		if err != nil {
+			return errors.Wrap(err, "failed to encode clusters")
+		}
+		fmt.Fprintln(streams.Out, string(out))
+		return nil
+	case "wide":
+		if len(selected) == 0 {
+			logger.V(0).Info("No kind clusters found.")
+			return nil
+		}
+		return printWide(streams.Out, selected, time.Now())
+	default:
+		if len(selected) == 0 {
+			logger.V(0).Info("No kind clusters found.")
+			return nil
+		}
+		for _, info := range selected {
+			fmt.Fprintln(streams.Out, info.Name)
+		}
 		return nil
 	}
-	for _, cluster := range clusters {
-		fmt.Fprintln(streams.Out, cluster)
+}
+
+// printWide prints a table of the clusters, ages are relative to now
+func printWide(out io.Writer, infos []cluster.ClusterInfo, now time.Time) error {
+	w := tabwriter.NewWriter(out, 0, 8, 3, ' ', 0)
+	fmt.Fprintln(w, "NAME\tVERSION\tNODES\tENDPOINT\tCREATED\tLABELS")
+	for _, info := range infos {
+		fmt.Fprintf(w, "%s\t%s\t%d\t%s\t%s\t%s\n",
+			info.Name,
+			orNone(info.KubernetesVersion),
+			info.Nodes,
+			orNone(info.APIServerEndpoint),
+			age(info.CreationTimestamp, now),
+			orNone(formatLabels(info.Labels)),
+		)
+	}
+	return w.Flush()
+}
+
+// formatLabels formats labels as sorted, comma separated key=value pairs
+func formatLabels(labels map[string]string) string {
+	pairs := make([]string, 0, len(labels))
+	for key, value := range labels {
+		pairs = append(pairs, key+"="+value)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+// age formats the time since created like kubectl, E.G. 5m or 3d
+func age(created, now time.Time) string {
+	if created.IsZero() {
+		return "<unknown>"
+	}
+	d := now.Sub(created)
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 48*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	}
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "<none>"
 	}
-	return nil
+	return s
 }
@@ -24,8 +24,11 @@ import (
 
 	"sigs.k8s.io/kind/pkg/cmd"
 	"sigs.k8s.io/kind/pkg/cmd/kind/get/clusters"
+	"sigs.k8s.io/kind/pkg/cmd/kind/get/images"
 	"sigs.k8s.io/kind/pkg/cmd/kind/get/kubeconfig"
 	"sigs.k8s.io/kind/pkg/cmd/kind/get/nodes"
+	"sigs.k8s.io/kind/pkg/cmd/kind/get/ports"
+	"sigs.k8s.io/kind/pkg/cmd/kind/get/settings"
 	"sigs.k8s.io/kind/pkg/log"
 )
 
@@ -34,8 +37,8 @@ func NewCommand(logger log.Logger, streams cmd.IOStreams) *cobra.Command {
 	cmd := &cobra.Command{
 		// TODO(bentheelder): more detailed usage
 		Use:   "get",
-		Short: "Gets one of [clusters, nodes, kubeconfig]",
-		Long:  "Gets one of [clusters, nodes, kubeconfig]",
+		Short: "Gets one of [clusters, nodes, images, kubeconfig, ports]",
+		Long:  "Gets one of [clusters, nodes, images, kubeconfig, ports]",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			err := cmd.Help()
 			if err != nil {
@@ -47,6 +50,9 @@ func NewCommand(logger log.Logger, streams cmd.IOStreams) *cobra.Command {
 	// add subcommands
 	cmd.AddCommand(clusters.NewCommand(logger, streams))
 	cmd.AddCommand(nodes.NewCommand(logger, streams))
+	cmd.AddCommand(images.NewCommand(logger, streams))
 	cmd.AddCommand(kubeconfig.NewCommand(logger, streams))
+	cmd.AddCommand(ports.NewCommand(logger, streams))
+	cmd.AddCommand(settings.NewCommand(logger, streams))
 	return cmd
 }
@@ -0,0 +1,263 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package images implements the `images` command
+package images
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
+
+	"sigs.k8s.io/kind/pkg/cluster"
+	"sigs.k8s.io/kind/pkg/cluster/nodeutils"
+	"sigs.k8s.io/kind/pkg/cmd"
+	"sigs.k8s.io/kind/pkg/errors"
+	"sigs.k8s.io/kind/pkg/log"
+
+	"sigs.k8s.io/kind/pkg/internal/cli"
+	"sigs.k8s.io/kind/pkg/internal/completion"
+	"sigs.k8s.io/kind/pkg/internal/imageload"
+	"sigs.k8s.io/kind/pkg/internal/runtime"
+)
+
+type flagpole struct {
+	Name   string
+	Nodes  []string
+	Output string
+}
+
+// image is an image reference and the nodes it is present on
+type image struct {
+	// Image is the image tag, or repo digest if the image is untagged
+	Image  string `json:"image"`
+	ID     string `json:"id"`
+	Size   uint64 `json:"size"`
+	Pinned bool   `json:"pinned"`
+	// Nodes are the nodes the image is present on
+	Nodes []string `json:"nodes"`
+	// Partial is true if the image is only present on some of the nodes
+	Partial bool `json:"partial"`
+}
+
+// NewCommand returns a new cobra.Command for listing the images in nodes
+func NewCommand(logger log.Logger, streams cmd.IOStreams) *cobra.Command {
+	flags := &flagpole{}
+	cmd := &cobra.Command{
+		Args:  cobra.NoArgs,
+		Use:   "images",
+		Short: "Lists the images in the cluster's nodes",
+		Long: `Lists the images in the cluster's nodes, including images preloaded into
+the node image and images loaded with kind load.
+
+Images only present on some of the nodes list the nodes they are present on.
+Pinned images, such as the preloaded images, are never garbage collected by
+the kubelet.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cli.OverrideDefaultName(cmd.Flags())
+			return runE(logger, streams, flags)
+		},
+	}
+	cmd.Flags().StringVarP(
+		&flags.Name,
+		"name",
+		"n",
+		cluster.DefaultName,
+		"the cluster context name",
+	)
+	cmd.Flags().StringSliceVar(
+		&flags.Nodes,
+		"node",
+		nil,
+		"the nodes to list images of, default all nodes",
+	)
+	cmd.Flags().StringVarP(
+		&flags.Output,
+		"output",
+		"o",
+		"",
+		"output format, one of: json, yaml (default table)",
+	)
+	completion.RegisterFlag(cmd, "name", completion.ClusterNames)
+	completion.RegisterFlag(cmd, "node", completion.NodeNameList)
+	return cmd
+}
+
+func runE(logger log.Logger, streams cmd.IOStreams, flags *flagpole) error {
+	switch flags.Output {
+	case "", "json", "yaml":
+	default:
+		return errors.Errorf("unknown output format %q, expected one of: json, yaml", flags.Output)
+	}
+
+	provider := cluster.NewProvider(
+		cluster.ProviderWithLogger(logger),
+		runtime.GetDefault(logger),
+	)
+	nodeList, err := provider.ListInternalNodes(flags.Name)
+	if err != nil {
+		return err
+	}
+	if len(nodeList) == 0 {
+		return fmt.Errorf("no nodes found for cluster %q", flags.Name)
+	}
+	selected, err := imageload.SelectNodes(nodeList, flags.Nodes)
+	if err != nil {
+		return err
+	}
+
+	// list the images of all nodes concurrently
+	var mu sync.Mutex
+	nodeNames := []string{}
+	imagesByNode := map[string][]nodeutils.Image{}
+	fns := []func() error{}
+	for _, node := range selected {
+		node := node // capture loop variable
+		nodeNames = append(nodeNames, node.String())
+		fns = append(fns, func() error {
+			images, err := nodeutils.ListImages(node)
+			if err != nil {
+				return errors.Wrapf(err, "failed to list images of node %s", node)
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			imagesByNode[node.String()] = images
+			return nil
+		})
+	}
+	if err := errors.AggregateConcurrent(fns); err != nil {
+		return err
+	}
+	images := aggregate(nodeNames, imagesByNode)
+
+	switch flags.Output {
+	case "json":
+		// always output a valid list, even if empty
+		encoded, err := json.MarshalIndent(images, "", "  ")
+		if err != nil {
+			return errors.Wrap(err, "failed to encode images")
+		}
+		fmt.Fprintln(streams.Out, string(encoded))
+		return nil
+	case "yaml":
+		encoded, err := yaml.Marshal(images)
+		if err != nil {
+			return errors.Wrap(err, "failed to encode images")
+		}
+		_, err = streams.Out.Write(encoded)
+		return err
+	}
+	return printTable(streams.Out, images)
+}
+
+// aggregate merges the images of each of nodeNames into one list, sorted by
+// image then ID
+func aggregate(nodeNames []string, imagesByNode map[string][]nodeutils.Image) []image {
+	byKey := map[string]*image{}
+	keys := []string{}
+	for _, nodeName := range nodeNames {
+		for _, img := range imagesByNode[nodeName] {
+			for _, ref := range references(img) {
+				key := ref + "\x00" + img.ID
+				agg, ok := byKey[key]
+				if !ok {
+					agg = &image{
+						Image:  ref,
+						ID:     img.ID,
+						Size:   img.Size,
+						Pinned: img.Pinned,
+						Nodes:  []string{},
+					}
+					byKey[key] = agg
+					keys = append(keys, key)
+				}
+				agg.Nodes = append(agg.Nodes, nodeName)
+			}
+		}
+	}
+	sort.Strings(keys)
+	images := make([]image, 0, len(keys))
+	for _, key := range keys {
+		agg := byKey[key]
+		agg.Partial = len(agg.Nodes) < len(nodeNames)
+		images = append(images, *agg)
+	}
+	return images
+}
+
+// references returns the tags of img, or a repo digest if it is untagged
+func references(img nodeutils.Image) []string {
+	if len(img.RepoTags) > 0 {
+		return img.RepoTags
+	}
+	if len(img.RepoDigests) > 0 {
+		return img.RepoDigests[:1]
+	}
+	return []string{"<none>"}
+}
+
+func printTable(out io.Writer, images []image) error {
+	w := tabwriter.NewWriter(out, 0, 8, 3, ' ', 0)
+	fmt.Fprintln(w, "IMAGE\tID\tSIZE\tPINNED\tNODES")
+	for _, img := range images {
+		nodes := "all"
+		if img.Partial {
+			nodes = strings.Join(img.Nodes, ",")
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%t\t%s\n",
+			img.Image,
+			shortID(img.ID),
+			formatSize(img.Size),
+			img.Pinned,
+			nodes,
+		)
+	}
+	return w.Flush()
+}
+
+// shortID returns the truncated image ID, as in `crictl images`
+func shortID(id string) string {
+	id = strings.TrimPrefix(id, "sha256:")
+	if len(id) > 13 {
+		return id[:13]
+	}
+	return id
+}
+
+// formatSize formats size in decimal units, E.G. 27.73MB
+func formatSize(size uint64) string {
+	units := []string{"B", "kB", "MB", "GB", "TB"}
+	value := float64(size)
+	i := 0
+	for value >= 1000 && i < len(units)-1 {
+		value /= 1000
+		i++
+	}
+	return strconv.FormatFloat(round(value), 'f', -1, 64) + units[i]
+}
+
+// round rounds to two decimal places
+func round(value float64) float64 {
+	return float64(int64(value*100+0.5)) / 100
+}
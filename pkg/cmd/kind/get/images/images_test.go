@@ -0,0 +1,97 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package images
+
+import (
+	"testing"
+
+	"sigs.k8s.io/kind/pkg/cluster/nodeutils"
+	"sigs.k8s.io/kind/pkg/internal/assert"
+)
+
+func TestAggregate(t *testing.T) {
+	t.Parallel()
+	kindnetd := nodeutils.Image{
+		ID:       "sha256:9f1c",
+		RepoTags: []string{"docker.io/kindest/kindnetd:v1"},
+		Size:     27731571,
+		Pinned:   true,
+	}
+	app := nodeutils.Image{
+		ID:       "sha256:a416",
+		RepoTags: []string{"docker.io/library/app:1.0", "docker.io/library/app:latest"},
+		Size:     2156416,
+	}
+	untagged := nodeutils.Image{
+		ID:          "sha256:b2c3",
+		RepoDigests: []string{"docker.io/library/busybox@sha256:3fbc"},
+		Size:        100,
+	}
+	images := aggregate(
+		[]string{"kind-control-plane", "kind-worker"},
+		map[string][]nodeutils.Image{
+			"kind-control-plane": {kindnetd, untagged},
+			"kind-worker":        {app, kindnetd},
+		},
+	)
+	expected := []image{
+		{
+			Image:   "docker.io/kindest/kindnetd:v1",
+			ID:      "sha256:9f1c",
+			Size:    27731571,
+			Pinned:  true,
+			Nodes:   []string{"kind-control-plane", "kind-worker"},
+			Partial: false,
+		},
+		{
+			Image:   "docker.io/library/app:1.0",
+			ID:      "sha256:a416",
+			Size:    2156416,
+			Nodes:   []string{"kind-worker"},
+			Partial: true,
+		},
+		{
+			Image:   "docker.io/library/app:latest",
+			ID:      "sha256:a416",
+			Size:    2156416,
+			Nodes:   []string{"kind-worker"},
+			Partial: true,
+		},
+		{
+			Image:   "docker.io/library/busybox@sha256:3fbc",
+			ID:      "sha256:b2c3",
+			Size:    100,
+			Nodes:   []string{"kind-control-plane"},
+			Partial: true,
+		},
+	}
+	assert.DeepEqual(t, expected, images)
+}
+
+func TestFormatSize(t *testing.T) {
+	t.Parallel()
+	for size, expected := range map[uint64]string{
+		0:          "0B",
+		999:        "999B",
+		1000:       "1kB",
+		27731571:   "27.73MB",
+		2156416:    "2.16MB",
+		1500000000: "1.5GB",
+	} {
+		assert.StringEqual(t, expected, formatSize(size))
+	}
+}
@@ -27,6 +27,7 @@ import (
 	"sigs.k8s.io/kind/pkg/log"
 
 	"sigs.k8s.io/kind/pkg/internal/cli"
+	"sigs.k8s.io/kind/pkg/internal/completion"
 	"sigs.k8s.io/kind/pkg/internal/runtime"
 )
 
@@ -61,6 +62,7 @@ func NewCommand(logger log.Logger, streams cmd.IOStreams) *cobra.Command {
 		false,
 		"use internal address instead of external",
 	)
+	completion.RegisterFlag(cmd, "name", completion.ClusterNames)
 	return cmd
 }
 
@@ -18,22 +18,30 @@ limitations under the License.
 package nodes
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
 
 	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
 
 	"sigs.k8s.io/kind/pkg/cluster"
 	"sigs.k8s.io/kind/pkg/cluster/nodes"
 	"sigs.k8s.io/kind/pkg/cmd"
+	"sigs.k8s.io/kind/pkg/errors"
 	"sigs.k8s.io/kind/pkg/log"
 
 	"sigs.k8s.io/kind/pkg/internal/cli"
+	"sigs.k8s.io/kind/pkg/internal/completion"
 	"sigs.k8s.io/kind/pkg/internal/runtime"
 )
 
 type flagpole struct {
 	Name        string
 	AllClusters bool
+	Output      string
 }
 
 // NewCommand returns a new cobra.Command for getting the list of nodes for a given cluster
@@ -43,7 +51,7 @@ func NewCommand(logger log.Logger, streams cmd.IOStreams) *cobra.Command {
 		Args:  cobra.NoArgs,
 		Use:   "nodes",
 		Short: "Lists existing kind nodes by their name",
-		Long:  "Lists existing kind nodes by their name",
+		Long:  "Lists existing kind nodes by their name, or with details using --output",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			cli.OverrideDefaultName(cmd.Flags())
 			return runE(logger, streams, flags)
@@ -63,43 +71,61 @@ func NewCommand(logger log.Logger, streams cmd.IOStreams) *cobra.Command {
 		false,
 		"If present, list all the available nodes across all cluster contexts. Current context is ignored even if specified with --name.",
 	)
+	cmd.Flags().StringVarP(
+		&flags.Output,
+		"output",
+		"o",
+		"",
+		"output format, one of: wide, json, yaml (default names only)",
+	)
+	completion.RegisterFlag(cmd, "name", completion.ClusterNames)
 	return cmd
 }
 
 func runE(logger log.Logger, streams cmd.IOStreams, flags *flagpole) error {
+	switch flags.Output {
+	case "", "wide", "json", "yaml":
+	default:
+		return errors.Errorf("unknown output format %q, expected one of: wide, json, yaml", flags.Output)
+	}
+
 	// List nodes by cluster context name
 	provider := cluster.NewProvider(
 		cluster.ProviderWithLogger(logger),
 		runtime.GetDefault(logger),
 	)
 
-	var nodes []nodes.Node
-	var err error
+	clusters := []string{flags.Name}
 	if flags.AllClusters {
-		clusters, err := provider.List()
-		if err != nil {
+		var err error
+		if clusters, err = provider.List(); err != nil {
 			return err
 		}
+	}
+
+	if flags.Output != "" {
+		infos := []cluster.NodeInfo{}
 		for _, clusterName := range clusters {
-			clusterNodes, err := provider.ListNodes(clusterName)
+			clusterInfos, err := provider.ListNodesWithInfo(clusterName)
 			if err != nil {
 				return err
 			}
-			nodes = append(nodes, clusterNodes...)
+			infos = append(infos, clusterInfos...)
 		}
-		if len(nodes) == 0 {
-			logger.V(0).Infof("No kind nodes for any cluster.")
-			return nil
-		}
-	} else {
-		nodes, err = provider.ListNodes(flags.Name)
+		return printInfos(logger, streams.Out, flags, infos)
+	}
+
+	var nodes []nodes.Node
+	for _, clusterName := range clusters {
+		clusterNodes, err := provider.ListNodes(clusterName)
 		if err != nil {
 			return err
 		}
-		if len(nodes) == 0 {
-			logger.V(0).Infof("No kind nodes found for cluster %q.", flags.Name)
-			return nil
-		}
+		nodes = append(nodes, clusterNodes...)
+	}
+	if len(nodes) == 0 {
+		logNoNodes(logger, flags)
+		return nil
 	}
 
 	for _, node := range nodes {
@@ -107,3 +133,85 @@ func runE(logger log.Logger, streams cmd.IOStreams, flags *flagpole) error {
 	}
 	return nil
 }
+
+func logNoNodes(logger log.Logger, flags *flagpole) {
+	if flags.AllClusters {
+		logger.V(0).Infof("No kind nodes for any cluster.")
+	} else {
+		logger.V(0).Infof("No kind nodes found for cluster %q.", flags.Name)
+	}
+}
+
+func printInfos(logger log.Logger, out io.Writer, flags *flagpole, infos []cluster.NodeInfo) error {
+	switch flags.Output {
+	case "json":
+		// always output a valid list, even if empty
+		encoded, err := json.MarshalIndent(infos, "", "  ")
+		if err != nil {
+			return errors.Wrap(err, "failed to encode nodes")
+		}
+		fmt.Fprintln(out, string(encoded))
+		return nil
+	case "yaml":
+		encoded, err := yaml.Marshal(infos)
+		if err != nil {
+			return errors.Wrap(err, "failed to encode nodes")
+		}
+		_, err = out.Write(encoded)
+		return err
+	default:
+		if len(infos) == 0 {
+			logNoNodes(logger, flags)
+			return nil
+		}
+		return printWide(out, infos, flags.AllClusters)
+	}
+}
+
+// printWide prints a table of the nodes, including the cluster column if
+// nodes of all clusters are listed
+func printWide(out io.Writer, infos []cluster.NodeInfo, withCluster bool) error {
+	w := tabwriter.NewWriter(out, 0, 8, 3, ' ', 0)
+	header := "NAME\tROLE\tSTATE\tREADY\tVERSION\tIPV4\tIPV6\tIMAGE\tPORTS"
+	if withCluster {
+		header = "CLUSTER\t" + header
+	}
+	fmt.Fprintln(w, header)
+	for _, info := range infos {
+		if withCluster {
+			fmt.Fprintf(w, "%s\t", info.Cluster)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			info.Name,
+			info.Role,
+			orNone(info.State),
+			orNone(info.Ready),
+			orNone(info.KubernetesVersion),
+			orNone(info.IPv4),
+			orNone(info.IPv6),
+			orNone(info.Image),
+			orNone(formatPorts(info.Ports)),
+		)
+	}
+	return w.Flush()
+}
+
+// formatPorts formats ports like docker ps, E.G. 127.0.0.1:34567->6443/tcp
+func formatPorts(ports []cluster.NodePort) string {
+	formatted := make([]string, 0, len(ports))
+	for _, port := range ports {
+		hostIP := port.HostIP
+		if strings.Contains(hostIP, ":") {
+			hostIP = "[" + hostIP + "]"
+		}
+		formatted = append(formatted, fmt.Sprintf("%s:%d->%d/%s", hostIP, port.HostPort, port.ContainerPort, port.Protocol))
+	}
+	return strings.Join(formatted, ",")
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "<none>"
+	}
+	return s
+}
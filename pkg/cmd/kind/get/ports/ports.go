@@ -0,0 +1,124 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package ports implements the `ports` command
+package ports
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/kind/pkg/cluster"
+	"sigs.k8s.io/kind/pkg/cmd"
+	"sigs.k8s.io/kind/pkg/errors"
+	"sigs.k8s.io/kind/pkg/log"
+
+	"sigs.k8s.io/kind/pkg/internal/cli"
+	"sigs.k8s.io/kind/pkg/internal/completion"
+	"sigs.k8s.io/kind/pkg/internal/runtime"
+)
+
+type flagpole struct {
+	Name   string
+	Output string
+}
+
+// NewCommand returns a new cobra.Command for listing the host ports of a cluster
+func NewCommand(logger log.Logger, streams cmd.IOStreams) *cobra.Command {
+	flags := &flagpole{}
+	cmd := &cobra.Command{
+		Args:  cobra.NoArgs,
+		Use:   "ports",
+		Short: "Lists the host ports published by a cluster",
+		Long: `Lists the host ports published by a cluster, including the API server port,
+extraPortMappings and ports added with "kind expose"`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cli.OverrideDefaultName(cmd.Flags())
+			return runE(logger, streams, flags)
+		},
+	}
+	cmd.Flags().StringVarP(
+		&flags.Name,
+		"name",
+		"n",
+		cluster.DefaultName,
+		"the cluster context name",
+	)
+	cmd.Flags().StringVarP(
+		&flags.Output,
+		"output",
+		"o",
+		"",
+		"output format, one of: json (default table)",
+	)
+	completion.RegisterFlag(cmd, "name", completion.ClusterNames)
+	return cmd
+}
+
+func runE(logger log.Logger, streams cmd.IOStreams, flags *flagpole) error {
+	switch flags.Output {
+	case "", "json":
+	default:
+		return errors.Errorf("unknown output format %q, expected one of: json", flags.Output)
+	}
+	provider := cluster.NewProvider(
+		cluster.ProviderWithLogger(logger),
+		runtime.GetDefault(logger),
+	)
+	ports, err := provider.ListPorts(flags.Name)
+	if err != nil {
+		return err
+	}
+	if flags.Output == "json" {
+		// always output a valid list, even if empty
+		encoded, err := json.MarshalIndent(ports, "", "  ")
+		if err != nil {
+			return errors.Wrap(err, "failed to encode ports")
+		}
+		fmt.Fprintln(streams.Out, string(encoded))
+		return nil
+	}
+	if len(ports) == 0 {
+		logger.V(0).Infof("No host ports found for cluster %q.", flags.Name)
+		return nil
+	}
+	return printTable(streams.Out, ports)
+}
+
+func printTable(out io.Writer, ports []cluster.ClusterPort) error {
+	w := tabwriter.NewWriter(out, 0, 8, 3, ' ', 0)
+	fmt.Fprintln(w, "NODE\tPORT\tHOST\tFORWARDER")
+	for _, port := range ports {
+		forwarder := port.Forwarder
+		if forwarder == "" {
+			forwarder = "<none>"
+		}
+		fmt.Fprintf(w, "%s\t%d/%s\t%s\t%s\n",
+			port.Node,
+			port.ContainerPort,
+			port.Protocol,
+			net.JoinHostPort(port.HostIP, strconv.Itoa(int(port.HostPort))),
+			forwarder,
+		)
+	}
+	return w.Flush()
+}
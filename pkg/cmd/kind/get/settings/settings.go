@@ -0,0 +1,169 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package settings implements the `settings` command
+package settings
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
+
+	"sigs.k8s.io/kind/pkg/apis/config/defaults"
+	"sigs.k8s.io/kind/pkg/cmd"
+	"sigs.k8s.io/kind/pkg/errors"
+	"sigs.k8s.io/kind/pkg/log"
+
+	"sigs.k8s.io/kind/pkg/internal/runtime"
+	"sigs.k8s.io/kind/pkg/internal/settings"
+)
+
+type flagpole struct {
+	Output string
+}
+
+// Sources of resolved settings
+const (
+	sourceEnv      = "env"
+	sourceSettings = "settings"
+	sourceDefault  = "default"
+)
+
+// resolved is the resolved value of a setting
+type resolved struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+	// Source is env, settings or default
+	Source string `json:"source"`
+	// Env is the environment variable overriding the setting, if any
+	Env string `json:"env,omitempty"`
+}
+
+// NewCommand returns a new cobra.Command for showing the kind settings
+func NewCommand(logger log.Logger, streams cmd.IOStreams) *cobra.Command {
+	flags := &flagpole{}
+	cmd := &cobra.Command{
+		Args:  cobra.NoArgs,
+		Use:   "settings",
+		Short: "Shows the resolved kind settings",
+		Long: `Shows the resolved kind settings and where each value comes from.
+
+Settings are read from $XDG_CONFIG_HOME/kind/config.yaml, E.G.
+
+  provider: podman
+  nodeImage: kindest/node:v1.36.1
+  wait: 5m
+  config: /home/user/kind-config.yaml
+  verbosity: 1
+  kubeconfigStrategy: merged
+
+Flags take precedence over environment variables, which take precedence
+over the settings file, which takes precedence over built-in defaults.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runE(logger, streams, flags)
+		},
+	}
+	cmd.Flags().StringVarP(
+		&flags.Output,
+		"output",
+		"o",
+		"",
+		"output format, one of: json, yaml (default table)",
+	)
+	return cmd
+}
+
+func runE(logger log.Logger, streams cmd.IOStreams, flags *flagpole) error {
+	switch flags.Output {
+	case "", "json", "yaml":
+	default:
+		return errors.Errorf("unknown output format %q, expected one of: json, yaml", flags.Output)
+	}
+	path, err := settings.Path()
+	if err != nil {
+		return err
+	}
+	// invalid settings are ignored, and warned about by the kind command
+	values := resolve(settings.Get(), os.Getenv)
+	switch flags.Output {
+	case "json":
+		encoded, err := json.MarshalIndent(values, "", "  ")
+		if err != nil {
+			return errors.Wrap(err, "failed to encode settings")
+		}
+		fmt.Fprintln(streams.Out, string(encoded))
+		return nil
+	case "yaml":
+		encoded, err := yaml.Marshal(values)
+		if err != nil {
+			return errors.Wrap(err, "failed to encode settings")
+		}
+		fmt.Fprint(streams.Out, string(encoded))
+		return nil
+	}
+	logger.V(0).Infof("Settings file: %s", path)
+	return printTable(streams.Out, values)
+}
+
+// resolve returns the value and source of each setting
+func resolve(s *settings.Settings, getEnv func(string) string) []resolved {
+	verbosity := ""
+	if s.Verbosity != nil {
+		verbosity = strconv.Itoa(int(*s.Verbosity))
+	}
+	return []resolved{
+		resolveOne("provider", getEnv, runtime.ProviderEnv, s.Provider, "<detected>"),
+		resolveOne("providerBinary", getEnv, runtime.ProviderBinaryEnv, s.ProviderBinary, "<none>"),
+		resolveOne("providerDialect", getEnv, runtime.ProviderDialectEnv, s.ProviderDialect, "docker"),
+		resolveOne("nodeImage", getEnv, "", s.NodeImage, defaults.Image),
+		resolveOne("wait", getEnv, "", s.Wait, "0s"),
+		resolveOne("config", getEnv, "", s.Config, "<none>"),
+		resolveOne("verbosity", getEnv, "", verbosity, "0"),
+		resolveOne("kubeconfigStrategy", getEnv, runtime.KubeConfigStrategyEnv, s.KubeconfigStrategy, "isolated"),
+	}
+}
+
+func resolveOne(name string, getEnv func(string) string, env, setting, def string) resolved {
+	r := resolved{Name: name, Env: env}
+	switch {
+	case env != "" && getEnv(env) != "":
+		r.Value, r.Source = getEnv(env), sourceEnv
+	case setting != "":
+		r.Value, r.Source = setting, sourceSettings
+	default:
+		r.Value, r.Source = def, sourceDefault
+	}
+	return r
+}
+
+func printTable(out io.Writer, values []resolved) error {
+	w := tabwriter.NewWriter(out, 0, 8, 3, ' ', 0)
+	fmt.Fprintln(w, "SETTING\tVALUE\tSOURCE")
+	for _, v := range values {
+		source := v.Source
+		if source == sourceEnv {
+			source += " (" + v.Env + ")"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", v.Name, v.Value, source)
+	}
+	return w.Flush()
+}
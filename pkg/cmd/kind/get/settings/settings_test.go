@@ -0,0 +1,51 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package settings
+
+import (
+	"testing"
+
+	"sigs.k8s.io/kind/pkg/apis/config/defaults"
+	"sigs.k8s.io/kind/pkg/internal/assert"
+	"sigs.k8s.io/kind/pkg/internal/settings"
+)
+
+func TestResolve(t *testing.T) {
+	t.Parallel()
+	verbosity := int32(2)
+	s := &settings.Settings{
+		Provider:           "podman",
+		ProviderDialect:    "nerdctl",
+		Wait:               "5m",
+		Verbosity:          &verbosity,
+		KubeconfigStrategy: "merged",
+	}
+	env := map[string]string{
+		"KIND_EXPERIMENTAL_PROVIDER": "nerdctl",
+	}
+	expected := []resolved{
+		{Name: "provider", Value: "nerdctl", Source: sourceEnv, Env: "KIND_EXPERIMENTAL_PROVIDER"},
+		{Name: "providerBinary", Value: "<none>", Source: sourceDefault, Env: "KIND_EXPERIMENTAL_PROVIDER_BINARY"},
+		{Name: "providerDialect", Value: "nerdctl", Source: sourceSettings, Env: "KIND_EXPERIMENTAL_PROVIDER_DIALECT"},
+		{Name: "nodeImage", Value: defaults.Image, Source: sourceDefault},
+		{Name: "wait", Value: "5m", Source: sourceSettings},
+		{Name: "config", Value: "<none>", Source: sourceDefault},
+		{Name: "verbosity", Value: "2", Source: sourceSettings},
+		{Name: "kubeconfigStrategy", Value: "merged", Source: sourceSettings, Env: "KIND_KUBECONFIG_STRATEGY"},
+	}
+	assert.DeepEqual(t, expected, resolve(s, func(key string) string { return env[key] }))
+}
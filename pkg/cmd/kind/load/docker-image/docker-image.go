@@ -19,32 +19,27 @@ package load
 
 import (
 	"fmt"
-	"os"
-	"path/filepath"
-	"strings"
+	"io"
 
 	"github.com/spf13/cobra"
 
 	"sigs.k8s.io/kind/pkg/cluster"
 	"sigs.k8s.io/kind/pkg/cluster/nodes"
-	"sigs.k8s.io/kind/pkg/cluster/nodeutils"
 	"sigs.k8s.io/kind/pkg/cmd"
 	"sigs.k8s.io/kind/pkg/errors"
 	"sigs.k8s.io/kind/pkg/exec"
-	"sigs.k8s.io/kind/pkg/fs"
 	"sigs.k8s.io/kind/pkg/log"
 
 	"sigs.k8s.io/kind/pkg/internal/cli"
+	"sigs.k8s.io/kind/pkg/internal/completion"
+	"sigs.k8s.io/kind/pkg/internal/imageload"
 	"sigs.k8s.io/kind/pkg/internal/runtime"
 )
 
-type (
-	imageTagFetcher func(nodes.Node, string) (map[string]bool, error)
-)
-
 type flagpole struct {
-	Name  string
-	Nodes []string
+	Name     string
+	Nodes    []string
+	Platform string
 }
 
 // NewCommand returns a new cobra.Command for loading an image into a cluster
@@ -78,6 +73,14 @@ func NewCommand(logger log.Logger, streams cmd.IOStreams) *cobra.Command {
 		nil,
 		"comma separated list of nodes to load images into",
 	)
+	cmd.Flags().StringVar(
+		&flags.Platform,
+		"platform",
+		"",
+		"the platform to load, E.G. linux/arm64, defaults to the platform of the nodes",
+	)
+	completion.RegisterFlag(cmd, "name", completion.ClusterNames)
+	completion.RegisterFlag(cmd, "nodes", completion.NodeNameList)
 	return cmd
 }
 
@@ -107,61 +110,33 @@ func runE(logger log.Logger, flags *flagpole, args []string) error {
 		return fmt.Errorf("no nodes found for cluster %q", flags.Name)
 	}
 
-	// map cluster nodes by their name
-	nodesByName := map[string]nodes.Node{}
-	for _, node := range nodeList {
-		// TODO(bentheelder): this depends on the fact that ListByCluster()
-		// will have name for nameOrId.
-		nodesByName[node.String()] = node
-	}
-
 	// pick only the user selected nodes and ensure they exist
 	// the default is all nodes unless flags.Nodes is set
-	candidateNodes := nodeList
-	if len(flags.Nodes) > 0 {
-		candidateNodes = []nodes.Node{}
-		for _, name := range flags.Nodes {
-			node, ok := nodesByName[name]
-			if !ok {
-				return fmt.Errorf("unknown node: %q", name)
-			}
-			candidateNodes = append(candidateNodes, node)
+	candidateNodes, err := imageload.SelectNodes(nodeList, flags.Nodes)
+	if err != nil {
+		return err
+	}
+
+	// load only the requested platform, or the platform of the nodes
+	platform, err := imageload.NodePlatform(flags.Platform, candidateNodes[0])
+	if err != nil {
+		return err
+	}
+	for _, imageName := range imageNames {
+		if err := checkPlatform(imageName, platform); err != nil {
+			return err
 		}
 	}
 
 	// pick only the nodes that don't have the image
-	selectedNodes := map[string]nodes.Node{}
-	fns := []func() error{}
+	selectedNodes := []nodes.Node{}
+	seen := map[string]bool{}
 	for i, imageName := range imageNames {
-		imageID := imageIDs[i]
-		processed := false
-		for _, node := range candidateNodes {
-			exists, reTagRequired, sanitizedImageName := checkIfImageReTagRequired(node, imageID, imageName, nodeutils.ImageTags)
-			if exists && !reTagRequired {
-				continue
-			}
-
-			if reTagRequired {
-				// We will try to re-tag the image. If the re-tag fails, we will fall back to the default behavior of loading
-				// the images into the nodes again
-				logger.V(0).Infof("Image with ID: %s already present on the node %s but is missing the tag %s. re-tagging...", imageID, node.String(), sanitizedImageName)
-				if err := nodeutils.ReTagImage(node, imageID, sanitizedImageName); err != nil {
-					logger.Errorf("failed to re-tag image on the node %s due to an error %s. Will load it instead...", node.String(), err)
-					selectedNodes[node.String()] = node
-				} else {
-					processed = true
-				}
-				continue
-			}
-			id, err := nodeutils.ImageID(node, imageName)
-			if err != nil || id != imageID {
-				selectedNodes[node.String()] = node
-				logger.V(0).Infof("Image: %q with ID %q not yet present on node %q, loading...", imageName, imageID, node.String())
+		for _, node := range imageload.NodesNeedingImage(logger, candidateNodes, imageIDs[i], imageName) {
+			if !seen[node.String()] {
+				seen[node.String()] = true
+				selectedNodes = append(selectedNodes, node)
 			}
-			continue
-		}
-		if len(selectedNodes) == 0 && !processed {
-			logger.V(0).Infof("Image: %q with ID %q found to be already present on all nodes.", imageName, imageID)
 		}
 	}
 
@@ -170,45 +145,39 @@ func runE(logger log.Logger, flags *flagpole, args []string) error {
 		return nil
 	}
 
-	// Setup the tar path where the images will be saved
-	dir, err := fs.TempDir("", "images-tar")
+	// Stream the saved images into the selected nodes
+	return imageload.LoadArchive(logger, selectedNodes, platform.String(), func(w io.Writer) error {
+		return save(imageNames, w)
+	})
+}
+
+// save writes an archive of images to w, as in `docker save`
+func save(images []string, w io.Writer) error {
+	commandArgs := append([]string{"save"}, images...)
+	return exec.Command("docker", commandArgs...).SetStdout(w).Run()
+}
+
+// checkPlatform returns an error if the local image is not for platform
+func checkPlatform(image string, platform imageload.Platform) error {
+	cmd := exec.Command("docker", "image", "inspect",
+		"-f", "{{ .Os }}/{{ .Architecture }}{{ with .Variant }}/{{ . }}{{ end }}",
+		image,
+	)
+	lines, err := exec.OutputLines(cmd)
 	if err != nil {
-		return errors.Wrap(err, "failed to create tempdir")
+		return errors.Wrapf(err, "failed to get platform of image %q", image)
 	}
-	defer os.RemoveAll(dir)
-	imagesTarPath := filepath.Join(dir, "images.tar")
-	// Save the images into a tar
-	err = save(imageNames, imagesTarPath)
+	if len(lines) != 1 {
+		return errors.Errorf("image platform should only be one line, got %d lines", len(lines))
+	}
+	imagePlatform, err := imageload.ParsePlatform(lines[0])
 	if err != nil {
 		return err
 	}
-
-	// Load the images on the selected nodes
-	for _, selectedNode := range selectedNodes {
-		selectedNode := selectedNode // capture loop variable
-		fns = append(fns, func() error {
-			return loadImage(imagesTarPath, selectedNode)
-		})
-	}
-	return errors.UntilErrorConcurrent(fns)
-}
-
-// TODO: we should consider having a cluster method to load images
-
-// loads an image tarball onto a node
-func loadImage(imageTarName string, node nodes.Node) error {
-	f, err := os.Open(imageTarName)
-	if err != nil {
-		return errors.Wrap(err, "failed to open image")
+	if !platform.Matches(imagePlatform) {
+		return errors.Errorf("image %q is for platform %s, not %s", image, imagePlatform, platform)
 	}
-	defer f.Close()
-	return nodeutils.LoadImageArchive(node, f)
-}
-
-// save saves images to dest, as in `docker save`
-func save(images []string, dest string) error {
-	commandArgs := append([]string{"save", "-o", dest}, images...)
-	return exec.Command("docker", commandArgs...).Run()
+	return nil
 }
 
 // imageID return the Id of the container image
@@ -239,51 +208,3 @@ func removeDuplicates(slice []string) []string {
 	}
 	return result
 }
-
-// checkIfImageExists makes sure we only perform the reverse lookup of the ImageID to tag map
-func checkIfImageReTagRequired(node nodes.Node, imageID, imageName string, tagFetcher imageTagFetcher) (exists, reTagRequired bool, sanitizedImage string) {
-	tags, err := tagFetcher(node, imageID)
-	if len(tags) == 0 || err != nil {
-		exists = false
-		return
-	}
-	exists = true
-	sanitizedImage = sanitizeImage(imageName)
-	if ok := tags[sanitizedImage]; ok {
-		reTagRequired = false
-		return
-	}
-	reTagRequired = true
-	return
-}
-
-// sanitizeImage is a helper to return human readable image name
-// This is a modified version of the same function found under providers/podman/images.go
-func sanitizeImage(image string) (sanitizedName string) {
-	const (
-		defaultDomain    = "docker.io/"
-		officialRepoName = "library"
-	)
-	sanitizedName = image
-
-	if !strings.ContainsRune(image, '/') {
-		sanitizedName = officialRepoName + "/" + image
-	}
-
-	i := strings.IndexRune(sanitizedName, '/')
-	if i == -1 || (!strings.ContainsAny(sanitizedName[:i], ".:") && sanitizedName[:i] != "localhost") {
-		sanitizedName = defaultDomain + sanitizedName
-	}
-
-	if !hasTag(sanitizedName) && !strings.ContainsRune(sanitizedName, '@') {
-		sanitizedName += ":latest"
-	}
-
-	return
-}
-
-func hasTag(image string) bool {
-	lastSlash := strings.LastIndex(image, "/")
-	lastColon := strings.LastIndex(image, ":")
-	return lastColon > lastSlash
-}
@@ -19,24 +19,26 @@ package load
 
 import (
 	"fmt"
+	"io"
 	"os"
 
 	"github.com/spf13/cobra"
 	"sigs.k8s.io/kind/pkg/errors"
 
 	"sigs.k8s.io/kind/pkg/cluster"
-	"sigs.k8s.io/kind/pkg/cluster/nodes"
-	"sigs.k8s.io/kind/pkg/cluster/nodeutils"
 	"sigs.k8s.io/kind/pkg/cmd"
 	"sigs.k8s.io/kind/pkg/log"
 
 	"sigs.k8s.io/kind/pkg/internal/cli"
+	"sigs.k8s.io/kind/pkg/internal/completion"
+	"sigs.k8s.io/kind/pkg/internal/imageload"
 	"sigs.k8s.io/kind/pkg/internal/runtime"
 )
 
 type flagpole struct {
-	Name  string
-	Nodes []string
+	Name     string
+	Nodes    []string
+	Platform string
 }
 
 // NewCommand returns a new cobra.Command for loading an image into a cluster
@@ -75,6 +77,14 @@ func NewCommand(logger log.Logger, streams cmd.IOStreams) *cobra.Command {
 		nil,
 		"comma separated list of nodes to load images into",
 	)
+	cmd.Flags().StringVar(
+		&flags.Platform,
+		"platform",
+		"",
+		"the platform to load, E.G. linux/arm64, defaults to the platform of the nodes",
+	)
+	completion.RegisterFlag(cmd, "name", completion.ClusterNames)
+	completion.RegisterFlag(cmd, "nodes", completion.NodeNameList)
 	return cmd
 }
 
@@ -91,14 +101,14 @@ func runE(logger log.Logger, flags *flagpole, args []string) error {
 	}
 
 	for _, imageTarPath := range args {
-		if err := loadArchiveToNodes(logger, provider, flags.Name, flags.Nodes, imageTarPath); err != nil {
+		if err := loadArchiveToNodes(logger, provider, flags.Name, flags.Nodes, flags.Platform, imageTarPath); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
-func loadArchiveToNodes(logger log.Logger, provider *cluster.Provider, clusterName string, nodeNames []string, imageArchivePath string) error {
+func loadArchiveToNodes(logger log.Logger, provider *cluster.Provider, clusterName string, nodeNames []string, platformFlag, imageArchivePath string) error {
 	// Check if the cluster nodes exist
 	nodeList, err := provider.ListInternalNodes(clusterName)
 	if err != nil {
@@ -108,46 +118,31 @@ func loadArchiveToNodes(logger log.Logger, provider *cluster.Provider, clusterNa
 		return fmt.Errorf("no nodes found for cluster %q", clusterName)
 	}
 
-	// map cluster nodes by their name
-	nodesByName := map[string]nodes.Node{}
-	for _, node := range nodeList {
-		// TODO(bentheelder): this depends on the fact that ListByCluster()
-		// will have name for nameOrId.
-		nodesByName[node.String()] = node
-	}
-
 	// pick only the user selected nodes and ensure they exist
 	// the default is all nodes unless flags.Nodes is set
-	selectedNodes := nodeList
-	if len(nodeNames) > 0 {
-		selectedNodes = []nodes.Node{}
-		for _, name := range nodeNames {
-			node, ok := nodesByName[name]
-			if !ok {
-				return fmt.Errorf("unknown node: %s", name)
-			}
-			selectedNodes = append(selectedNodes, node)
-		}
-	}
-
-	// Load the image on the selected nodes
-	fns := []func() error{}
-	for _, selectedNode := range selectedNodes {
-		selectedNode := selectedNode // capture loop variable
-		fns = append(fns, func() error {
-			return loadImage(logger, imageArchivePath, selectedNode)
-		})
+	selectedNodes, err := imageload.SelectNodes(nodeList, nodeNames)
+	if err != nil {
+		return err
 	}
-	return errors.UntilErrorConcurrent(fns)
-}
 
-// loads an image tarball onto a node
-func loadImage(logger log.Logger, imageTarName string, node nodes.Node) error {
-	f, err := os.Open(imageTarName)
+	// load only the requested platform, or the platform of the nodes
+	platform, err := imageload.NodePlatform(platformFlag, selectedNodes[0])
 	if err != nil {
-		return errors.Wrap(err, "failed to open image")
+		return err
+	}
+	if err := imageload.CheckArchivePlatform(imageArchivePath, platform); err != nil {
+		return err
 	}
-	defer f.Close()
-	logger.V(2).Infof("Loading Docker Image from archive %s to node %s", imageTarName, node.String())
-	return nodeutils.LoadImageArchive(node, f)
+
+	// Stream the archive into the selected nodes
+	logger.V(2).Infof("Loading %s image archive %s", platform, imageArchivePath)
+	return imageload.LoadArchive(logger, selectedNodes, platform.String(), func(w io.Writer) error {
+		f, err := os.Open(imageArchivePath)
+		if err != nil {
+			return errors.Wrap(err, "failed to open image")
+		}
+		defer f.Close()
+		_, err = io.Copy(w, f)
+		return err
+	})
 }
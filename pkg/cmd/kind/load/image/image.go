@@ -0,0 +1,139 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package load implements the `load image` command
+package load
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/kind/pkg/cluster"
+	"sigs.k8s.io/kind/pkg/cmd"
+	"sigs.k8s.io/kind/pkg/errors"
+	"sigs.k8s.io/kind/pkg/log"
+
+	"sigs.k8s.io/kind/pkg/internal/cli"
+	"sigs.k8s.io/kind/pkg/internal/completion"
+	"sigs.k8s.io/kind/pkg/internal/imageload"
+	"sigs.k8s.io/kind/pkg/internal/runtime"
+)
+
+type flagpole struct {
+	Name     string
+	Nodes    []string
+	Platform string
+}
+
+// NewCommand returns a new cobra.Command for loading images into a cluster
+func NewCommand(logger log.Logger, streams cmd.IOStreams) *cobra.Command {
+	flags := &flagpole{}
+	cmd := &cobra.Command{
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) < 1 {
+				return errors.New("a list of image references is required")
+			}
+			return nil
+		},
+		Use:   "image <IMAGE> [IMAGE...]",
+		Short: "Loads images from the provider, OCI layouts or registries into nodes",
+		Long: `Loads images into all or specified nodes by name.
+
+Images are loaded from the local image store of the node provider, E.G.
+podman images when using the podman provider, unless prefixed with a source:
+
+  oci:<dir>[:<image>]            an OCI image layout directory
+  oci-archive:<file>[:<image>]   a tar archive of an OCI image layout
+  registry:<image>               an image in a registry, E.G. a local registry
+
+OCI layouts must name the image if it is not annotated with a name.
+OCI layouts and registries may contain images for multiple platforms, only
+the platform of the nodes is loaded unless --platform is set.`,
+		Example: `  kind load image my-app:latest
+  kind load image oci:./my-app-layout:my-app:latest
+  kind load image registry:localhost:5001/my-app:latest`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cli.OverrideDefaultName(cmd.Flags())
+			return runE(logger, flags, args)
+		},
+	}
+	cmd.Flags().StringVarP(
+		&flags.Name,
+		"name",
+		"n",
+		cluster.DefaultName,
+		"the cluster context name",
+	)
+	cmd.Flags().StringSliceVar(
+		&flags.Nodes,
+		"nodes",
+		nil,
+		"comma separated list of nodes to load images into",
+	)
+	cmd.Flags().StringVar(
+		&flags.Platform,
+		"platform",
+		"",
+		"the platform to load, E.G. linux/arm64, defaults to the platform of the nodes",
+	)
+	completion.RegisterFlag(cmd, "name", completion.ClusterNames)
+	completion.RegisterFlag(cmd, "nodes", completion.NodeNameList)
+	return cmd
+}
+
+func runE(logger log.Logger, flags *flagpole, args []string) error {
+	provider := cluster.NewProvider(
+		cluster.ProviderWithLogger(logger),
+		runtime.GetDefault(logger),
+	)
+
+	// Check if the cluster nodes exist
+	nodeList, err := provider.ListInternalNodes(flags.Name)
+	if err != nil {
+		return err
+	}
+	if len(nodeList) == 0 {
+		return fmt.Errorf("no nodes found for cluster %q", flags.Name)
+	}
+	candidateNodes, err := imageload.SelectNodes(nodeList, flags.Nodes)
+	if err != nil {
+		return err
+	}
+
+	// load only the requested platform, or the platform of the nodes
+	platform, err := imageload.NodePlatform(flags.Platform, candidateNodes[0])
+	if err != nil {
+		return err
+	}
+
+	// resolve all of the sources before loading any of them
+	sources := []imageload.Source{}
+	for _, ref := range args {
+		src, err := imageload.Parse(ref, provider, platform)
+		if err != nil {
+			return err
+		}
+		sources = append(sources, src)
+	}
+
+	for _, src := range sources {
+		if err := imageload.Load(logger, src, candidateNodes); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,214 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package logs implements the `logs` command
+package logs
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/kind/pkg/cluster"
+	"sigs.k8s.io/kind/pkg/cluster/nodes"
+	"sigs.k8s.io/kind/pkg/cluster/nodeutils"
+	"sigs.k8s.io/kind/pkg/cmd"
+	"sigs.k8s.io/kind/pkg/errors"
+	kindexec "sigs.k8s.io/kind/pkg/exec"
+	"sigs.k8s.io/kind/pkg/log"
+
+	"sigs.k8s.io/kind/pkg/internal/cli"
+	"sigs.k8s.io/kind/pkg/internal/completion"
+	"sigs.k8s.io/kind/pkg/internal/runtime"
+)
+
+type flagpole struct {
+	Name   string
+	Nodes  []string
+	Unit   string
+	Follow bool
+	Since  time.Duration
+	Serial bool
+}
+
+// NewCommand returns a new cobra.Command for streaming node logs
+func NewCommand(logger log.Logger, streams cmd.IOStreams) *cobra.Command {
+	flags := &flagpole{}
+	cmd := &cobra.Command{
+		Args:  cobra.NoArgs,
+		Use:   "logs",
+		Short: "Prints or follows the logs of the nodes",
+		Long: `Prints or follows the systemd journal of the nodes concurrently, with each
+line prefixed by the node name. For a one-time dump of all logs to files use
+"kind export logs" instead.
+
+  kind logs --unit kubelet -f
+  kind logs --node kind-worker --unit containerd --since 10m
+  kind logs --serial -f
+`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cli.OverrideDefaultName(cmd.Flags())
+			return runE(logger, streams, flags)
+		},
+	}
+	cmd.Flags().StringVarP(
+		&flags.Name,
+		"name",
+		"n",
+		cluster.DefaultName,
+		"the cluster context name",
+	)
+	cmd.Flags().StringSliceVar(
+		&flags.Nodes,
+		"node",
+		nil,
+		"the nodes to print logs of, may be repeated (default all nodes)",
+	)
+	cmd.Flags().StringVar(
+		&flags.Unit,
+		"unit",
+		"",
+		"only print logs of this systemd unit, E.G. kubelet or containerd (default all units)",
+	)
+	cmd.Flags().BoolVarP(
+		&flags.Follow,
+		"follow",
+		"f",
+		false,
+		"follow the logs as they are written",
+	)
+	cmd.Flags().DurationVar(
+		&flags.Since,
+		"since",
+		0,
+		"only print logs newer than this duration, E.G. 10m",
+	)
+	cmd.Flags().BoolVar(
+		&flags.Serial,
+		"serial",
+		false,
+		"print the node container console output instead of the systemd journal",
+	)
+	completion.RegisterFlag(cmd, "name", completion.ClusterNames)
+	completion.RegisterFlag(cmd, "node", completion.NodeNameList)
+	return cmd
+}
+
+func runE(logger log.Logger, streams cmd.IOStreams, flags *flagpole) error {
+	if flags.Serial && flags.Unit != "" {
+		return errors.New("--unit may not be used with --serial")
+	}
+	if flags.Since < 0 {
+		return errors.New("--since must not be negative")
+	}
+
+	provider := cluster.NewProvider(
+		cluster.ProviderWithLogger(logger),
+		runtime.GetDefault(logger),
+	)
+	allNodes, err := provider.ListNodes(flags.Name)
+	if err != nil {
+		return err
+	}
+	selected, err := selectNodes(allNodes, flags.Nodes, flags.Serial)
+	if err != nil {
+		return err
+	}
+	if len(selected) == 0 {
+		return errors.Errorf("no nodes found for cluster %q", flags.Name)
+	}
+
+	var mu sync.Mutex
+	fns := []func() error{}
+	for _, n := range selected {
+		n := n // capture loop variable
+		fns = append(fns, func() error {
+			w := cli.NewPrefixWriter(streams.Out, &mu, fmt.Sprintf("[%s] ", n.String()))
+			err := streamLogs(n, w, flags)
+			_ = w.Flush()
+			if err != nil {
+				return errors.Wrapf(err, "failed to get logs of node %q", n.String())
+			}
+			return nil
+		})
+	}
+	return errors.AggregateConcurrent(fns)
+}
+
+// selectNodes returns the named nodes, or all nodes with a journal
+// if names is empty. Only the serial logs include the load balancer.
+func selectNodes(allNodes []nodes.Node, names []string, serial bool) ([]nodes.Node, error) {
+	if len(names) == 0 {
+		if serial {
+			return allNodes, nil
+		}
+		return nodeutils.InternalNodes(allNodes)
+	}
+	byName := map[string]nodes.Node{}
+	for _, n := range allNodes {
+		byName[n.String()] = n
+	}
+	selected := []nodes.Node{}
+	for _, name := range names {
+		n, exists := byName[name]
+		if !exists {
+			return nil, errors.Errorf("unknown node %q", name)
+		}
+		selected = append(selected, n)
+	}
+	return selected, nil
+}
+
+func streamLogs(n nodes.Node, w io.Writer, flags *flagpole) error {
+	if flags.Serial {
+		type serialLogsStreamer interface {
+			StreamSerialLogs(w io.Writer, follow bool, since time.Duration) error
+		}
+		if v, ok := n.(serialLogsStreamer); ok {
+			return v.StreamSerialLogs(w, flags.Follow, flags.Since)
+		}
+		if flags.Follow || flags.Since > 0 {
+			return errors.New("the node provider does not support --follow or --since with --serial")
+		}
+		return n.SerialLogs(w)
+	}
+	// the logs may be followed indefinitely, so do not keep a copy of them
+	out := kindexec.Uncaptured(w)
+	return n.Command("journalctl", journalctlArgs(flags)...).SetStdout(out).SetStderr(out).Run()
+}
+
+// journalctlArgs returns the journalctl arguments for flags, the default
+// short-iso output format includes timestamps
+func journalctlArgs(flags *flagpole) []string {
+	args := []string{"--no-pager", "--output=short-iso"}
+	if flags.Unit != "" {
+		args = append(args, "--unit", flags.Unit)
+	}
+	if flags.Follow {
+		args = append(args, "--follow")
+	}
+	if flags.Since > 0 {
+		// journalctl only takes whole seconds, round up to include
+		// everything newer than flags.Since
+		seconds := int64(math.Ceil(flags.Since.Seconds()))
+		args = append(args, fmt.Sprintf("--since=-%ds", seconds))
+	}
+	return args
+}
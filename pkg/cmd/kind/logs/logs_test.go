@@ -0,0 +1,129 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logs
+
+import (
+	"testing"
+	"time"
+
+	"sigs.k8s.io/kind/pkg/cluster/constants"
+	"sigs.k8s.io/kind/pkg/cluster/nodes"
+	"sigs.k8s.io/kind/pkg/internal/assert"
+)
+
+// fakeNode implements the parts of nodes.Node used for selection
+type fakeNode struct {
+	nodes.Node
+	name string
+	role string
+}
+
+func (n *fakeNode) String() string {
+	return n.name
+}
+
+func (n *fakeNode) Role() (string, error) {
+	return n.role, nil
+}
+
+func TestSelectNodes(t *testing.T) {
+	t.Parallel()
+	allNodes := []nodes.Node{
+		&fakeNode{name: "kind-control-plane", role: constants.ControlPlaneNodeRoleValue},
+		&fakeNode{name: "kind-worker", role: constants.WorkerNodeRoleValue},
+		&fakeNode{name: "kind-external-load-balancer", role: constants.ExternalLoadBalancerNodeRoleValue},
+	}
+	cases := []struct {
+		name        string
+		names       []string
+		serial      bool
+		expected    []string
+		expectError bool
+	}{
+		{
+			name:     "journal of all kubernetes nodes",
+			expected: []string{"kind-control-plane", "kind-worker"},
+		},
+		{
+			name:     "serial logs include the load balancer",
+			serial:   true,
+			expected: []string{"kind-control-plane", "kind-worker", "kind-external-load-balancer"},
+		},
+		{
+			name:     "named nodes in the given order",
+			names:    []string{"kind-worker", "kind-external-load-balancer"},
+			expected: []string{"kind-worker", "kind-external-load-balancer"},
+		},
+		{
+			name:        "unknown node",
+			names:       []string{"kind-worker2"},
+			expectError: true,
+		},
+	}
+	for _, tc := range cases {
+		tc := tc // capture range variable
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			selected, err := selectNodes(allNodes, tc.names, tc.serial)
+			assert.ExpectError(t, tc.expectError, err)
+			if err != nil {
+				return
+			}
+			names := []string{}
+			for _, n := range selected {
+				names = append(names, n.String())
+			}
+			assert.DeepEqual(t, tc.expected, names)
+		})
+	}
+}
+
+func TestJournalctlArgs(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name     string
+		flags    flagpole
+		expected []string
+	}{
+		{
+			name:     "defaults",
+			expected: []string{"--no-pager", "--output=short-iso"},
+		},
+		{
+			name:     "unit and follow",
+			flags:    flagpole{Unit: "kubelet", Follow: true},
+			expected: []string{"--no-pager", "--output=short-iso", "--unit", "kubelet", "--follow"},
+		},
+		{
+			name:     "since whole seconds",
+			flags:    flagpole{Since: 10 * time.Minute},
+			expected: []string{"--no-pager", "--output=short-iso", "--since=-600s"},
+		},
+		{
+			name:     "since is rounded up",
+			flags:    flagpole{Since: 500 * time.Millisecond},
+			expected: []string{"--no-pager", "--output=short-iso", "--since=-1s"},
+		},
+	}
+	for _, tc := range cases {
+		tc := tc // capture range variable
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			assert.DeepEqual(t, tc.expected, journalctlArgs(&tc.flags))
+		})
+	}
+}
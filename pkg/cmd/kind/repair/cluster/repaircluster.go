@@ -0,0 +1,90 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package cluster implements the `repair cluster` command
+package cluster
+
+import (
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/kind/pkg/cluster"
+	"sigs.k8s.io/kind/pkg/cmd"
+	"sigs.k8s.io/kind/pkg/errors"
+	"sigs.k8s.io/kind/pkg/log"
+
+	"sigs.k8s.io/kind/pkg/internal/cli"
+	"sigs.k8s.io/kind/pkg/internal/completion"
+	"sigs.k8s.io/kind/pkg/internal/runtime"
+)
+
+type flagpole struct {
+	Name       string
+	Kubeconfig string
+}
+
+// NewCommand returns a new cobra.Command for cluster repair
+func NewCommand(logger log.Logger, streams cmd.IOStreams) *cobra.Command {
+	flags := &flagpole{}
+	cmd := &cobra.Command{
+		Args:  cobra.NoArgs,
+		Use:   "cluster",
+		Short: "Repairs a cluster after a host reboot",
+		Long: `Repairs a Kind cluster after the node containers have been restarted.
+
+Following a host or container runtime restart the node containers may be
+assigned new IP addresses. This detects nodes whose address no longer matches
+the address the kubelet was configured with, rewrites the affected
+configuration, restarts the Kubernetes components, regenerates the load
+balancer config, reinstalls the routes to other multi-cluster clusters and
+re-exports the kubeconfig.
+
+This is safe to run on a healthy cluster, in which case only the load balancer
+config and kubeconfig are refreshed.
+`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cli.OverrideDefaultName(cmd.Flags())
+			return repairCluster(logger, flags)
+		},
+	}
+	cmd.Flags().StringVarP(
+		&flags.Name,
+		"name",
+		"n",
+		cluster.DefaultName,
+		"the cluster name",
+	)
+	cmd.Flags().StringVar(
+		&flags.Kubeconfig,
+		"kubeconfig",
+		"",
+		"sets kubeconfig path instead of $KUBECONFIG or $HOME/.kube/config",
+	)
+	completion.RegisterFlag(cmd, "name", completion.ClusterNames)
+	return cmd
+}
+
+func repairCluster(logger log.Logger, flags *flagpole) error {
+	provider := cluster.NewProvider(
+		cluster.ProviderWithLogger(logger),
+		runtime.GetDefault(logger),
+		runtime.GetKubeConfigStrategy(logger),
+	)
+	logger.V(0).Infof("Repairing cluster %q ...", flags.Name)
+	if err := provider.Repair(flags.Name, flags.Kubeconfig); err != nil {
+		return errors.Wrapf(err, "failed to repair cluster %q", flags.Name)
+	}
+	return nil
+}
@@ -21,17 +21,27 @@ import (
 	"io"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 
 	"sigs.k8s.io/kind/pkg/cmd"
 	"sigs.k8s.io/kind/pkg/cmd/kind/build"
 	"sigs.k8s.io/kind/pkg/cmd/kind/completion"
+	"sigs.k8s.io/kind/pkg/cmd/kind/cp"
 	"sigs.k8s.io/kind/pkg/cmd/kind/create"
 	"sigs.k8s.io/kind/pkg/cmd/kind/delete"
+	"sigs.k8s.io/kind/pkg/cmd/kind/doctor"
+	"sigs.k8s.io/kind/pkg/cmd/kind/env"
+	"sigs.k8s.io/kind/pkg/cmd/kind/exec"
 	"sigs.k8s.io/kind/pkg/cmd/kind/export"
+	"sigs.k8s.io/kind/pkg/cmd/kind/expose"
 	"sigs.k8s.io/kind/pkg/cmd/kind/get"
 	"sigs.k8s.io/kind/pkg/cmd/kind/load"
+	"sigs.k8s.io/kind/pkg/cmd/kind/logs"
+	"sigs.k8s.io/kind/pkg/cmd/kind/repair"
 	"sigs.k8s.io/kind/pkg/cmd/kind/version"
 	"sigs.k8s.io/kind/pkg/log"
+
+	"sigs.k8s.io/kind/pkg/internal/settings"
 )
 
 type flagpole struct {
@@ -47,7 +57,7 @@ func NewCommand(logger log.Logger, streams cmd.IOStreams) *cobra.Command {
 		Short: "kind is a tool for managing local Kubernetes clusters",
 		Long:  "kind creates and manages local Kubernetes clusters using Docker container 'nodes'",
 		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
-			return runE(logger, flags)
+			return runE(logger, flags, cmd.Flags())
 		},
 		SilenceUsage:  true,
 		SilenceErrors: true,
@@ -72,16 +82,33 @@ func NewCommand(logger log.Logger, streams cmd.IOStreams) *cobra.Command {
 	// add all top level subcommands
 	cmd.AddCommand(build.NewCommand(logger, streams))
 	cmd.AddCommand(completion.NewCommand(logger, streams))
+	cmd.AddCommand(cp.NewCommand(logger, streams))
 	cmd.AddCommand(create.NewCommand(logger, streams))
 	cmd.AddCommand(delete.NewCommand(logger, streams))
+	cmd.AddCommand(doctor.NewCommand(logger, streams))
+	cmd.AddCommand(env.NewCommand(logger, streams))
+	cmd.AddCommand(exec.NewCommand(logger, streams))
+	cmd.AddCommand(expose.NewCommand(logger, streams))
 	cmd.AddCommand(export.NewCommand(logger, streams))
 	cmd.AddCommand(get.NewCommand(logger, streams))
 	cmd.AddCommand(version.NewCommand(logger, streams))
 	cmd.AddCommand(load.NewCommand(logger, streams))
+	cmd.AddCommand(logs.NewCommand(logger, streams))
+	cmd.AddCommand(repair.NewCommand(logger, streams))
 	return cmd
 }
 
-func runE(logger log.Logger, flags *flagpole) error {
+func runE(logger log.Logger, flags *flagpole, fs *pflag.FlagSet) error {
+	// default verbosity from the kind settings file, which are ignored if
+	// invalid so that every command, including `kind get settings`, works
+	s, err := settings.Load()
+	if err != nil {
+		logger.Warnf("ignoring kind settings: %v", err)
+		s = settings.Get()
+	}
+	if !fs.Changed("verbosity") && s.Verbosity != nil {
+		flags.Verbosity = *s.Verbosity
+	}
 	// normal logger setup
 	if flags.Quiet {
 		// NOTE: if we are coming from app.Run handling this flag is
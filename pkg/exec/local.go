@@ -88,7 +88,27 @@ func (cmd *LocalCmd) Run() error {
 	// IFF ! interfaceEqual(cmd.Sterr, cmd.Stdout)
 	var combinedOutput bytes.Buffer
 	var combinedOutputWriter io.Writer = &combinedOutput
-	if cmd.Stdout == nil && cmd.Stderr == nil {
+	stdout, stdoutUncaptured := cmd.Stdout.(*uncapturedWriter)
+	stderr, stderrUncaptured := cmd.Stderr.(*uncapturedWriter)
+	if stdoutUncaptured || stderrUncaptured {
+		// Uncaptured writers are handed to the command as is, so that E.G.
+		// an *os.File is used by the child process directly.
+		// Only the other stream, if any, is captured, by a single goroutine
+		if stdoutUncaptured {
+			cmd.Stdout = stdout.Writer
+		} else if cmd.Stdout != nil {
+			cmd.Stdout = io.MultiWriter(cmd.Stdout, combinedOutputWriter)
+		} else {
+			cmd.Stdout = combinedOutputWriter
+		}
+		if stderrUncaptured {
+			cmd.Stderr = stderr.Writer
+		} else if cmd.Stderr != nil {
+			cmd.Stderr = io.MultiWriter(cmd.Stderr, combinedOutputWriter)
+		} else {
+			cmd.Stderr = combinedOutputWriter
+		}
+	} else if cmd.Stdout == nil && cmd.Stderr == nil {
 		// Case 1: If stdout and stderr are nil, we can just use the buffer
 		// The buffer will be == and Go will use one fd / goroutine
 		cmd.Stdout = combinedOutputWriter
@@ -130,6 +150,18 @@ func (cmd *LocalCmd) Run() error {
 	return nil
 }
 
+// Uncaptured wraps w so that LocalCmd writes the command output to it
+// without keeping a copy for RunError, E.G. for long running or interactive
+// commands. If w is an *os.File it is used by the command directly, which is
+// required for commands that check whether their output is a terminal.
+func Uncaptured(w io.Writer) io.Writer {
+	return &uncapturedWriter{Writer: w}
+}
+
+type uncapturedWriter struct {
+	io.Writer
+}
+
 // interfaceEqual protects against panics from doing equality tests on
 // two interfaces with non-comparable underlying types.
 // This trivial is borrowed from the go stdlib in os/exec
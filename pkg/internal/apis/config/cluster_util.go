@@ -16,6 +16,8 @@ limitations under the License.
 
 package config
 
+import "net"
+
 // ClusterHasIPv6 returns true if the cluster should have IPv6 enabled due to either
 // being IPv6 cluster family or Dual Stack
 func ClusterHasIPv6(c *Cluster) bool {
@@ -32,3 +34,23 @@ func ClusterHasImplicitLoadBalancer(c *Cluster) bool {
 	}
 	return controlPlanes > 1
 }
+
+// DynamicAddressRange returns the upper half of the subnet as a CIDR, from
+// which the runtime assigns addresses to containers without a static address.
+// The lower half is reserved for static node addresses.
+// It returns "" if the subnet is invalid or too small to be split.
+func DynamicAddressRange(subnet string) string {
+	_, cidr, err := net.ParseCIDR(subnet)
+	if err != nil {
+		return ""
+	}
+	ones, bits := cidr.Mask.Size()
+	// keep at least two addresses in each half
+	if ones+2 > bits {
+		return ""
+	}
+	ip := make(net.IP, len(cidr.IP))
+	copy(ip, cidr.IP)
+	ip[ones/8] |= 0x80 >> uint(ones%8)
+	return (&net.IPNet{IP: ip, Mask: net.CIDRMask(ones+1, bits)}).String()
+}
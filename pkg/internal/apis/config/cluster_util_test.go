@@ -136,3 +136,44 @@ func TestClusterHasImplicitLoadBalancer(t *testing.T) {
 		})
 	}
 }
+
+func TestDynamicAddressRange(t *testing.T) {
+	cases := []struct {
+		Name     string
+		subnet   string
+		expected string
+	}{
+		{
+			Name:     "IPv4",
+			subnet:   "172.30.0.0/16",
+			expected: "172.30.128.0/17",
+		},
+		{
+			Name:     "IPv4 unaligned prefix",
+			subnet:   "10.1.2.0/27",
+			expected: "10.1.2.16/28",
+		},
+		{
+			Name:     "IPv6",
+			subnet:   "fc00:f853:ccd:e793::/64",
+			expected: "fc00:f853:ccd:e793:8000::/65",
+		},
+		{
+			Name:     "too small",
+			subnet:   "10.1.2.0/31",
+			expected: "",
+		},
+		{
+			Name:     "invalid",
+			subnet:   "10.1.2.0",
+			expected: "",
+		},
+	}
+	for _, tc := range cases {
+		tc := tc // capture loop var
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+			assert.StringEqual(t, tc.expected, DynamicAddressRange(tc.subnet))
+		})
+	}
+}
@@ -26,6 +26,8 @@ func Convertv1alpha4(in *v1alpha4.Cluster) *Cluster {
 	out := &Cluster{
 		Name:                            in.Name,
 		Nodes:                           make([]Node, len(in.Nodes)),
+		Labels:                          in.Labels,
+		Annotations:                     in.Annotations,
 		FeatureGates:                    in.FeatureGates,
 		RuntimeConfig:                   in.RuntimeConfig,
 		KubeadmConfigPatches:            in.KubeadmConfigPatches,
@@ -52,6 +54,9 @@ func convertv1alpha4Node(in *v1alpha4.Node, out *Node) {
 	out.Image = in.Image
 
 	out.Labels = in.Labels
+	out.IPv4Address = in.IPv4Address
+	out.IPv6Address = in.IPv6Address
+	out.ExtraNetworks = make([]NodeNetwork, len(in.ExtraNetworks))
 	out.KubeadmConfigPatches = in.KubeadmConfigPatches
 	out.ExtraMounts = make([]Mount, len(in.ExtraMounts))
 	out.ExtraPortMappings = make([]PortMapping, len(in.ExtraPortMappings))
@@ -68,6 +73,16 @@ func convertv1alpha4Node(in *v1alpha4.Node, out *Node) {
 	for i := range in.KubeadmConfigPatchesJSON6902 {
 		convertv1alpha4PatchJSON6902(&in.KubeadmConfigPatchesJSON6902[i], &out.KubeadmConfigPatchesJSON6902[i])
 	}
+
+	for i := range in.ExtraNetworks {
+		convertv1alpha4NodeNetwork(&in.ExtraNetworks[i], &out.ExtraNetworks[i])
+	}
+}
+
+func convertv1alpha4NodeNetwork(in *v1alpha4.NodeNetwork, out *NodeNetwork) {
+	out.Name = in.Name
+	out.IPv4Address = in.IPv4Address
+	out.IPv6Address = in.IPv6Address
 }
 
 func convertv1alpha4PatchJSON6902(in *v1alpha4.PatchJSON6902, out *PatchJSON6902) {
@@ -86,6 +101,17 @@ func convertv1alpha4Networking(in *v1alpha4.Networking, out *Networking) {
 	out.ServiceSubnet = in.ServiceSubnet
 	out.DisableDefaultCNI = in.DisableDefaultCNI
 	out.DNSSearch = in.DNSSearch
+	convertv1alpha4ContainerNetwork(&in.ContainerNetwork, &out.ContainerNetwork)
+	out.MultiCluster = in.MultiCluster
+}
+
+func convertv1alpha4ContainerNetwork(in *v1alpha4.ContainerNetwork, out *ContainerNetwork) {
+	out.Name = in.Name
+	out.IPv4Subnet = in.IPv4Subnet
+	out.IPv4Gateway = in.IPv4Gateway
+	out.IPv6Subnet = in.IPv6Subnet
+	out.IPv6Gateway = in.IPv6Gateway
+	out.MTU = in.MTU
 }
 
 func convertv1alpha4Mount(in *v1alpha4.Mount, out *Mount) {
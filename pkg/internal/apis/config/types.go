@@ -34,6 +34,14 @@ type Cluster struct {
 	// control plane load balancer will be provisioned implicitly
 	Nodes []Node
 
+	// Labels are metadata for identifying the cluster itself, they are
+	// stored on every node container
+	Labels map[string]string
+
+	// Annotations are additional unselectable metadata for the cluster itself,
+	// stored on every node container
+	Annotations map[string]string
+
 	/* Advanced fields */
 
 	// Networking contains cluster wide network settings
@@ -90,6 +98,18 @@ type Node struct {
 
 	/* Advanced fields */
 
+	// IPv4Address is a static IPv4 address for the node container on the
+	// cluster's container network, if unset the runtime will assign one
+	IPv4Address string
+
+	// IPv6Address is a static IPv6 address for the node container on the
+	// cluster's container network, if unset the runtime will assign one
+	IPv6Address string
+
+	// ExtraNetworks are additional existing container networks the node
+	// container is connected to after creation
+	ExtraNetworks []NodeNetwork
+
 	// ExtraMounts describes additional mount points for the node container
 	// These may be used to bind a hostPath
 	ExtraMounts []Mount
@@ -109,6 +129,16 @@ type Node struct {
 	KubeadmConfigPatchesJSON6902 []PatchJSON6902
 }
 
+// NodeNetwork is an additional container network for a node
+type NodeNetwork struct {
+	// Name is the name of the existing container network
+	Name string
+	// IPv4Address is an optional static IPv4 address for the node on this network
+	IPv4Address string
+	// IPv6Address is an optional static IPv6 address for the node on this network
+	IPv6Address string
+}
+
 // NodeRole defines possible role for nodes in a Kubernetes cluster managed by `kind`
 type NodeRole string
 
@@ -152,6 +182,29 @@ type Networking struct {
 	KubeProxyMode ProxyMode
 	// DNSSearch defines the DNS search domain to use for nodes. If not set, this will be inherited from the host.
 	DNSSearch *[]string
+	// ContainerNetwork configures the container runtime network the nodes
+	// are attached to
+	ContainerNetwork ContainerNetwork
+	// MultiCluster makes the cluster aware of the other multi-cluster clusters
+	// sharing its container network, see v1alpha4.Networking.MultiCluster
+	MultiCluster bool
+}
+
+// ContainerNetwork configures the container runtime (docker, podman...)
+// network that nodes are attached to.
+type ContainerNetwork struct {
+	// Name is the name of the network
+	Name string
+	// IPv4Subnet is the CIDR used for node IPv4 addresses
+	IPv4Subnet string
+	// IPv4Gateway is the IPv4 gateway address within IPv4Subnet
+	IPv4Gateway string
+	// IPv6Subnet is the CIDR used for node IPv6 addresses
+	IPv6Subnet string
+	// IPv6Gateway is the IPv6 gateway address within IPv6Subnet
+	IPv6Gateway string
+	// MTU is the network MTU
+	MTU int32
 }
 
 // ClusterIPFamily defines cluster network IP family
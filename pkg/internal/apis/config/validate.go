@@ -32,6 +32,11 @@ import (
 // https://godoc.org/github.com/docker/docker/daemon/names#pkg-constants
 var validNameRE = regexp.MustCompile(`^[a-z0-9.-]+$`)
 
+// cluster labels and annotations are stored as container labels, and labels
+// must be usable in selectors, similar to Kubernetes labels
+var validMetadataKeyRE = regexp.MustCompile(`^[A-Za-z0-9]([-A-Za-z0-9_./]*[A-Za-z0-9])?$`)
+var validLabelValueRE = regexp.MustCompile(`^([A-Za-z0-9]([-A-Za-z0-9_.]*[A-Za-z0-9])?)?$`)
+
 // Validate returns a ConfigErrors with an entry for each problem
 // with the config, or nil if there are none
 func (c *Cluster) Validate() error {
@@ -73,6 +78,26 @@ func (c *Cluster) Validate() error {
 		errs = append(errs, errors.Errorf("invalid kubeProxyMode: %s", c.Networking.KubeProxyMode))
 	}
 
+	// containerNetwork settings are optional, but must be valid if set
+	if err := c.Networking.ContainerNetwork.Validate(); err != nil {
+		errs = append(errs, errors.Errorf("invalid containerNetwork: %v", err))
+	}
+
+	// validate cluster metadata
+	for key, value := range c.Labels {
+		if !validMetadataKeyRE.MatchString(key) {
+			errs = append(errs, errors.Errorf("invalid label key %q, keys must match `%s`", key, validMetadataKeyRE.String()))
+		}
+		if !validLabelValueRE.MatchString(value) {
+			errs = append(errs, errors.Errorf("invalid value %q for label %q, values must match `%s`", value, key, validLabelValueRE.String()))
+		}
+	}
+	for key := range c.Annotations {
+		if !validMetadataKeyRE.MatchString(key) {
+			errs = append(errs, errors.Errorf("invalid annotation key %q, keys must match `%s`", key, validMetadataKeyRE.String()))
+		}
+	}
+
 	// validate nodes
 	numByRole := make(map[NodeRole]int32)
 	// All nodes in the config should be valid
@@ -89,6 +114,11 @@ func (c *Cluster) Validate() error {
 		}
 	}
 
+	// static node addresses must not conflict
+	if err := validateNodeAddresses(c.Nodes, &c.Networking.ContainerNetwork); err != nil {
+		errs = append(errs, errors.Errorf("invalid node addresses: %v", err))
+	}
+
 	// there must be at least one control plane node
 	numControlPlane, anyControlPlane := numByRole[ControlPlaneRole]
 	if !anyControlPlane || numControlPlane < 1 {
@@ -134,6 +164,31 @@ func (n *Node) Validate() error {
 		errs = append(errs, errors.Wrapf(err, "invalid portMapping"))
 	}
 
+	// static addresses are optional, but must be of the right family
+	if err := validateNodeAddress(n.IPv4Address, false); err != nil {
+		errs = append(errs, errors.Wrap(err, "invalid ipv4Address"))
+	}
+	if err := validateNodeAddress(n.IPv6Address, true); err != nil {
+		errs = append(errs, errors.Wrap(err, "invalid ipv6Address"))
+	}
+
+	// validate extra networks
+	networkNames := sets.NewString()
+	for _, network := range n.ExtraNetworks {
+		if network.Name == "" {
+			errs = append(errs, errors.New("extraNetworks name is a required field"))
+		} else if networkNames.Has(network.Name) {
+			errs = append(errs, errors.Errorf("extraNetworks %q is listed more than once", network.Name))
+		}
+		networkNames.Insert(network.Name)
+		if err := validateNodeAddress(network.IPv4Address, false); err != nil {
+			errs = append(errs, errors.Wrapf(err, "invalid ipv4Address for extraNetworks %q", network.Name))
+		}
+		if err := validateNodeAddress(network.IPv6Address, true); err != nil {
+			errs = append(errs, errors.Wrapf(err, "invalid ipv6Address for extraNetworks %q", network.Name))
+		}
+	}
+
 	if len(errs) > 0 {
 		return errors.NewAggregate(errs)
 	}
@@ -141,6 +196,146 @@ func (n *Node) Validate() error {
 	return nil
 }
 
+// validateNodeAddress validates an optional static node address of the
+// given family
+func validateNodeAddress(address string, ipv6 bool) error {
+	if address == "" {
+		return nil
+	}
+	ip := net.ParseIP(address)
+	if ip == nil {
+		return errors.Errorf("failed to parse address %q", address)
+	}
+	if (ip.To4() == nil) != ipv6 {
+		return errors.Errorf("address %q is not of the expected IP family", address)
+	}
+	return nil
+}
+
+// validateNodeAddresses validates the static node addresses against each
+// other and against the configured container network subnets, if any.
+// Without a configured subnet, and for the dynamic address range of the
+// network, addresses are validated against the inspected network when
+// creating the cluster.
+func validateNodeAddresses(nodes []Node, network *ContainerNetwork) error {
+	errs := []error{}
+	seen := sets.NewString()
+	for i, n := range nodes {
+		for _, extra := range n.ExtraNetworks {
+			if network.Name != "" && extra.Name == network.Name {
+				errs = append(errs, errors.Errorf("node %d: extraNetworks %q is the cluster's container network", i, extra.Name))
+			}
+			// the subnets of extra networks are only known when creating
+			// the cluster, but addresses must at least be unique
+			for _, address := range []string{extra.IPv4Address, extra.IPv6Address} {
+				ip := net.ParseIP(address)
+				if ip == nil {
+					continue
+				}
+				key := extra.Name + "/" + ip.String()
+				if seen.Has(key) {
+					errs = append(errs, errors.Errorf("node %d: address %q on extraNetworks %q is used by another node", i, address, extra.Name))
+				}
+				seen.Insert(key)
+			}
+		}
+		for _, family := range []struct {
+			address string
+			subnet  string
+			gateway string
+		}{
+			{n.IPv4Address, network.IPv4Subnet, network.IPv4Gateway},
+			{n.IPv6Address, network.IPv6Subnet, network.IPv6Gateway},
+		} {
+			ip := net.ParseIP(family.address)
+			if ip == nil {
+				// either unset or already reported by Node.Validate
+				continue
+			}
+			if seen.Has(ip.String()) {
+				errs = append(errs, errors.Errorf("node %d: address %q is used by another node", i, family.address))
+			}
+			seen.Insert(ip.String())
+			if family.subnet == "" {
+				// validated against the inspected network when creating
+				continue
+			}
+			if gw := net.ParseIP(family.gateway); gw != nil && gw.Equal(ip) {
+				errs = append(errs, errors.Errorf("node %d: address %q is the network gateway", i, family.address))
+			}
+			_, cidr, err := net.ParseCIDR(family.subnet)
+			if err != nil {
+				// already reported by ContainerNetwork.Validate
+				continue
+			}
+			if !cidr.Contains(ip) {
+				errs = append(errs, errors.Errorf("node %d: address %q is not within subnet %q", i, family.address, family.subnet))
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return errors.NewAggregate(errs)
+	}
+	return nil
+}
+
+// Validate returns a ConfigErrors with an entry for each problem
+// with the ContainerNetwork, or nil if there are none
+func (n *ContainerNetwork) Validate() error {
+	errs := []error{}
+
+	if n.Name != "" && !validNameRE.MatchString(n.Name) {
+		errs = append(errs, errors.Errorf("'%s' is not a valid network name, network names must match `%s`",
+			n.Name, validNameRE.String()))
+	}
+
+	if err := validateNetworkSubnet(n.IPv4Subnet, n.IPv4Gateway, false); err != nil {
+		errs = append(errs, errors.Wrap(err, "invalid IPv4 settings"))
+	}
+	if err := validateNetworkSubnet(n.IPv6Subnet, n.IPv6Gateway, true); err != nil {
+		errs = append(errs, errors.Wrap(err, "invalid IPv6 settings"))
+	}
+
+	// 68 is the minimum IPv4 MTU, see RFC 791
+	if n.MTU != 0 && (n.MTU < 68 || n.MTU > 65535) {
+		errs = append(errs, errors.Errorf("invalid mtu: %d", n.MTU))
+	}
+
+	if len(errs) > 0 {
+		return errors.NewAggregate(errs)
+	}
+	return nil
+}
+
+// validateNetworkSubnet validates an optional subnet and gateway pair
+// of the given family
+func validateNetworkSubnet(subnet, gateway string, ipv6 bool) error {
+	if subnet == "" {
+		if gateway != "" {
+			return errors.New("gateway requires a subnet")
+		}
+		return nil
+	}
+	_, cidr, err := net.ParseCIDR(subnet)
+	if err != nil {
+		return errors.Errorf("failed to parse subnet %q: %v", subnet, err)
+	}
+	if (cidr.IP.To4() == nil) != ipv6 {
+		return errors.Errorf("subnet %q is not of the expected IP family", subnet)
+	}
+	if gateway == "" {
+		return nil
+	}
+	gw := net.ParseIP(gateway)
+	if gw == nil {
+		return errors.Errorf("failed to parse gateway %q", gateway)
+	}
+	if !cidr.Contains(gw) {
+		return errors.Errorf("gateway %q is not within subnet %q", gateway, subnet)
+	}
+	return nil
+}
+
 func validatePortMappings(portMappings []PortMapping) error {
 	errMsg := "port mapping with same listen address, port and protocol already configured"
 
@@ -97,6 +97,153 @@ func TestClusterValidate(t *testing.T) {
 			}(),
 			ExpectErrors: 1,
 		},
+		{
+			Name: "valid containerNetwork",
+			Cluster: func() Cluster {
+				c := Cluster{}
+				SetDefaultsCluster(&c)
+				c.Networking.ContainerNetwork = ContainerNetwork{
+					Name:        "kind-ci",
+					IPv4Subnet:  "172.30.0.0/16",
+					IPv4Gateway: "172.30.0.1",
+					IPv6Subnet:  "fc00:f853:ccd:e793::/64",
+					MTU:         1400,
+				}
+				return c
+			}(),
+		},
+		{
+			Name: "bogus containerNetwork",
+			Cluster: func() Cluster {
+				c := Cluster{}
+				SetDefaultsCluster(&c)
+				c.Networking.ContainerNetwork = ContainerNetwork{
+					IPv4Subnet:  "fc00:f853:ccd:e793::/64",
+					IPv6Gateway: "fc00::1",
+					MTU:         9,
+				}
+				return c
+			}(),
+			ExpectErrors: 1,
+		},
+		{
+			Name: "containerNetwork gateway outside subnet",
+			Cluster: func() Cluster {
+				c := Cluster{}
+				SetDefaultsCluster(&c)
+				c.Networking.ContainerNetwork = ContainerNetwork{
+					IPv4Subnet:  "172.30.0.0/16",
+					IPv4Gateway: "172.31.0.1",
+				}
+				return c
+			}(),
+			ExpectErrors: 1,
+		},
+		{
+			Name: "valid static node addresses",
+			Cluster: func() Cluster {
+				c := Cluster{}
+				SetDefaultsCluster(&c)
+				c.Networking.ContainerNetwork.IPv4Subnet = "172.30.0.0/16"
+				c.Networking.ContainerNetwork.IPv6Subnet = "fc00::/64"
+				c.Nodes = append(c.Nodes, newDefaultedNode(WorkerRole))
+				c.Nodes[0].IPv4Address = "172.30.0.10"
+				c.Nodes[1].IPv4Address = "172.30.0.11"
+				c.Nodes[1].IPv6Address = "fc00::11"
+				return c
+			}(),
+		},
+		{
+			Name: "static node address without a configured subnet",
+			Cluster: func() Cluster {
+				c := Cluster{}
+				SetDefaultsCluster(&c)
+				// validated against the inspected network when creating
+				c.Nodes[0].IPv4Address = "172.18.0.10"
+				return c
+			}(),
+		},
+		{
+			Name: "static node address outside of the configured subnet",
+			Cluster: func() Cluster {
+				c := Cluster{}
+				SetDefaultsCluster(&c)
+				c.Networking.ContainerNetwork.IPv4Subnet = "172.30.0.0/16"
+				c.Nodes[0].IPv4Address = "172.31.0.10"
+				return c
+			}(),
+			ExpectErrors: 1,
+		},
+		{
+			Name: "conflicting extra network addresses",
+			Cluster: func() Cluster {
+				c := Cluster{}
+				SetDefaultsCluster(&c)
+				c.Nodes = append(c.Nodes, newDefaultedNode(WorkerRole))
+				c.Nodes[0].ExtraNetworks = []NodeNetwork{{Name: "dmz", IPv4Address: "10.20.0.10"}}
+				c.Nodes[1].ExtraNetworks = []NodeNetwork{{Name: "dmz", IPv4Address: "10.20.0.10"}}
+				return c
+			}(),
+			ExpectErrors: 1,
+		},
+		{
+			Name: "conflicting static node addresses",
+			Cluster: func() Cluster {
+				c := Cluster{}
+				SetDefaultsCluster(&c)
+				c.Networking.ContainerNetwork.IPv4Subnet = "172.30.0.0/16"
+				c.Networking.ContainerNetwork.IPv4Gateway = "172.30.0.1"
+				c.Nodes = append(c.Nodes, newDefaultedNode(WorkerRole), newDefaultedNode(WorkerRole))
+				c.Nodes[0].IPv4Address = "172.30.0.10"
+				c.Nodes[1].IPv4Address = "172.30.0.10"
+				c.Nodes[2].IPv4Address = "172.30.0.1"
+				return c
+			}(),
+			ExpectErrors: 1,
+		},
+		{
+			Name: "extra network is the container network",
+			Cluster: func() Cluster {
+				c := Cluster{}
+				SetDefaultsCluster(&c)
+				c.Networking.ContainerNetwork.Name = "kind-ci"
+				c.Nodes[0].ExtraNetworks = []NodeNetwork{{Name: "kind-ci"}}
+				return c
+			}(),
+			ExpectErrors: 1,
+		},
+		{
+			Name: "static node address outside subnet",
+			Cluster: func() Cluster {
+				c := Cluster{}
+				SetDefaultsCluster(&c)
+				c.Networking.ContainerNetwork.IPv4Subnet = "172.30.0.0/16"
+				c.Nodes[0].IPv4Address = "10.0.0.10"
+				return c
+			}(),
+			ExpectErrors: 1,
+		},
+		{
+			Name: "valid cluster metadata",
+			Cluster: func() Cluster {
+				c := Cluster{}
+				SetDefaultsCluster(&c)
+				c.Labels = map[string]string{"owner": "ci", "example.com/job": "1234", "empty": ""}
+				c.Annotations = map[string]string{"purpose": "testing, with anything in the value"}
+				return c
+			}(),
+		},
+		{
+			Name: "invalid cluster metadata",
+			Cluster: func() Cluster {
+				c := Cluster{}
+				SetDefaultsCluster(&c)
+				c.Labels = map[string]string{"owner=": "ci", "purpose": "a,b"}
+				c.Annotations = map[string]string{"": "value"}
+				return c
+			}(),
+			ExpectErrors: 3,
+		},
 		{
 			Name: "bogus ipFamily",
 			Cluster: func() Cluster {
@@ -321,6 +468,51 @@ func TestNodeValidate(t *testing.T) {
 			}(),
 			ExpectErrors: 1,
 		},
+		{
+			TestName: "Static addresses",
+			Node: func() Node {
+				cfg := newDefaultedNode(ControlPlaneRole)
+				cfg.IPv4Address = "172.18.0.10"
+				cfg.IPv6Address = "fc00:f853:ccd:e793::10"
+				return cfg
+			}(),
+			ExpectErrors: 0,
+		},
+		{
+			TestName: "Static addresses of the wrong family",
+			Node: func() Node {
+				cfg := newDefaultedNode(ControlPlaneRole)
+				cfg.IPv4Address = "fc00:f853:ccd:e793::10"
+				cfg.IPv6Address = "not-an-ip"
+				return cfg
+			}(),
+			ExpectErrors: 2,
+		},
+		{
+			TestName: "Extra networks",
+			Node: func() Node {
+				cfg := newDefaultedNode(WorkerRole)
+				cfg.ExtraNetworks = []NodeNetwork{
+					{Name: "storage"},
+					{Name: "dmz", IPv4Address: "10.20.0.10", IPv6Address: "fd00:20::10"},
+				}
+				return cfg
+			}(),
+			ExpectErrors: 0,
+		},
+		{
+			TestName: "Invalid extra networks",
+			Node: func() Node {
+				cfg := newDefaultedNode(WorkerRole)
+				cfg.ExtraNetworks = []NodeNetwork{
+					{Name: "dmz"},
+					{Name: "dmz", IPv4Address: "fd00:20::10"},
+					{},
+				}
+				return cfg
+			}(),
+			ExpectErrors: 3,
+		},
 		{
 			TestName: "Empty role field",
 			Node: func() Node {
@@ -31,6 +31,20 @@ func (in *Cluster) DeepCopyInto(out *Cluster) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 	in.Networking.DeepCopyInto(&out.Networking)
 	if in.FeatureGates != nil {
 		in, out := &in.FeatureGates, &out.FeatureGates
@@ -79,6 +93,22 @@ func (in *Cluster) DeepCopy() *Cluster {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContainerNetwork) DeepCopyInto(out *ContainerNetwork) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerNetwork.
+func (in *ContainerNetwork) DeepCopy() *ContainerNetwork {
+	if in == nil {
+		return nil
+	}
+	out := new(ContainerNetwork)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Mount) DeepCopyInto(out *Mount) {
 	*out = *in
@@ -107,6 +137,7 @@ func (in *Networking) DeepCopyInto(out *Networking) {
 			copy(*out, *in)
 		}
 	}
+	out.ContainerNetwork = in.ContainerNetwork
 	return
 }
 
@@ -130,6 +161,11 @@ func (in *Node) DeepCopyInto(out *Node) {
 			(*out)[key] = val
 		}
 	}
+	if in.ExtraNetworks != nil {
+		in, out := &in.ExtraNetworks, &out.ExtraNetworks
+		*out = make([]NodeNetwork, len(*in))
+		copy(*out, *in)
+	}
 	if in.ExtraMounts != nil {
 		in, out := &in.ExtraMounts, &out.ExtraMounts
 		*out = make([]Mount, len(*in))
@@ -163,6 +199,22 @@ func (in *Node) DeepCopy() *Node {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeNetwork) DeepCopyInto(out *NodeNetwork) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeNetwork.
+func (in *NodeNetwork) DeepCopy() *NodeNetwork {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeNetwork)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PatchJSON6902) DeepCopyInto(out *PatchJSON6902) {
 	*out = *in
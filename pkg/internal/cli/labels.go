@@ -0,0 +1,117 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"strings"
+
+	"sigs.k8s.io/kind/pkg/errors"
+)
+
+// ParseKeyValues parses repeated key=value flag values into a map,
+// flagName is used in errors
+func ParseKeyValues(flagName string, values []string) (map[string]string, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+	parsed := make(map[string]string, len(values))
+	for _, value := range values {
+		parts := strings.SplitN(value, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, errors.Errorf("invalid --%s %q, expected key=value", flagName, value)
+		}
+		parsed[parts[0]] = parts[1]
+	}
+	return parsed, nil
+}
+
+// Selector is a parsed label selector, see ParseSelector
+type Selector []requirement
+
+type requirement struct {
+	key   string
+	value string
+	// op is one of "=", "!=", "exists" or "!exists"
+	op string
+}
+
+// ParseSelector parses a comma separated list of label requirements,
+// each one of `key=value`, `key==value`, `key!=value`, `key` or `!key`.
+// The empty selector matches everything.
+func ParseSelector(s string) (Selector, error) {
+	selector := Selector{}
+	for _, term := range strings.Split(s, ",") {
+		term = strings.TrimSpace(term)
+		if term == "" {
+			continue
+		}
+		var r requirement
+		switch {
+		case strings.Contains(term, "!="):
+			parts := strings.SplitN(term, "!=", 2)
+			r = requirement{key: parts[0], value: parts[1], op: "!="}
+		case strings.Contains(term, "=="):
+			parts := strings.SplitN(term, "==", 2)
+			r = requirement{key: parts[0], value: parts[1], op: "="}
+		case strings.Contains(term, "="):
+			parts := strings.SplitN(term, "=", 2)
+			r = requirement{key: parts[0], value: parts[1], op: "="}
+		case strings.HasPrefix(term, "!"):
+			r = requirement{key: strings.TrimPrefix(term, "!"), op: "!exists"}
+		default:
+			r = requirement{key: term, op: "exists"}
+		}
+		r.key = strings.TrimSpace(r.key)
+		r.value = strings.TrimSpace(r.value)
+		if r.key == "" {
+			return nil, errors.Errorf("invalid selector requirement %q", term)
+		}
+		selector = append(selector, r)
+	}
+	return selector, nil
+}
+
+// Matches returns true if labels satisfy all of the selector's requirements
+func (s Selector) Matches(labels map[string]string) bool {
+	for _, r := range s {
+		value, exists := labels[r.key]
+		switch r.op {
+		case "=":
+			if !exists || value != r.value {
+				return false
+			}
+		case "!=":
+			if exists && value == r.value {
+				return false
+			}
+		case "exists":
+			if !exists {
+				return false
+			}
+		case "!exists":
+			if exists {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Empty returns true if the selector has no requirements
+func (s Selector) Empty() bool {
+	return len(s) == 0
+}
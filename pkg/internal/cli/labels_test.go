@@ -0,0 +1,96 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"testing"
+
+	"sigs.k8s.io/kind/pkg/internal/assert"
+)
+
+func TestParseKeyValues(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name        string
+		values      []string
+		expected    map[string]string
+		expectError bool
+	}{
+		{
+			name: "no values",
+		},
+		{
+			name:     "values",
+			values:   []string{"owner=ci", "job=a=b", "empty="},
+			expected: map[string]string{"owner": "ci", "job": "a=b", "empty": ""},
+		},
+		{
+			name:        "missing value",
+			values:      []string{"owner"},
+			expectError: true,
+		},
+		{
+			name:        "missing key",
+			values:      []string{"=ci"},
+			expectError: true,
+		},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			parsed, err := ParseKeyValues("label", tc.values)
+			assert.ExpectError(t, tc.expectError, err)
+			assert.DeepEqual(t, tc.expected, parsed)
+		})
+	}
+}
+
+func TestSelector(t *testing.T) {
+	t.Parallel()
+	labels := map[string]string{"owner": "ci", "purpose": "e2e"}
+	cases := []struct {
+		name        string
+		selector    string
+		matches     bool
+		expectError bool
+	}{
+		{name: "empty", selector: "", matches: true},
+		{name: "equals", selector: "owner=ci", matches: true},
+		{name: "double equals", selector: "owner==ci", matches: true},
+		{name: "equals mismatch", selector: "owner=dev", matches: false},
+		{name: "not equals", selector: "owner!=dev", matches: true},
+		{name: "not equals mismatch", selector: "owner!=ci", matches: false},
+		{name: "not equals missing key", selector: "team!=a", matches: true},
+		{name: "exists", selector: "purpose", matches: true},
+		{name: "not exists", selector: "!purpose", matches: false},
+		{name: "multiple", selector: "owner=ci, purpose=e2e,!team", matches: true},
+		{name: "multiple mismatch", selector: "owner=ci,purpose=dev", matches: false},
+		{name: "missing key", selector: "=ci", expectError: true},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			selector, err := ParseSelector(tc.selector)
+			assert.ExpectError(t, tc.expectError, err)
+			if err == nil {
+				assert.BoolEqual(t, tc.matches, selector.Matches(labels))
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"bytes"
+	"io"
+	"sync"
+)
+
+// PrefixWriter is an io.Writer that prefixes each line written to it,
+// for interleaving the output of multiple concurrent commands, E.G. one
+// per node. Multiple PrefixWriters may share the same underlying writer
+// and lock to avoid interleaving within lines.
+type PrefixWriter struct {
+	w      io.Writer
+	mu     *sync.Mutex
+	prefix []byte
+	// buffer holds any incomplete line
+	buffer []byte
+}
+
+// NewPrefixWriter returns a PrefixWriter writing to w, holding mu while
+// writing complete lines
+func NewPrefixWriter(w io.Writer, mu *sync.Mutex, prefix string) *PrefixWriter {
+	return &PrefixWriter{
+		w:      w,
+		mu:     mu,
+		prefix: []byte(prefix),
+	}
+}
+
+// Write implements io.Writer, complete lines are written immediately
+func (p *PrefixWriter) Write(b []byte) (int, error) {
+	p.buffer = append(p.buffer, b...)
+	i := bytes.LastIndexByte(p.buffer, '\n')
+	if i < 0 {
+		return len(b), nil
+	}
+	lines := p.buffer[:i+1]
+	if err := p.write(lines); err != nil {
+		return 0, err
+	}
+	p.buffer = append(p.buffer[:0], p.buffer[i+1:]...)
+	return len(b), nil
+}
+
+// Flush writes any incomplete final line, terminating it
+func (p *PrefixWriter) Flush() error {
+	if len(p.buffer) == 0 {
+		return nil
+	}
+	err := p.write(append(p.buffer, '\n'))
+	p.buffer = p.buffer[:0]
+	return err
+}
+
+func (p *PrefixWriter) write(lines []byte) error {
+	var out bytes.Buffer
+	for len(lines) > 0 {
+		i := bytes.IndexByte(lines, '\n')
+		out.Write(p.prefix)
+		out.Write(lines[:i+1])
+		lines = lines[i+1:]
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	_, err := p.w.Write(out.Bytes())
+	return err
+}
@@ -0,0 +1,43 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+
+	"sigs.k8s.io/kind/pkg/internal/assert"
+)
+
+func TestPrefixWriter(t *testing.T) {
+	t.Parallel()
+	var out bytes.Buffer
+	var mu sync.Mutex
+	a := NewPrefixWriter(&out, &mu, "[a] ")
+	b := NewPrefixWriter(&out, &mu, "[b] ")
+	_, _ = a.Write([]byte("one\ntw"))
+	_, _ = b.Write([]byte("three\n"))
+	_, _ = a.Write([]byte("o\nfour"))
+	if err := a.Flush(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := b.Flush(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.StringEqual(t, "[a] one\n[b] three\n[a] two\n[a] four\n", out.String())
+}
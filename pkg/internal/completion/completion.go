@@ -0,0 +1,165 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package completion implements dynamic shell completion of kind clusters,
+// nodes and images for cobra ValidArgsFunction and RegisterFlagCompletionFunc
+// hooks, using the provider selected by KIND_EXPERIMENTAL_PROVIDER
+package completion
+
+import (
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/kind/pkg/cluster"
+	"sigs.k8s.io/kind/pkg/log"
+
+	"sigs.k8s.io/kind/pkg/internal/cli"
+	"sigs.k8s.io/kind/pkg/internal/runtime"
+)
+
+// Func is a cobra completion function
+type Func func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective)
+
+// nodeImageReference matches the node images in local image stores
+const nodeImageReference = "kindest/node"
+
+// RegisterFlag registers f to complete the flag name of cmd
+func RegisterFlag(cmd *cobra.Command, name string, f Func) {
+	// this only fails if the flag does not exist or is already registered
+	_ = cmd.RegisterFlagCompletionFunc(name, f)
+}
+
+// ClusterNames completes cluster names, E.G. for --name
+func ClusterNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	names, err := provider().List()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
+
+// ClusterNameArgs completes up to max cluster name arguments, or any number
+// if max is negative, excluding clusters already given
+func ClusterNameArgs(max int) Func {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if max >= 0 && len(args) >= max {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		names, directive := ClusterNames(cmd, args, toComplete)
+		return without(names, args), directive
+	}
+}
+
+// NodeNames completes the node names of the cluster selected by --name
+func NodeNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	names, err := nodeNames(cmd)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
+
+// NodeNameList completes a comma separated list of the node names of the
+// cluster selected by --name, E.G. for --nodes
+func NodeNameList(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	names, err := nodeNames(cmd)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	return list(names, toComplete)
+}
+
+// NodeImages completes the node images in the local image store
+func NodeImages(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	images, err := provider().ListImages(nodeImageReference)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	return images, cobra.ShellCompDirectiveNoFileComp
+}
+
+// Values completes one of values
+func Values(values ...string) Func {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return values, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// ValueList completes a comma separated list of values
+func ValueList(values ...string) Func {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return list(values, toComplete)
+	}
+}
+
+// provider returns the provider selected by KIND_EXPERIMENTAL_PROVIDER,
+// without logging, as stderr is shown while completing in some shells
+func provider() *cluster.Provider {
+	logger := log.NoopLogger{}
+	return cluster.NewProvider(
+		cluster.ProviderWithLogger(logger),
+		runtime.GetDefault(logger),
+	)
+}
+
+func nodeNames(cmd *cobra.Command) ([]string, error) {
+	name := cluster.DefaultName
+	if flag := cmd.Flags().Lookup("name"); flag != nil {
+		cli.OverrideDefaultName(cmd.Flags())
+		name = flag.Value.String()
+	}
+	n, err := provider().ListNodes(name)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, len(n))
+	for i := range n {
+		names[i] = n[i].String()
+	}
+	return names, nil
+}
+
+// list completes the last element of the comma separated list toComplete
+// with values not already in the list
+func list(values []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	i := strings.LastIndex(toComplete, ",")
+	if i < 0 {
+		return values, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace
+	}
+	prefix := toComplete[:i+1]
+	remaining := without(values, strings.Split(toComplete[:i], ","))
+	completions := make([]string, len(remaining))
+	for j := range remaining {
+		completions[j] = prefix + remaining[j]
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace
+}
+
+// without returns values that are not in exclude
+func without(values, exclude []string) []string {
+	excluded := map[string]bool{}
+	for _, e := range exclude {
+		excluded[e] = true
+	}
+	out := []string{}
+	for _, v := range values {
+		if !excluded[v] {
+			out = append(out, v)
+		}
+	}
+	return out
+}
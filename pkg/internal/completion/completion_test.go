@@ -0,0 +1,76 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package completion
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/kind/pkg/internal/assert"
+)
+
+func TestList(t *testing.T) {
+	t.Parallel()
+	values := []string{"kind-control-plane", "kind-worker", "kind-worker2"}
+	cases := []struct {
+		Name       string
+		ToComplete string
+		Expected   []string
+	}{
+		{
+			Name:     "first element",
+			Expected: values,
+		},
+		{
+			Name:       "first element prefix",
+			ToComplete: "kind-w",
+			Expected:   values,
+		},
+		{
+			Name:       "second element",
+			ToComplete: "kind-worker,",
+			Expected:   []string{"kind-worker,kind-control-plane", "kind-worker,kind-worker2"},
+		},
+		{
+			Name:       "third element prefix",
+			ToComplete: "kind-worker,kind-worker2,kind-c",
+			Expected:   []string{"kind-worker,kind-worker2,kind-control-plane"},
+		},
+		{
+			Name:       "all given",
+			ToComplete: "kind-worker,kind-worker2,kind-control-plane,",
+			Expected:   []string{},
+		},
+	}
+	for _, tc := range cases {
+		tc := tc // capture range variable
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+			completions, directive := list(values, tc.ToComplete)
+			assert.DeepEqual(t, tc.Expected, completions)
+			assert.BoolEqual(t, true, directive&cobra.ShellCompDirectiveNoSpace != 0)
+		})
+	}
+}
+
+func TestValues(t *testing.T) {
+	t.Parallel()
+	completions, directive := Values("url", "file")(nil, nil, "")
+	assert.DeepEqual(t, []string{"url", "file"}, completions)
+	assert.BoolEqual(t, true, directive == cobra.ShellCompDirectiveNoFileComp)
+}
@@ -0,0 +1,175 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package imageload
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"strings"
+
+	"sigs.k8s.io/kind/pkg/errors"
+)
+
+// PlatformArchive is an image archive written by docker save, holding an
+// image for Platform
+type PlatformArchive struct {
+	Platform Platform
+	Path     string
+}
+
+// platformImage is an image manifest and the store with its content
+type platformImage struct {
+	image *image
+	store blobStore
+}
+
+// WriteIndexArchive writes an OCI image layout archive to w with an image
+// index named name of the images in archives
+func WriteIndexArchive(w io.Writer, name string, archives []PlatformArchive) error {
+	images, err := loadPlatformImages(archives)
+	if err != nil {
+		return err
+	}
+	root, raw, err := buildIndex(images)
+	if err != nil {
+		return err
+	}
+	return writeLayout(w, SanitizeImage(name), root, raw, images)
+}
+
+// PushIndex pushes the images in archives to the registry as an image index
+// tagged as name, E.G. localhost:5001/kindest/node:latest, see CheckPushTarget
+func PushIndex(name string, archives []PlatformArchive) error {
+	if err := CheckPushTarget(name); err != nil {
+		return err
+	}
+	registry := newRegistryStore(name, "pull,push")
+	images, err := loadPlatformImages(archives)
+	if err != nil {
+		return err
+	}
+	root, raw, err := buildIndex(images)
+	if err != nil {
+		return err
+	}
+	for _, img := range images {
+		missing := []descriptor{}
+		for _, desc := range img.image.blobs() {
+			exists, err := registry.blobExists(desc.Digest)
+			if err != nil {
+				return err
+			}
+			if !exists {
+				missing = append(missing, desc)
+			}
+		}
+		err := img.store.walk(missing, func(desc descriptor, r io.Reader) error {
+			return registry.pushBlob(desc, r)
+		})
+		if err != nil {
+			return err
+		}
+		manifest := img.image.manifest
+		if err := registry.pushManifest(manifest.Digest, manifest.MediaType, img.image.raw); err != nil {
+			return err
+		}
+	}
+	return registry.pushManifest(registry.reference, root.MediaType, raw)
+}
+
+// CheckPushTarget returns an error unless PushIndex can push to name.
+// Pushes are anonymous, without credentials, so only registries on a
+// loopback address are supported, E.G. localhost:5001/kindest/node:latest.
+func CheckPushTarget(name string) error {
+	host, _, reference := parseReference(name)
+	if !isLoopback(host) {
+		return errors.Errorf("cannot push image index %q, only registries on a loopback address such as localhost:5001 are supported", name)
+	}
+	if strings.ContainsRune(reference, ':') {
+		return errors.Errorf("cannot push image index to digest reference %q", name)
+	}
+	return nil
+}
+
+// loadPlatformImages reads the image manifest for its platform from each
+// of archives, which must be in the OCI image layout format
+func loadPlatformImages(archives []PlatformArchive) ([]platformImage, error) {
+	images := make([]platformImage, 0, len(archives))
+	for _, archive := range archives {
+		store := &archiveStore{path: archive.Path}
+		names, err := store.names()
+		if err != nil {
+			return nil, err
+		}
+		if !names["index.json"] {
+			return nil, errors.Errorf("%q is not an OCI image layout archive, docker 25 or newer is required", archive.Path)
+		}
+		raw, err := store.readFile("index.json")
+		if err != nil {
+			return nil, err
+		}
+		idx, err := parseIndex(raw)
+		if err != nil {
+			return nil, err
+		}
+		if len(idx.Manifests) != 1 {
+			return nil, errors.Errorf("expected a single image in %q, found %d", archive.Path, len(idx.Manifests))
+		}
+		img, err := resolve(store, idx.Manifests[0], archive.Platform)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to resolve image in %q", archive.Path)
+		}
+		config, err := fetchVerified(store, img.config)
+		if err != nil {
+			return nil, err
+		}
+		platform, err := configPlatform(config)
+		if err != nil {
+			return nil, err
+		}
+		if !archive.Platform.Matches(platform) {
+			return nil, errors.Errorf("image in %q is for platform %s, expected %s", archive.Path, platform, archive.Platform)
+		}
+		img.manifest.Platform = &platform
+		images = append(images, platformImage{image: img, store: store})
+	}
+	return images, nil
+}
+
+// buildIndex returns the descriptor and content of an image index of images
+func buildIndex(images []platformImage) (descriptor, []byte, error) {
+	manifests := make([]descriptor, 0, len(images))
+	for _, img := range images {
+		manifests = append(manifests, img.image.manifest)
+	}
+	raw, err := json.Marshal(&index{
+		SchemaVersion: 2,
+		MediaType:     mediaTypeOCIIndex,
+		Manifests:     manifests,
+	})
+	if err != nil {
+		return descriptor{}, nil, err
+	}
+	sum := sha256.Sum256(raw)
+	return descriptor{
+		MediaType: mediaTypeOCIIndex,
+		Digest:    "sha256:" + hex.EncodeToString(sum[:]),
+		Size:      int64(len(raw)),
+	}, raw, nil
+}
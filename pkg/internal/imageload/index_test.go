@@ -0,0 +1,212 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package imageload
+
+import (
+	"archive/tar"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"sigs.k8s.io/kind/pkg/internal/assert"
+)
+
+// platformArchives writes an archive per platform of ti, as written by
+// docker save
+func platformArchives(t *testing.T, ti *testImage) []PlatformArchive {
+	t.Helper()
+	idx, err := parseIndex(ti.blobs[ti.index.Digest])
+	if err != nil {
+		t.Fatal(err)
+	}
+	archives := []PlatformArchive{}
+	for _, m := range idx.Manifests {
+		platform := *m.Platform
+		m.Platform = nil
+		var buf bytes.Buffer
+		tw := tar.NewWriter(&buf)
+		files := map[string][]byte{
+			"oci-layout": []byte(`{"imageLayoutVersion":"1.0.0"}`),
+			"index.json": mustMarshal(t, &index{SchemaVersion: 2, Manifests: []descriptor{m}}),
+		}
+		for digest, content := range ti.blobs {
+			p, err := blobPath(digest)
+			if err != nil {
+				t.Fatal(err)
+			}
+			files[p] = content
+		}
+		for p, content := range files {
+			if err := writeTarFile(tw, p, content); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := tw.Close(); err != nil {
+			t.Fatal(err)
+		}
+		p := filepath.Join(t.TempDir(), platform.Architecture+".tar")
+		if err := os.WriteFile(p, buf.Bytes(), 0644); err != nil {
+			t.Fatal(err)
+		}
+		archives = append(archives, PlatformArchive{Platform: platform, Path: p})
+	}
+	return archives
+}
+
+func TestWriteIndexArchive(t *testing.T) {
+	t.Parallel()
+	ti := newTestImage(t)
+	archives := platformArchives(t, ti)
+	p := filepath.Join(t.TempDir(), "index.tar")
+	f, err := os.Create(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.ExpectError(t, false, WriteIndexArchive(f, "kindest/node:test", archives))
+	assert.ExpectError(t, false, f.Close())
+
+	src, err := Parse(OCIArchivePrefix+p, nil, nativePlatform)
+	assert.ExpectError(t, false, err)
+	checkSource(t, src, ti, "docker.io/kindest/node:test")
+	assert.ExpectError(t, false, CheckArchivePlatform(p, archives[0].Platform))
+	assert.ExpectError(t, false, CheckArchivePlatform(p, archives[1].Platform))
+}
+
+func TestWriteIndexArchiveWrongPlatform(t *testing.T) {
+	t.Parallel()
+	ti := newTestImage(t)
+	archives := platformArchives(t, ti)
+	archives[0].Platform, archives[1].Platform = archives[1].Platform, archives[0].Platform
+	err := WriteIndexArchive(io.Discard, "kindest/node:test", archives)
+	assert.ExpectError(t, true, err)
+}
+
+func TestPushIndex(t *testing.T) {
+	t.Parallel()
+	ti := newTestImage(t)
+	var mu sync.Mutex
+	blobs := map[string][]byte{}
+	manifests := map[string][]byte{}
+	mediaTypes := map[string]string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		const prefix = "/v2/team/app/"
+		if !strings.HasPrefix(r.URL.Path, prefix) {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		parts := strings.SplitN(strings.TrimPrefix(r.URL.Path, prefix), "/", 2)
+		switch {
+		case r.Method == http.MethodPost && parts[1] == "uploads/":
+			w.Header().Set("Location", prefix+"blobs/uploads/upload?state=1")
+			w.WriteHeader(http.StatusAccepted)
+		case r.Method == http.MethodPut && parts[0] == "blobs":
+			content, _ := io.ReadAll(r.Body)
+			sum := sha256.Sum256(content)
+			digest := r.URL.Query().Get("digest")
+			if r.URL.Query().Get("state") != "1" || digest != "sha256:"+hex.EncodeToString(sum[:]) {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			blobs[digest] = content
+			w.WriteHeader(http.StatusCreated)
+		case r.Method == http.MethodPut && parts[0] == "manifests":
+			content, _ := io.ReadAll(r.Body)
+			sum := sha256.Sum256(content)
+			digest := "sha256:" + hex.EncodeToString(sum[:])
+			manifests[parts[1]] = content
+			manifests[digest] = content
+			mediaTypes[parts[1]] = r.Header.Get("Content-Type")
+			w.WriteHeader(http.StatusCreated)
+		case parts[0] == "blobs":
+			content, ok := blobs[parts[1]]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_, _ = w.Write(content)
+		case r.Method == http.MethodGet && parts[0] == "manifests":
+			content, ok := manifests[parts[1]]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Header().Set("Content-Type", mediaTypes[parts[1]])
+			_, _ = w.Write(content)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	}))
+	defer server.Close()
+
+	host := strings.TrimPrefix(server.URL, "http://")
+	archives := platformArchives(t, ti)
+	assert.ExpectError(t, false, PushIndex(host+"/team/app:1.0", archives))
+
+	mu.Lock()
+	assert.StringEqual(t, mediaTypeOCIIndex, mediaTypes["1.0"])
+	idx := &index{}
+	if err := json.Unmarshal(manifests["1.0"], idx); err != nil {
+		t.Fatal(err)
+	}
+	mu.Unlock()
+	if len(idx.Manifests) != 2 {
+		t.Fatalf("expected two manifests in the index, got %d", len(idx.Manifests))
+	}
+	for i, archive := range archives {
+		assert.DeepEqual(t, &archive.Platform, idx.Manifests[i].Platform)
+	}
+
+	src, err := Parse(RegistryPrefix+host+"/team/app:1.0", nil, nativePlatform)
+	assert.ExpectError(t, false, err)
+	checkSource(t, src, ti, host+"/team/app:1.0")
+
+	assert.ExpectError(t, true, PushIndex(host+"/team/app@"+ti.index.Digest, archives))
+}
+
+func TestCheckPushTarget(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		Name        string
+		Image       string
+		ExpectError bool
+	}{
+		{Name: "localhost", Image: "localhost:5001/kindest/node:latest"},
+		{Name: "ipv4 loopback", Image: "127.0.0.1:5001/kindest/node:latest"},
+		{Name: "ipv6 loopback", Image: "[::1]:5001/kindest/node:latest"},
+		{Name: "docker hub", Image: "kindest/node:latest", ExpectError: true},
+		{Name: "remote registry", Image: "registry.example.com/kindest/node:latest", ExpectError: true},
+		{Name: "digest", Image: "localhost:5001/kindest/node@sha256:" + strings.Repeat("0", 64), ExpectError: true},
+	}
+	for _, tc := range cases {
+		tc := tc // capture range variable
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+			assert.ExpectError(t, tc.ExpectError, CheckPushTarget(tc.Image))
+		})
+	}
+}
@@ -0,0 +1,76 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package imageload implements loading images from different sources into
+// cluster nodes
+package imageload
+
+import (
+	"strings"
+
+	"sigs.k8s.io/kind/pkg/cluster/nodes"
+)
+
+// TagFetcher returns the set of tags of imageID on a node
+type TagFetcher func(nodes.Node, string) (map[string]bool, error)
+
+// CheckIfImageReTagRequired makes sure we only perform the reverse lookup of the ImageID to tag map
+func CheckIfImageReTagRequired(node nodes.Node, imageID, imageName string, tagFetcher TagFetcher) (exists, reTagRequired bool, sanitizedImage string) {
+	tags, err := tagFetcher(node, imageID)
+	if len(tags) == 0 || err != nil {
+		exists = false
+		return
+	}
+	exists = true
+	sanitizedImage = SanitizeImage(imageName)
+	if ok := tags[sanitizedImage]; ok {
+		reTagRequired = false
+		return
+	}
+	reTagRequired = true
+	return
+}
+
+// SanitizeImage is a helper to return human readable image name
+// This is a modified version of the same function found under providers/podman/images.go
+func SanitizeImage(image string) (sanitizedName string) {
+	const (
+		defaultDomain    = "docker.io/"
+		officialRepoName = "library"
+	)
+	sanitizedName = image
+
+	if !strings.ContainsRune(image, '/') {
+		sanitizedName = officialRepoName + "/" + image
+	}
+
+	i := strings.IndexRune(sanitizedName, '/')
+	if i == -1 || (!strings.ContainsAny(sanitizedName[:i], ".:") && sanitizedName[:i] != "localhost") {
+		sanitizedName = defaultDomain + sanitizedName
+	}
+
+	if !hasTag(sanitizedName) && !strings.ContainsRune(sanitizedName, '@') {
+		sanitizedName += ":latest"
+	}
+
+	return
+}
+
+func hasTag(image string) bool {
+	lastSlash := strings.LastIndex(image, "/")
+	lastColon := strings.LastIndex(image, ":")
+	return lastColon > lastSlash
+}
@@ -0,0 +1,101 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package imageload
+
+import (
+	"fmt"
+
+	"sigs.k8s.io/kind/pkg/cluster/nodes"
+	"sigs.k8s.io/kind/pkg/cluster/nodeutils"
+	"sigs.k8s.io/kind/pkg/log"
+)
+
+// SelectNodes returns the nodes in nodeList named in names, or all of
+// nodeList if names is empty
+func SelectNodes(nodeList []nodes.Node, names []string) ([]nodes.Node, error) {
+	if len(names) == 0 {
+		return nodeList, nil
+	}
+	// map cluster nodes by their name
+	nodesByName := map[string]nodes.Node{}
+	for _, node := range nodeList {
+		// TODO(bentheelder): this depends on the fact that ListByCluster()
+		// will have name for nameOrId.
+		nodesByName[node.String()] = node
+	}
+	selected := []nodes.Node{}
+	for _, name := range names {
+		node, ok := nodesByName[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown node: %q", name)
+		}
+		selected = append(selected, node)
+	}
+	return selected, nil
+}
+
+// NodesNeedingImage returns the candidates that do not have imageName with
+// imageID yet, re-tagging the image on nodes that have the ID under
+// another name instead where possible
+func NodesNeedingImage(logger log.Logger, candidates []nodes.Node, imageID, imageName string) []nodes.Node {
+	selected := []nodes.Node{}
+	processed := false
+	for _, node := range candidates {
+		exists, reTagRequired, sanitizedImageName := CheckIfImageReTagRequired(node, imageID, imageName, nodeutils.ImageTags)
+		if exists && !reTagRequired {
+			continue
+		}
+
+		if reTagRequired {
+			// We will try to re-tag the image. If the re-tag fails, we will fall back to the default behavior of loading
+			// the images into the nodes again
+			logger.V(0).Infof("Image with ID: %s already present on the node %s but is missing the tag %s. re-tagging...", imageID, node.String(), sanitizedImageName)
+			if err := nodeutils.ReTagImage(node, imageID, sanitizedImageName); err != nil {
+				logger.Errorf("failed to re-tag image on the node %s due to an error %s. Will load it instead...", node.String(), err)
+				selected = append(selected, node)
+			} else {
+				processed = true
+			}
+			continue
+		}
+		id, err := nodeutils.ImageID(node, imageName)
+		if err != nil || id != imageID {
+			selected = append(selected, node)
+			logger.V(0).Infof("Image: %q with ID %q not yet present on node %q, loading...", imageName, imageID, node.String())
+		}
+	}
+	if len(selected) == 0 && !processed {
+		logger.V(0).Infof("Image: %q with ID %q found to be already present on all nodes.", imageName, imageID)
+	}
+	return selected
+}
+
+// Load loads the image from src into the candidates that don't have it yet,
+// streaming it to all of them at once
+func Load(logger log.Logger, src Source, candidates []nodes.Node) error {
+	imageID, err := src.ID()
+	if err != nil {
+		return err
+	}
+	selected := NodesNeedingImage(logger, candidates, imageID, src.Name())
+	if len(selected) == 0 {
+		return nil
+	}
+
+	// the archive only contains the platform the source resolved
+	return LoadArchive(logger, selected, "", src.WriteArchive)
+}
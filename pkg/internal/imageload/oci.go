@@ -0,0 +1,606 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package imageload
+
+import (
+	"archive/tar"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
+	"hash"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"sigs.k8s.io/kind/pkg/errors"
+)
+
+// OCI and docker distribution media types of manifests and indexes
+const (
+	mediaTypeOCIIndex           = "application/vnd.oci.image.index.v1+json"
+	mediaTypeOCIManifest        = "application/vnd.oci.image.manifest.v1+json"
+	mediaTypeDockerManifestList = "application/vnd.docker.distribution.manifest.list.v2+json"
+	mediaTypeDockerManifest     = "application/vnd.docker.distribution.manifest.v2+json"
+)
+
+const (
+	// annotationImageName is the image name containerd imports an
+	// index entry as
+	annotationImageName = "io.containerd.image.name"
+	// annotationRefName is the OCI image layout reference name, often
+	// only a tag
+	annotationRefName = "org.opencontainers.image.ref.name"
+)
+
+// maxFetchSize limits the size of manifests and image configs read into
+// memory
+const maxFetchSize = 8 << 20
+
+// maxIndexDepth limits the nesting of indexes when resolving an image
+const maxIndexDepth = 4
+
+// descriptor is an OCI content descriptor
+type descriptor struct {
+	MediaType   string            `json:"mediaType,omitempty"`
+	Digest      string            `json:"digest"`
+	Size        int64             `json:"size"`
+	Platform    *Platform         `json:"platform,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// index is an OCI image index, as in the index.json of an image layout
+type index struct {
+	SchemaVersion int          `json:"schemaVersion"`
+	MediaType     string       `json:"mediaType,omitempty"`
+	Manifests     []descriptor `json:"manifests"`
+}
+
+// image is an image manifest for a single platform
+type image struct {
+	manifest descriptor
+	raw      []byte
+	config   descriptor
+	layers   []descriptor
+}
+
+// blobs returns the config and unique layers of the image
+func (i *image) blobs() []descriptor {
+	blobs := []descriptor{i.config}
+	seen := map[string]bool{i.config.Digest: true}
+	for _, layer := range i.layers {
+		if !seen[layer.Digest] {
+			seen[layer.Digest] = true
+			blobs = append(blobs, layer)
+		}
+	}
+	return blobs
+}
+
+// blobStore is content addressable image storage, E.G. an image layout
+type blobStore interface {
+	// root returns the descriptor of image and its sanitized name, image
+	// may be empty if the store can name the image itself
+	root(image string) (descriptor, string, error)
+	// fetch returns the content of a small blob, E.G. a manifest
+	fetch(desc descriptor) ([]byte, error)
+	// walk calls fn with the content of each of descs, in any order
+	walk(descs []descriptor, fn func(descriptor, io.Reader) error) error
+}
+
+// ociSource is an image in a blobStore
+type ociSource struct {
+	ref      string
+	name     string
+	root     descriptor
+	store    blobStore
+	platform Platform
+	image    *image
+}
+
+var _ Source = &ociSource{}
+
+func newOCISource(ref, image string, store blobStore, platform Platform) (Source, error) {
+	root, name, err := store.root(image)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read image %q", ref)
+	}
+	return &ociSource{
+		ref:      ref,
+		name:     name,
+		root:     root,
+		store:    store,
+		platform: platform,
+	}, nil
+}
+
+func (s *ociSource) Name() string {
+	return s.name
+}
+
+// ID returns the image config digest, which is the image ID
+func (s *ociSource) ID() (string, error) {
+	img, err := s.resolve()
+	if err != nil {
+		return "", err
+	}
+	return img.config.Digest, nil
+}
+
+// WriteArchive writes an OCI image layout archive of the image for the
+// source's platform, annotated with the image name for containerd
+func (s *ociSource) WriteArchive(w io.Writer) error {
+	img, err := s.resolve()
+	if err != nil {
+		return err
+	}
+	return writeArchive(w, s.name, img, s.store)
+}
+
+func (s *ociSource) String() string {
+	return s.ref
+}
+
+func (s *ociSource) resolve() (*image, error) {
+	if s.image == nil {
+		img, err := resolve(s.store, s.root, s.platform)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to resolve image %q", s.ref)
+		}
+		s.image = img
+	}
+	return s.image, nil
+}
+
+// resolve resolves desc to the image manifest for p, following indexes
+func resolve(store blobStore, desc descriptor, p Platform) (*image, error) {
+	for depth := 0; depth < maxIndexDepth; depth++ {
+		raw, err := fetchVerified(store, desc)
+		if err != nil {
+			return nil, err
+		}
+		content := struct {
+			MediaType string       `json:"mediaType"`
+			Manifests []descriptor `json:"manifests"`
+			Config    descriptor   `json:"config"`
+			Layers    []descriptor `json:"layers"`
+		}{}
+		if err := json.Unmarshal(raw, &content); err != nil {
+			return nil, errors.Wrapf(err, "failed to parse %s", desc.Digest)
+		}
+		if isIndex(desc.MediaType) || len(content.Manifests) > 0 {
+			next, err := matchPlatform(content.Manifests, p)
+			if err != nil {
+				return nil, err
+			}
+			desc = next
+			continue
+		}
+		if content.Config.Digest == "" {
+			return nil, errors.Errorf("%s is not an image manifest", desc.Digest)
+		}
+		if desc.MediaType == "" {
+			desc.MediaType = content.MediaType
+		}
+		if desc.MediaType == "" {
+			desc.MediaType = mediaTypeOCIManifest
+		}
+		desc.Size = int64(len(raw))
+		desc.Platform = nil
+		desc.Annotations = nil
+		return &image{
+			manifest: desc,
+			raw:      raw,
+			config:   content.Config,
+			layers:   content.Layers,
+		}, nil
+	}
+	return nil, errors.Errorf("image indexes nested deeper than %d", maxIndexDepth)
+}
+
+func isIndex(mediaType string) bool {
+	return mediaType == mediaTypeOCIIndex || mediaType == mediaTypeDockerManifestList
+}
+
+// matchPlatform returns the manifest in manifests for p
+func matchPlatform(manifests []descriptor, p Platform) (descriptor, error) {
+	if len(manifests) == 1 && manifests[0].Platform == nil {
+		return manifests[0], nil
+	}
+	available := []string{}
+	for _, desc := range manifests {
+		if desc.Platform == nil {
+			continue
+		}
+		if p.Matches(*desc.Platform) {
+			return desc, nil
+		}
+		available = append(available, desc.Platform.String())
+	}
+	return descriptor{}, errors.Errorf("no image for platform %s, available platforms: %s", p, strings.Join(available, ", "))
+}
+
+// pickManifest returns the index entry of image and its sanitized name,
+// if image is empty the index must contain a single named image
+func pickManifest(idx *index, image string) (descriptor, string, error) {
+	if image != "" {
+		want := SanitizeImage(image)
+		for _, desc := range idx.Manifests {
+			if name := imageName(desc); name != "" && SanitizeImage(name) == want {
+				return desc, want, nil
+			}
+			if desc.Annotations[annotationRefName] == image {
+				return desc, want, nil
+			}
+		}
+		if len(idx.Manifests) == 1 {
+			return idx.Manifests[0], want, nil
+		}
+		return descriptor{}, "", errors.Errorf("image %q not found in index of %d images", image, len(idx.Manifests))
+	}
+	if len(idx.Manifests) != 1 {
+		return descriptor{}, "", errors.Errorf("index contains %d images, an image name is required", len(idx.Manifests))
+	}
+	desc := idx.Manifests[0]
+	name := imageName(desc)
+	if name == "" {
+		return descriptor{}, "", errors.New("image is not annotated with a name, an image name is required")
+	}
+	return desc, SanitizeImage(name), nil
+}
+
+// imageName returns the full image name desc is annotated with, if any
+func imageName(desc descriptor) string {
+	if name := desc.Annotations[annotationImageName]; name != "" {
+		return name
+	}
+	// the reference name is often only a tag, which does not name an image
+	if name := desc.Annotations[annotationRefName]; strings.ContainsAny(name, "/:") {
+		return name
+	}
+	return ""
+}
+
+// writeArchive writes an OCI image layout archive of img to w, with the
+// blobs before the index as containerd imports the index last
+func writeArchive(w io.Writer, name string, img *image, store blobStore) error {
+	return writeLayout(w, name, img.manifest, img.raw, []platformImage{{image: img, store: store}})
+}
+
+// writeLayout writes an OCI image layout archive of images to w, with the
+// root manifest or index annotated with name
+func writeLayout(w io.Writer, name string, root descriptor, rootRaw []byte, images []platformImage) error {
+	tw := tar.NewWriter(w)
+	written := map[string]bool{}
+	writeManifest := func(desc descriptor, raw []byte) error {
+		if written[desc.Digest] {
+			return nil
+		}
+		manifestPath, err := blobPath(desc.Digest)
+		if err != nil {
+			return err
+		}
+		if err := writeTarFile(tw, manifestPath, raw); err != nil {
+			return err
+		}
+		written[desc.Digest] = true
+		return nil
+	}
+	if err := writeManifest(root, rootRaw); err != nil {
+		return err
+	}
+	for _, img := range images {
+		if err := writeManifest(img.image.manifest, img.image.raw); err != nil {
+			return err
+		}
+		blobs := img.image.blobs()
+		err := img.store.walk(blobs, func(desc descriptor, r io.Reader) error {
+			if written[desc.Digest] {
+				return nil
+			}
+			if err := writeTarBlob(tw, desc, r); err != nil {
+				return err
+			}
+			written[desc.Digest] = true
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		for _, desc := range blobs {
+			if !written[desc.Digest] {
+				return errors.Errorf("blob %s not found", desc.Digest)
+			}
+		}
+	}
+	root.Platform = nil
+	root.Annotations = map[string]string{annotationImageName: name}
+	idx, err := json.Marshal(&index{
+		SchemaVersion: 2,
+		MediaType:     mediaTypeOCIIndex,
+		Manifests:     []descriptor{root},
+	})
+	if err != nil {
+		return err
+	}
+	if err := writeTarFile(tw, "oci-layout", []byte(`{"imageLayoutVersion":"1.0.0"}`)); err != nil {
+		return err
+	}
+	if err := writeTarFile(tw, "index.json", idx); err != nil {
+		return err
+	}
+	return tw.Close()
+}
+
+func writeTarFile(tw *tar.Writer, name string, content []byte) error {
+	if err := tw.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     name,
+		Mode:     0644,
+		Size:     int64(len(content)),
+	}); err != nil {
+		return err
+	}
+	_, err := tw.Write(content)
+	return err
+}
+
+// writeTarBlob copies desc from r to tw, verifying its size and digest
+func writeTarBlob(tw *tar.Writer, desc descriptor, r io.Reader) error {
+	name, err := blobPath(desc.Digest)
+	if err != nil {
+		return err
+	}
+	h, err := newHash(desc.Digest)
+	if err != nil {
+		return err
+	}
+	if err := tw.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     name,
+		Mode:     0644,
+		Size:     desc.Size,
+	}); err != nil {
+		return err
+	}
+	if _, err := io.CopyN(io.MultiWriter(tw, h), r, desc.Size); err != nil {
+		return errors.Wrapf(err, "failed to copy blob %s", desc.Digest)
+	}
+	return checkDigest(desc.Digest, h)
+}
+
+var digestRegexp = regexp.MustCompile(`^[a-z0-9]+:[a-f0-9]+$`)
+
+// blobPath returns the image layout path of the blob with digest
+func blobPath(digest string) (string, error) {
+	if !digestRegexp.MatchString(digest) {
+		return "", errors.Errorf("invalid digest %q", digest)
+	}
+	return "blobs/" + strings.Replace(digest, ":", "/", 1), nil
+}
+
+func newHash(digest string) (hash.Hash, error) {
+	switch algorithm := strings.SplitN(digest, ":", 2)[0]; algorithm {
+	case "sha256":
+		return sha256.New(), nil
+	case "sha512":
+		return sha512.New(), nil
+	default:
+		return nil, errors.Errorf("unsupported digest algorithm %q", algorithm)
+	}
+}
+
+func checkDigest(digest string, h hash.Hash) error {
+	algorithm := strings.SplitN(digest, ":", 2)[0]
+	if actual := algorithm + ":" + hex.EncodeToString(h.Sum(nil)); actual != digest {
+		return errors.Errorf("digest mismatch: expected %s, got %s", digest, actual)
+	}
+	return nil
+}
+
+// fetchVerified fetches desc from store, verifying its digest
+func fetchVerified(store blobStore, desc descriptor) ([]byte, error) {
+	raw, err := store.fetch(desc)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to fetch %s", desc.Digest)
+	}
+	h, err := newHash(desc.Digest)
+	if err != nil {
+		return nil, err
+	}
+	h.Write(raw)
+	if err := checkDigest(desc.Digest, h); err != nil {
+		return nil, err
+	}
+	return raw, nil
+}
+
+// readLimited reads all of r, up to maxFetchSize
+func readLimited(r io.Reader) ([]byte, error) {
+	raw, err := io.ReadAll(io.LimitReader(r, maxFetchSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(raw) > maxFetchSize {
+		return nil, errors.Errorf("content exceeds %d bytes", maxFetchSize)
+	}
+	return raw, nil
+}
+
+func parseIndex(raw []byte) (*index, error) {
+	idx := &index{}
+	if err := json.Unmarshal(raw, idx); err != nil {
+		return nil, errors.Wrap(err, "failed to parse index.json")
+	}
+	return idx, nil
+}
+
+// layoutStore is an OCI image layout directory
+type layoutStore struct {
+	dir string
+}
+
+func (s *layoutStore) root(image string) (descriptor, string, error) {
+	if _, err := os.Stat(filepath.Join(s.dir, "oci-layout")); err != nil {
+		return descriptor{}, "", errors.Errorf("%q is not an OCI image layout", s.dir)
+	}
+	raw, err := os.ReadFile(filepath.Join(s.dir, "index.json"))
+	if err != nil {
+		return descriptor{}, "", err
+	}
+	idx, err := parseIndex(raw)
+	if err != nil {
+		return descriptor{}, "", err
+	}
+	return pickManifest(idx, image)
+}
+
+func (s *layoutStore) fetch(desc descriptor) ([]byte, error) {
+	f, err := s.open(desc)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return readLimited(f)
+}
+
+func (s *layoutStore) walk(descs []descriptor, fn func(descriptor, io.Reader) error) error {
+	for _, desc := range descs {
+		f, err := s.open(desc)
+		if err != nil {
+			return err
+		}
+		err = fn(desc, f)
+		f.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *layoutStore) open(desc descriptor) (*os.File, error) {
+	name, err := blobPath(desc.Digest)
+	if err != nil {
+		return nil, err
+	}
+	return os.Open(filepath.Join(s.dir, filepath.FromSlash(name)))
+}
+
+// archiveStore is a tar archive of an OCI image layout, which is read
+// sequentially
+type archiveStore struct {
+	path string
+}
+
+func (s *archiveStore) root(image string) (descriptor, string, error) {
+	raw, err := s.readFile("index.json")
+	if err != nil {
+		return descriptor{}, "", err
+	}
+	idx, err := parseIndex(raw)
+	if err != nil {
+		return descriptor{}, "", err
+	}
+	return pickManifest(idx, image)
+}
+
+func (s *archiveStore) fetch(desc descriptor) ([]byte, error) {
+	name, err := blobPath(desc.Digest)
+	if err != nil {
+		return nil, err
+	}
+	return s.readFile(name)
+}
+
+func (s *archiveStore) walk(descs []descriptor, fn func(descriptor, io.Reader) error) error {
+	wanted := map[string]descriptor{}
+	for _, desc := range descs {
+		name, err := blobPath(desc.Digest)
+		if err != nil {
+			return err
+		}
+		wanted[name] = desc
+	}
+	return s.scan(func(name string, r io.Reader) (bool, error) {
+		desc, ok := wanted[name]
+		if !ok {
+			return false, nil
+		}
+		delete(wanted, name)
+		return len(wanted) == 0, fn(desc, r)
+	})
+}
+
+// readFile returns the content of the archive file name
+func (s *archiveStore) readFile(name string) ([]byte, error) {
+	var content []byte
+	err := s.scan(func(entry string, r io.Reader) (bool, error) {
+		if entry != name {
+			return false, nil
+		}
+		raw, err := readLimited(r)
+		content = raw
+		return true, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if content == nil {
+		return nil, errors.Errorf("%s not found in %q", name, s.path)
+	}
+	return content, nil
+}
+
+// names returns the set of regular files in the archive
+func (s *archiveStore) names() (map[string]bool, error) {
+	names := map[string]bool{}
+	err := s.scan(func(name string, _ io.Reader) (bool, error) {
+		names[name] = true
+		return false, nil
+	})
+	return names, err
+}
+
+// scan calls fn with each regular file in the archive until it is done
+func (s *archiveStore) scan(fn func(name string, r io.Reader) (done bool, err error)) error {
+	f, err := os.Open(s.path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	tr := tar.NewReader(f)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return errors.Wrapf(err, "failed to read %q", s.path)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		done, err := fn(path.Clean(hdr.Name), tr)
+		if err != nil || done {
+			return err
+		}
+	}
+}
@@ -0,0 +1,320 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package imageload
+
+import (
+	"archive/tar"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"sigs.k8s.io/kind/pkg/internal/assert"
+)
+
+// testImage is a two platform image for tests
+type testImage struct {
+	blobs         map[string][]byte
+	index         descriptor
+	configDigest  string
+	otherConfig   string
+	otherManifest string
+}
+
+// nativePlatform is the platform of the test image's default manifest
+var nativePlatform = Platform{OS: "linux", Architecture: runtime.GOARCH}
+
+func newTestImage(t *testing.T) *testImage {
+	t.Helper()
+	ti := &testImage{blobs: map[string][]byte{}}
+	layer := ti.add(t, "", []byte("layer"))
+	platformManifest := func(arch string) (descriptor, string) {
+		config := ti.add(t, "application/vnd.oci.image.config.v1+json",
+			[]byte(`{"architecture":"`+arch+`","os":"linux"}`))
+		m := ti.add(t, mediaTypeOCIManifest, mustMarshal(t, map[string]interface{}{
+			"schemaVersion": 2,
+			"mediaType":     mediaTypeOCIManifest,
+			"config":        config,
+			"layers":        []descriptor{layer, layer},
+		}))
+		m.Platform = &Platform{OS: "linux", Architecture: arch}
+		return m, config.Digest
+	}
+	native, nativeConfig := platformManifest(runtime.GOARCH)
+	other, otherConfig := platformManifest("other")
+	ti.index = ti.add(t, mediaTypeOCIIndex, mustMarshal(t, &index{
+		SchemaVersion: 2,
+		MediaType:     mediaTypeOCIIndex,
+		Manifests:     []descriptor{other, native},
+	}))
+	ti.configDigest = nativeConfig
+	ti.otherConfig = otherConfig
+	ti.otherManifest = other.Digest
+	return ti
+}
+
+func (ti *testImage) add(t *testing.T, mediaType string, content []byte) descriptor {
+	sum := sha256.Sum256(content)
+	digest := "sha256:" + hex.EncodeToString(sum[:])
+	ti.blobs[digest] = content
+	if mediaType == "" {
+		mediaType = "application/vnd.oci.image.layer.v1.tar"
+	}
+	return descriptor{MediaType: mediaType, Digest: digest, Size: int64(len(content))}
+}
+
+// files returns the image layout files, annotating the image with name
+// if set
+func (ti *testImage) files(t *testing.T, name string) map[string][]byte {
+	root := ti.index
+	if name != "" {
+		root.Annotations = map[string]string{annotationImageName: name}
+	}
+	files := map[string][]byte{
+		"oci-layout": []byte(`{"imageLayoutVersion":"1.0.0"}`),
+		"index.json": mustMarshal(t, &index{SchemaVersion: 2, Manifests: []descriptor{root}}),
+	}
+	for digest, content := range ti.blobs {
+		p, err := blobPath(digest)
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[p] = content
+	}
+	return files
+}
+
+func (ti *testImage) writeLayout(t *testing.T, name string) string {
+	dir := t.TempDir()
+	for p, content := range ti.files(t, name) {
+		p = filepath.Join(dir, filepath.FromSlash(p))
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, content, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func (ti *testImage) writeArchive(t *testing.T, name string) string {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for p, content := range ti.files(t, name) {
+		if err := writeTarFile(tw, "./"+p, content); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	p := filepath.Join(t.TempDir(), "image.tar")
+	if err := os.WriteFile(p, buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func mustMarshal(t *testing.T, v interface{}) []byte {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return raw
+}
+
+// readTar returns the files in the archive
+func readTar(t *testing.T, archive []byte) map[string][]byte {
+	t.Helper()
+	files := map[string][]byte{}
+	tr := tar.NewReader(bytes.NewReader(archive))
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return files
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		content, err := io.ReadAll(tr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[hdr.Name] = content
+	}
+}
+
+// checkSource checks src resolves to the native platform of ti and writes
+// an archive containerd imports as name
+func checkSource(t *testing.T, src Source, ti *testImage, name string) {
+	t.Helper()
+	assert.StringEqual(t, name, src.Name())
+	id, err := src.ID()
+	assert.ExpectError(t, false, err)
+	assert.StringEqual(t, ti.configDigest, id)
+
+	var buf bytes.Buffer
+	assert.ExpectError(t, false, src.WriteArchive(&buf))
+	files := readTar(t, buf.Bytes())
+	configPath, _ := blobPath(ti.configDigest)
+	if _, ok := files[configPath]; !ok {
+		t.Errorf("archive is missing the image config")
+	}
+	otherPath, _ := blobPath(ti.otherConfig)
+	if _, ok := files[otherPath]; ok {
+		t.Errorf("archive contains the config of another platform")
+	}
+	idx := &index{}
+	if err := json.Unmarshal(files["index.json"], idx); err != nil {
+		t.Fatal(err)
+	}
+	if len(idx.Manifests) != 1 {
+		t.Fatalf("expected one manifest in the index, got %d", len(idx.Manifests))
+	}
+	assert.StringEqual(t, name, idx.Manifests[0].Annotations[annotationImageName])
+	manifestPath, _ := blobPath(idx.Manifests[0].Digest)
+	if _, ok := files[manifestPath]; !ok {
+		t.Errorf("archive is missing the image manifest")
+	}
+	// the layer is referenced twice but written once
+	if len(files) != 5 {
+		t.Errorf("expected 5 files in the archive, got %d", len(files))
+	}
+}
+
+func TestLayoutSource(t *testing.T) {
+	t.Parallel()
+	ti := newTestImage(t)
+	dir := ti.writeLayout(t, "app:1.0")
+	src, err := Parse(OCILayoutPrefix+dir, nil, nativePlatform)
+	assert.ExpectError(t, false, err)
+	checkSource(t, src, ti, "docker.io/library/app:1.0")
+}
+
+func TestLayoutSourceName(t *testing.T) {
+	t.Parallel()
+	ti := newTestImage(t)
+	dir := ti.writeLayout(t, "")
+	_, err := Parse(OCILayoutPrefix+dir, nil, nativePlatform)
+	assert.ExpectError(t, true, err)
+	src, err := Parse(OCILayoutPrefix+dir+":localhost:5001/app:2", nil, nativePlatform)
+	assert.ExpectError(t, false, err)
+	checkSource(t, src, ti, "localhost:5001/app:2")
+}
+
+func TestLayoutSourceCorruptBlob(t *testing.T) {
+	t.Parallel()
+	ti := newTestImage(t)
+	dir := ti.writeLayout(t, "app:1.0")
+	layer := []byte("layer")
+	sum := sha256.Sum256(layer)
+	layerPath := filepath.Join(dir, "blobs", "sha256", hex.EncodeToString(sum[:]))
+	if err := os.WriteFile(layerPath, []byte("LAYER"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	src, err := Parse(OCILayoutPrefix+dir, nil, nativePlatform)
+	assert.ExpectError(t, false, err)
+	assert.ExpectError(t, true, src.WriteArchive(io.Discard))
+}
+
+func TestLayoutSourcePlatform(t *testing.T) {
+	t.Parallel()
+	ti := newTestImage(t)
+	dir := ti.writeLayout(t, "app:1.0")
+	src, err := Parse(OCILayoutPrefix+dir, nil, Platform{OS: "linux", Architecture: "other"})
+	assert.ExpectError(t, false, err)
+	id, err := src.ID()
+	assert.ExpectError(t, false, err)
+	assert.StringEqual(t, ti.otherConfig, id)
+
+	src, err = Parse(OCILayoutPrefix+dir, nil, Platform{OS: "linux", Architecture: "missing"})
+	assert.ExpectError(t, false, err)
+	_, err = src.ID()
+	assert.ExpectError(t, true, err)
+}
+
+func TestArchiveSource(t *testing.T) {
+	t.Parallel()
+	ti := newTestImage(t)
+	archive := ti.writeArchive(t, "registry.example/app:1.0")
+	src, err := Parse(OCIArchivePrefix+archive, nil, nativePlatform)
+	assert.ExpectError(t, false, err)
+	checkSource(t, src, ti, "registry.example/app:1.0")
+}
+
+func TestMatchPlatform(t *testing.T) {
+	t.Parallel()
+	amd64 := descriptor{Digest: "sha256:a", Platform: &Platform{OS: "linux", Architecture: "amd64"}}
+	arm64 := descriptor{Digest: "sha256:b", Platform: &Platform{OS: "linux", Architecture: "arm64", Variant: "v8"}}
+	unknown := descriptor{Digest: "sha256:c", Platform: &Platform{OS: "unknown", Architecture: "unknown"}}
+	cases := []struct {
+		Name        string
+		Manifests   []descriptor
+		Platform    Platform
+		Expected    string
+		ExpectError bool
+	}{
+		{
+			Name:      "match",
+			Manifests: []descriptor{unknown, amd64, arm64},
+			Platform:  Platform{OS: "linux", Architecture: "arm64"},
+			Expected:  "sha256:b",
+		},
+		{
+			Name:      "match variant",
+			Manifests: []descriptor{amd64, arm64},
+			Platform:  Platform{OS: "linux", Architecture: "arm64", Variant: "v8"},
+			Expected:  "sha256:b",
+		},
+		{
+			Name:      "single manifest without platform",
+			Manifests: []descriptor{{Digest: "sha256:d"}},
+			Platform:  Platform{OS: "linux", Architecture: "arm64"},
+			Expected:  "sha256:d",
+		},
+		{
+			Name:        "no match",
+			Manifests:   []descriptor{amd64, unknown},
+			Platform:    Platform{OS: "linux", Architecture: "arm64"},
+			ExpectError: true,
+		},
+	}
+	for _, tc := range cases {
+		tc := tc // capture range variable
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+			desc, err := matchPlatform(tc.Manifests, tc.Platform)
+			assert.ExpectError(t, tc.ExpectError, err)
+			assert.StringEqual(t, tc.Expected, desc.Digest)
+		})
+	}
+}
+
+func TestBlobPath(t *testing.T) {
+	t.Parallel()
+	p, err := blobPath("sha256:abc123")
+	assert.ExpectError(t, false, err)
+	assert.StringEqual(t, "blobs/sha256/abc123", p)
+	_, err = blobPath("sha256:../../etc/passwd")
+	assert.ExpectError(t, true, err)
+}
@@ -0,0 +1,234 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package imageload
+
+import (
+	"encoding/json"
+	"path"
+	"sort"
+	"strings"
+
+	"sigs.k8s.io/kind/pkg/cluster/nodes"
+	"sigs.k8s.io/kind/pkg/cluster/nodeutils"
+	"sigs.k8s.io/kind/pkg/errors"
+)
+
+// Platform is an OCI image platform
+type Platform struct {
+	Architecture string `json:"architecture"`
+	OS           string `json:"os"`
+	Variant      string `json:"variant,omitempty"`
+}
+
+// ParsePlatform parses a platform as in os/arch[/variant], E.G. linux/arm64,
+// the os defaults to linux if only an architecture is given
+func ParsePlatform(s string) (Platform, error) {
+	parts := strings.Split(s, "/")
+	for _, part := range parts {
+		if part == "" {
+			return Platform{}, errors.Errorf("invalid platform %q, expected os/arch[/variant]", s)
+		}
+	}
+	switch len(parts) {
+	case 1:
+		return Platform{OS: "linux", Architecture: parts[0]}, nil
+	case 2:
+		return Platform{OS: parts[0], Architecture: parts[1]}, nil
+	case 3:
+		return Platform{OS: parts[0], Architecture: parts[1], Variant: parts[2]}, nil
+	}
+	return Platform{}, errors.Errorf("invalid platform %q, expected os/arch[/variant]", s)
+}
+
+func (p Platform) String() string {
+	s := p.OS + "/" + p.Architecture
+	if p.Variant != "" {
+		s += "/" + p.Variant
+	}
+	return s
+}
+
+// Matches returns true if an image for other runs on p, variants are only
+// compared if both platforms have one
+func (p Platform) Matches(other Platform) bool {
+	return p.OS == other.OS && p.Architecture == other.Architecture &&
+		(p.Variant == "" || other.Variant == "" || p.Variant == other.Variant)
+}
+
+// NodePlatform returns the platform parsed from flag if set, otherwise the
+// platform of node
+func NodePlatform(flag string, node nodes.Node) (Platform, error) {
+	if flag != "" {
+		return ParsePlatform(flag)
+	}
+	platform, err := nodeutils.Platform(node)
+	if err != nil {
+		return Platform{}, err
+	}
+	return ParsePlatform(platform)
+}
+
+// CheckArchivePlatform returns an error unless every image in the image
+// archive at path, in the OCI image layout or docker save format, has
+// content for p
+func CheckArchivePlatform(path string, p Platform) error {
+	images, err := archivePlatforms(&archiveStore{path: path})
+	if err != nil {
+		return errors.Wrapf(err, "failed to read platforms of %q", path)
+	}
+	names := make([]string, 0, len(images))
+	for name := range images {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		available := []string{}
+		matched := false
+		for _, platform := range images[name] {
+			matched = matched || p.Matches(platform)
+			available = append(available, platform.String())
+		}
+		if !matched {
+			return errors.Errorf("image %s in %q has no %s content, available platforms: %s", name, path, p, strings.Join(available, ", "))
+		}
+	}
+	return nil
+}
+
+// archivePlatforms returns the platforms the archive has content for, by image
+func archivePlatforms(s *archiveStore) (map[string][]Platform, error) {
+	names, err := s.names()
+	if err != nil {
+		return nil, err
+	}
+	images := map[string][]Platform{}
+	if names["index.json"] {
+		raw, err := s.readFile("index.json")
+		if err != nil {
+			return nil, err
+		}
+		idx, err := parseIndex(raw)
+		if err != nil {
+			return nil, err
+		}
+		for _, desc := range idx.Manifests {
+			name := imageName(desc)
+			if name == "" {
+				name = desc.Digest
+			}
+			platforms, err := descriptorPlatforms(s, names, desc, 0)
+			if err != nil {
+				return nil, err
+			}
+			images[name] = append(images[name], platforms...)
+		}
+		return images, nil
+	}
+	if !names["manifest.json"] {
+		return nil, errors.New("not an image archive, missing index.json and manifest.json")
+	}
+	// the docker save format lists the config of each image
+	raw, err := s.readFile("manifest.json")
+	if err != nil {
+		return nil, err
+	}
+	manifests := []struct {
+		Config   string
+		RepoTags []string
+	}{}
+	if err := json.Unmarshal(raw, &manifests); err != nil {
+		return nil, errors.Wrap(err, "failed to parse manifest.json")
+	}
+	for _, m := range manifests {
+		name := m.Config
+		if len(m.RepoTags) > 0 {
+			name = m.RepoTags[0]
+		}
+		config, err := s.readFile(path.Clean(m.Config))
+		if err != nil {
+			return nil, err
+		}
+		platform, err := configPlatform(config)
+		if err != nil {
+			return nil, err
+		}
+		images[name] = append(images[name], platform)
+	}
+	return images, nil
+}
+
+// descriptorPlatforms returns the platforms of desc with content in the
+// archive, following indexes
+func descriptorPlatforms(s *archiveStore, names map[string]bool, desc descriptor, depth int) ([]Platform, error) {
+	if depth >= maxIndexDepth {
+		return nil, errors.Errorf("image indexes nested deeper than %d", maxIndexDepth)
+	}
+	name, err := blobPath(desc.Digest)
+	if err != nil {
+		return nil, err
+	}
+	// indexes may list platforms that were not exported
+	if !names[name] {
+		return nil, nil
+	}
+	if desc.Platform != nil && !isIndex(desc.MediaType) {
+		return []Platform{*desc.Platform}, nil
+	}
+	raw, err := fetchVerified(s, desc)
+	if err != nil {
+		return nil, err
+	}
+	content := struct {
+		Manifests []descriptor `json:"manifests"`
+		Config    descriptor   `json:"config"`
+	}{}
+	if err := json.Unmarshal(raw, &content); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse %s", desc.Digest)
+	}
+	if isIndex(desc.MediaType) || len(content.Manifests) > 0 {
+		platforms := []Platform{}
+		for _, child := range content.Manifests {
+			childPlatforms, err := descriptorPlatforms(s, names, child, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			platforms = append(platforms, childPlatforms...)
+		}
+		return platforms, nil
+	}
+	config, err := fetchVerified(s, content.Config)
+	if err != nil {
+		return nil, err
+	}
+	platform, err := configPlatform(config)
+	if err != nil {
+		return nil, err
+	}
+	return []Platform{platform}, nil
+}
+
+// configPlatform returns the platform of an image config
+func configPlatform(config []byte) (Platform, error) {
+	p := Platform{}
+	if err := json.Unmarshal(config, &p); err != nil {
+		return Platform{}, errors.Wrap(err, "failed to parse image config")
+	}
+	if p.OS == "" || p.Architecture == "" {
+		return Platform{}, errors.New("image config is missing the platform")
+	}
+	return p, nil
+}
@@ -0,0 +1,112 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package imageload
+
+import (
+	"archive/tar"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"sigs.k8s.io/kind/pkg/internal/assert"
+)
+
+func TestParsePlatform(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		In          string
+		Expected    Platform
+		ExpectError bool
+	}{
+		{In: "arm64", Expected: Platform{OS: "linux", Architecture: "arm64"}},
+		{In: "linux/amd64", Expected: Platform{OS: "linux", Architecture: "amd64"}},
+		{In: "linux/arm/v7", Expected: Platform{OS: "linux", Architecture: "arm", Variant: "v7"}},
+		{In: "", ExpectError: true},
+		{In: "linux//v7", ExpectError: true},
+		{In: "linux/arm/v7/extra", ExpectError: true},
+	}
+	for _, tc := range cases {
+		tc := tc // capture range variable
+		t.Run(tc.In, func(t *testing.T) {
+			t.Parallel()
+			p, err := ParsePlatform(tc.In)
+			assert.ExpectError(t, tc.ExpectError, err)
+			assert.DeepEqual(t, tc.Expected, p)
+		})
+	}
+}
+
+func TestPlatformMatches(t *testing.T) {
+	t.Parallel()
+	arm64 := Platform{OS: "linux", Architecture: "arm64"}
+	arm64v8 := Platform{OS: "linux", Architecture: "arm64", Variant: "v8"}
+	armv7 := Platform{OS: "linux", Architecture: "arm", Variant: "v7"}
+	armv6 := Platform{OS: "linux", Architecture: "arm", Variant: "v6"}
+	assert.BoolEqual(t, true, arm64.Matches(arm64v8))
+	assert.BoolEqual(t, true, arm64v8.Matches(arm64))
+	assert.BoolEqual(t, false, armv7.Matches(armv6))
+	assert.BoolEqual(t, false, arm64.Matches(Platform{OS: "linux", Architecture: "amd64"}))
+	assert.BoolEqual(t, false, arm64.Matches(Platform{OS: "windows", Architecture: "arm64"}))
+}
+
+func TestCheckArchivePlatform(t *testing.T) {
+	t.Parallel()
+	native := Platform{OS: "linux", Architecture: runtime.GOARCH}
+	missing := Platform{OS: "linux", Architecture: "missing"}
+
+	ti := newTestImage(t)
+	archive := ti.writeArchive(t, "app:1.0")
+	assert.ExpectError(t, false, CheckArchivePlatform(archive, native))
+	assert.ExpectError(t, false, CheckArchivePlatform(archive, Platform{OS: "linux", Architecture: "other"}))
+	assert.ExpectError(t, true, CheckArchivePlatform(archive, missing))
+
+	// platforms listed by the index without exported content do not match
+	delete(ti.blobs, ti.otherManifest)
+	delete(ti.blobs, ti.otherConfig)
+	partial := ti.writeArchive(t, "app:1.0")
+	assert.ExpectError(t, false, CheckArchivePlatform(partial, native))
+	assert.ExpectError(t, true, CheckArchivePlatform(partial, Platform{OS: "linux", Architecture: "other"}))
+}
+
+func TestCheckArchivePlatformDockerFormat(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "image.tar")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tw := tar.NewWriter(f)
+	for name, content := range map[string]string{
+		"manifest.json": `[{"Config":"abc.json","RepoTags":["app:1.0"],"Layers":["def/layer.tar"]}]`,
+		"abc.json":      `{"architecture":"arm","os":"linux","variant":"v7"}`,
+		"def/layer.tar": "layer",
+	} {
+		if err := writeTarFile(tw, name, []byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+	assert.ExpectError(t, false, CheckArchivePlatform(path, Platform{OS: "linux", Architecture: "arm"}))
+	assert.ExpectError(t, true, CheckArchivePlatform(path, Platform{OS: "linux", Architecture: "arm", Variant: "v6"}))
+	assert.ExpectError(t, true, CheckArchivePlatform(path, Platform{OS: "linux", Architecture: "amd64"}))
+}
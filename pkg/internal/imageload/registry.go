@@ -0,0 +1,357 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package imageload
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"sigs.k8s.io/kind/pkg/errors"
+)
+
+// manifestMediaTypes are the manifest and index media types accepted from
+// registries
+var manifestMediaTypes = []string{
+	mediaTypeOCIIndex,
+	mediaTypeDockerManifestList,
+	mediaTypeOCIManifest,
+	mediaTypeDockerManifest,
+}
+
+// registryStore is an image repository in a registry, accessed anonymously
+type registryStore struct {
+	// host is the registry API host, E.G. localhost:5001
+	host       string
+	repository string
+	// reference is a tag or digest
+	reference string
+	scheme    string
+	// actions are the repository actions to get a token for, E.G. pull
+	actions string
+	token   string
+	client  *http.Client
+	// manifests caches the manifests fetched by root by digest
+	manifests map[string][]byte
+}
+
+func newRegistrySource(ref, image string, platform Platform) (Source, error) {
+	return newOCISource(ref, image, newRegistryStore(image, "pull"), platform)
+}
+
+func newRegistryStore(image, actions string) *registryStore {
+	host, repository, reference := parseReference(image)
+	return &registryStore{
+		host:       host,
+		repository: repository,
+		reference:  reference,
+		scheme:     "https",
+		actions:    actions,
+		client:     &http.Client{},
+		manifests:  map[string][]byte{},
+	}
+}
+
+// parseReference splits image into the registry API host, repository and
+// tag or digest
+func parseReference(image string) (host, repository, reference string) {
+	name := SanitizeImage(image)
+	if i := strings.IndexRune(name, '@'); i != -1 {
+		name, reference = name[:i], name[i+1:]
+		// a digest takes precedence over any tag
+		if hasTag(name) {
+			name = name[:strings.LastIndex(name, ":")]
+		}
+	} else {
+		i := strings.LastIndex(name, ":")
+		name, reference = name[:i], name[i+1:]
+	}
+	i := strings.IndexRune(name, '/')
+	host, repository = name[:i], name[i+1:]
+	if host == "docker.io" {
+		host = "registry-1.docker.io"
+	}
+	return host, repository, reference
+}
+
+func (r *registryStore) root(image string) (descriptor, string, error) {
+	resp, err := r.get("manifests/"+r.reference, manifestMediaTypes...)
+	if err != nil {
+		return descriptor{}, "", err
+	}
+	defer resp.Body.Close()
+	raw, err := readLimited(resp.Body)
+	if err != nil {
+		return descriptor{}, "", err
+	}
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	digest := resp.Header.Get("Docker-Content-Digest")
+	if strings.ContainsRune(r.reference, ':') {
+		digest = r.reference
+	} else if digest == "" {
+		sum := sha256.Sum256(raw)
+		digest = "sha256:" + hex.EncodeToString(sum[:])
+	}
+	r.manifests[digest] = raw
+	return descriptor{
+		MediaType: mediaType,
+		Digest:    digest,
+		Size:      int64(len(raw)),
+	}, SanitizeImage(image), nil
+}
+
+func (r *registryStore) fetch(desc descriptor) ([]byte, error) {
+	if raw, ok := r.manifests[desc.Digest]; ok {
+		return raw, nil
+	}
+	endpoint := "blobs/"
+	if desc.MediaType == "" || isManifestMediaType(desc.MediaType) {
+		endpoint = "manifests/"
+	}
+	resp, err := r.get(endpoint+desc.Digest, manifestMediaTypes...)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	return readLimited(resp.Body)
+}
+
+func (r *registryStore) walk(descs []descriptor, fn func(descriptor, io.Reader) error) error {
+	for _, desc := range descs {
+		resp, err := r.get("blobs/" + desc.Digest)
+		if err != nil {
+			return err
+		}
+		err = fn(desc, resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func isManifestMediaType(mediaType string) bool {
+	for _, t := range manifestMediaTypes {
+		if mediaType == t {
+			return true
+		}
+	}
+	return false
+}
+
+// blobExists returns true if the repository has the blob digest
+func (r *registryStore) blobExists(digest string) (bool, error) {
+	resp, err := r.do(http.MethodHead, "blobs/"+digest, nil, nil, 0)
+	if err != nil {
+		return false, err
+	}
+	resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return true, nil
+	case http.StatusNotFound:
+		return false, nil
+	}
+	return false, errors.Errorf("failed to check for blob %s in %s/%s: %s", digest, r.host, r.repository, resp.Status)
+}
+
+// pushBlob uploads the content of desc in a single request
+func (r *registryStore) pushBlob(desc descriptor, content io.Reader) error {
+	resp, err := r.do(http.MethodPost, "blobs/uploads/", nil, nil, 0)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusAccepted {
+		return errors.Errorf("failed to start upload of blob %s to %s/%s: %s", desc.Digest, r.host, r.repository, resp.Status)
+	}
+	location, err := url.Parse(resp.Header.Get("Location"))
+	if err != nil || location.String() == "" {
+		return errors.Errorf("invalid upload location %q from %s", resp.Header.Get("Location"), r.host)
+	}
+	query := location.Query()
+	query.Set("digest", desc.Digest)
+	location.RawQuery = query.Encode()
+	header := http.Header{}
+	header.Set("Content-Type", "application/octet-stream")
+	resp, err = r.do(http.MethodPut, location.String(), header, content, desc.Size)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		return errors.Errorf("failed to upload blob %s to %s/%s: %s", desc.Digest, r.host, r.repository, resp.Status)
+	}
+	return nil
+}
+
+// pushManifest puts the manifest or index raw as reference, a tag or digest
+func (r *registryStore) pushManifest(reference, mediaType string, raw []byte) error {
+	header := http.Header{}
+	header.Set("Content-Type", mediaType)
+	resp, err := r.do(http.MethodPut, "manifests/"+reference, header, bytes.NewReader(raw), int64(len(raw)))
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		return errors.Errorf("failed to push manifest %s to %s/%s: %s", reference, r.host, r.repository, resp.Status)
+	}
+	return nil
+}
+
+// get gets the repository API path
+func (r *registryStore) get(path string, accept ...string) (*http.Response, error) {
+	header := http.Header{}
+	if len(accept) > 0 {
+		header.Set("Accept", strings.Join(accept, ", "))
+	}
+	resp, err := r.do(http.MethodGet, path, header, nil, 0)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, errors.Errorf("failed to get %s/%s from %s: %s", r.repository, path, r.host, resp.Status)
+	}
+	return resp, nil
+}
+
+// do sends a request to the repository API path, or to a URL returned by the
+// registry, authenticating with an anonymous token if the registry requires
+// one. Requests with a body are not retried, so they must follow a request
+// without one.
+func (r *registryStore) do(method, path string, header http.Header, body io.Reader, size int64) (*http.Response, error) {
+	resp, err := r.send(method, path, header, body, size)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusUnauthorized && r.token == "" && body == nil {
+		challenge := resp.Header.Get("WWW-Authenticate")
+		resp.Body.Close()
+		token, err := r.authenticate(challenge)
+		if err != nil {
+			return nil, err
+		}
+		r.token = token
+		return r.send(method, path, header, body, size)
+	}
+	return resp, nil
+}
+
+func (r *registryStore) send(method, path string, header http.Header, body io.Reader, size int64) (*http.Response, error) {
+	for {
+		req, err := http.NewRequest(method, r.url(path), body)
+		if err != nil {
+			return nil, err
+		}
+		for key, values := range header {
+			req.Header[key] = values
+		}
+		if body != nil {
+			req.ContentLength = size
+		}
+		if r.token != "" {
+			req.Header.Set("Authorization", "Bearer "+r.token)
+		}
+		resp, err := r.client.Do(req)
+		if err != nil && body == nil && r.scheme == "https" && isLoopback(r.host) {
+			// local registries commonly serve plain HTTP
+			r.scheme = "http"
+			continue
+		}
+		return resp, err
+	}
+}
+
+// url returns the URL of the repository API path, absolute paths and URLs
+// returned by the registry are resolved against the registry
+func (r *registryStore) url(path string) string {
+	base := &url.URL{Scheme: r.scheme, Host: r.host, Path: "/v2/" + r.repository + "/"}
+	ref, err := url.Parse(path)
+	if err != nil {
+		return base.String() + path
+	}
+	return base.ResolveReference(ref).String()
+}
+
+var challengeParamRegexp = regexp.MustCompile(`(\w+)="([^"]*)"`)
+
+// authenticate gets an anonymous pull token for the bearer challenge
+func (r *registryStore) authenticate(challenge string) (string, error) {
+	if !strings.HasPrefix(strings.ToLower(challenge), "bearer ") {
+		return "", errors.Errorf("registry %s requires unsupported authentication %q", r.host, challenge)
+	}
+	params := map[string]string{}
+	for _, match := range challengeParamRegexp.FindAllStringSubmatch(challenge, -1) {
+		params[match[1]] = match[2]
+	}
+	realm, err := url.Parse(params["realm"])
+	if err != nil || params["realm"] == "" {
+		return "", errors.Errorf("invalid authentication challenge %q", challenge)
+	}
+	query := realm.Query()
+	if service := params["service"]; service != "" {
+		query.Set("service", service)
+	}
+	scope := params["scope"]
+	if scope == "" || r.actions != "pull" {
+		scope = "repository:" + r.repository + ":" + r.actions
+	}
+	query.Set("scope", scope)
+	realm.RawQuery = query.Encode()
+	resp, err := r.client.Get(realm.String())
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get registry token")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", errors.Errorf("failed to get registry token: %s", resp.Status)
+	}
+	token := struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}{}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return "", errors.Wrap(err, "failed to parse registry token")
+	}
+	if token.Token != "" {
+		return token.Token, nil
+	}
+	return token.AccessToken, nil
+}
+
+// isLoopback returns true if host is a loopback address, E.G. localhost:5001
+func isLoopback(host string) bool {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
@@ -0,0 +1,122 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package imageload
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kind/pkg/internal/assert"
+)
+
+func TestParseReference(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		Image      string
+		Host       string
+		Repository string
+		Reference  string
+	}{
+		{
+			Image:      "localhost:5001/app:1.0",
+			Host:       "localhost:5001",
+			Repository: "app",
+			Reference:  "1.0",
+		},
+		{
+			Image:      "busybox",
+			Host:       "registry-1.docker.io",
+			Repository: "library/busybox",
+			Reference:  "latest",
+		},
+		{
+			Image:      "registry.example/team/app:1.0@sha256:abc",
+			Host:       "registry.example",
+			Repository: "team/app",
+			Reference:  "sha256:abc",
+		},
+	}
+	for _, tc := range cases {
+		tc := tc // capture range variable
+		t.Run(tc.Image, func(t *testing.T) {
+			t.Parallel()
+			host, repository, reference := parseReference(tc.Image)
+			assert.StringEqual(t, tc.Host, host)
+			assert.StringEqual(t, tc.Repository, repository)
+			assert.StringEqual(t, tc.Reference, reference)
+		})
+	}
+}
+
+func TestIsLoopback(t *testing.T) {
+	t.Parallel()
+	for host, expected := range map[string]bool{
+		"localhost:5001":   true,
+		"127.0.0.1:5000":   true,
+		"[::1]:5000":       true,
+		"registry.example": false,
+		"10.0.0.1:5000":    false,
+	} {
+		assert.BoolEqual(t, expected, isLoopback(host))
+	}
+}
+
+func TestRegistrySource(t *testing.T) {
+	t.Parallel()
+	ti := newTestImage(t)
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			_, _ = w.Write([]byte(`{"token":"secret"}`))
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="`+server.URL+`/token",service="test"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		const prefix = "/v2/team/app/"
+		if !strings.HasPrefix(r.URL.Path, prefix) {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		parts := strings.SplitN(strings.TrimPrefix(r.URL.Path, prefix), "/", 2)
+		ref := parts[1]
+		if parts[0] == "manifests" && ref == "1.0" {
+			ref = ti.index.Digest
+			w.Header().Set("Content-Type", mediaTypeOCIIndex)
+		}
+		content, ok := ti.blobs[ref]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Docker-Content-Digest", ref)
+		_, _ = w.Write(content)
+	}))
+	defer server.Close()
+
+	host := strings.TrimPrefix(server.URL, "http://")
+	src, err := Parse(RegistryPrefix+host+"/team/app:1.0", nil, nativePlatform)
+	assert.ExpectError(t, false, err)
+	checkSource(t, src, ti, host+"/team/app:1.0")
+
+	_, err = Parse(RegistryPrefix+host+"/team/app:missing", nil, nativePlatform)
+	assert.ExpectError(t, true, err)
+}
@@ -0,0 +1,112 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package imageload
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"sigs.k8s.io/kind/pkg/errors"
+)
+
+// Source reference prefixes, references without one of these are images in
+// the provider's local image store
+const (
+	// OCILayoutPrefix selects an OCI image layout directory,
+	// as in oci:<dir>[:<image>]
+	OCILayoutPrefix = "oci:"
+	// OCIArchivePrefix selects a tar archive of an OCI image layout,
+	// as in oci-archive:<file>[:<image>]
+	OCIArchivePrefix = "oci-archive:"
+	// RegistryPrefix selects an image in a registry,
+	// as in registry:<image>
+	RegistryPrefix = "registry:"
+)
+
+// Source is an image that can be loaded into nodes
+type Source interface {
+	// Name returns the sanitized image name the image is loaded as,
+	// E.G. docker.io/library/busybox:latest
+	Name() string
+	// ID returns the ID nodes report for the image once it is loaded
+	ID() (string, error)
+	// WriteArchive writes an archive of the image to w that
+	// nodeutils.LoadImageArchive can import
+	WriteArchive(w io.Writer) error
+	fmt.Stringer
+}
+
+// LocalStore is a host image store, E.G. the provider's
+type LocalStore interface {
+	ImageID(image string) (string, error)
+	SaveImages(w io.Writer, images ...string) error
+}
+
+// Parse returns the Source for ref, images without a source prefix are
+// looked up in store.
+// Sources that may contain images for multiple platforms resolve platform.
+func Parse(ref string, store LocalStore, platform Platform) (Source, error) {
+	switch {
+	case strings.HasPrefix(ref, OCILayoutPrefix):
+		path, image := splitPathImage(strings.TrimPrefix(ref, OCILayoutPrefix))
+		return newOCISource(ref, image, &layoutStore{dir: path}, platform)
+	case strings.HasPrefix(ref, OCIArchivePrefix):
+		path, image := splitPathImage(strings.TrimPrefix(ref, OCIArchivePrefix))
+		return newOCISource(ref, image, &archiveStore{path: path}, platform)
+	case strings.HasPrefix(ref, RegistryPrefix):
+		image := strings.TrimPrefix(ref, RegistryPrefix)
+		if image == "" {
+			return nil, errors.Errorf("invalid image reference %q: missing image", ref)
+		}
+		return newRegistrySource(ref, image, platform)
+	}
+	if ref == "" {
+		return nil, errors.New("image reference must not be empty")
+	}
+	return &localSource{ref: ref, store: store}, nil
+}
+
+// splitPathImage splits <path>[:<image>], paths may not contain ":"
+func splitPathImage(s string) (path, image string) {
+	if i := strings.IndexRune(s, ':'); i != -1 {
+		return s[:i], s[i+1:]
+	}
+	return s, ""
+}
+
+// localSource is an image in a LocalStore
+type localSource struct {
+	ref   string
+	store LocalStore
+}
+
+func (s *localSource) Name() string {
+	return SanitizeImage(s.ref)
+}
+
+func (s *localSource) ID() (string, error) {
+	return s.store.ImageID(s.ref)
+}
+
+func (s *localSource) WriteArchive(w io.Writer) error {
+	return s.store.SaveImages(w, s.ref)
+}
+
+func (s *localSource) String() string {
+	return s.ref
+}
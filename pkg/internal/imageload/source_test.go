@@ -0,0 +1,86 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package imageload
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"sigs.k8s.io/kind/pkg/internal/assert"
+)
+
+type fakeStore struct {
+	images map[string]string
+}
+
+func (f *fakeStore) ImageID(image string) (string, error) {
+	return f.images[image], nil
+}
+
+func (f *fakeStore) SaveImages(w io.Writer, images ...string) error {
+	for _, image := range images {
+		if _, err := io.WriteString(w, image); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+	_, err := Parse("", nil, nativePlatform)
+	assert.ExpectError(t, true, err)
+	_, err = Parse(RegistryPrefix, nil, nativePlatform)
+	assert.ExpectError(t, true, err)
+	_, err = Parse(OCILayoutPrefix+t.TempDir(), nil, nativePlatform)
+	assert.ExpectError(t, true, err)
+
+	store := &fakeStore{images: map[string]string{"app:1.0": "sha256:abc"}}
+	src, err := Parse("app:1.0", store, nativePlatform)
+	assert.ExpectError(t, false, err)
+	assert.StringEqual(t, "docker.io/library/app:1.0", src.Name())
+	assert.StringEqual(t, "app:1.0", src.String())
+	id, err := src.ID()
+	assert.ExpectError(t, false, err)
+	assert.StringEqual(t, "sha256:abc", id)
+	var buf bytes.Buffer
+	assert.ExpectError(t, false, src.WriteArchive(&buf))
+	assert.StringEqual(t, "app:1.0", buf.String())
+}
+
+func TestSplitPathImage(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		In    string
+		Path  string
+		Image string
+	}{
+		{In: "./dir", Path: "./dir"},
+		{In: "./dir:app", Path: "./dir", Image: "app"},
+		{In: "/tmp/app.tar:localhost:5001/app:1.0", Path: "/tmp/app.tar", Image: "localhost:5001/app:1.0"},
+	}
+	for _, tc := range cases {
+		tc := tc // capture range variable
+		t.Run(tc.In, func(t *testing.T) {
+			t.Parallel()
+			path, image := splitPathImage(tc.In)
+			assert.StringEqual(t, tc.Path, path)
+			assert.StringEqual(t, tc.Image, image)
+		})
+	}
+}
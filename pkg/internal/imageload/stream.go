@@ -0,0 +1,200 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package imageload
+
+import (
+	"archive/tar"
+	"io"
+	"path"
+	"strings"
+
+	"sigs.k8s.io/kind/pkg/cluster/nodes"
+	"sigs.k8s.io/kind/pkg/cluster/nodeutils"
+	"sigs.k8s.io/kind/pkg/errors"
+	"sigs.k8s.io/kind/pkg/log"
+)
+
+// LoadArchive streams the image archive written by write into all of
+// selected concurrently, without buffering it on disk. Blobs a node already
+// has in its content store are left out of the archive sent to it.
+// Only platform is imported if set, otherwise all platforms are.
+func LoadArchive(logger log.Logger, selected []nodes.Node, platform string, write func(io.Writer) error) error {
+	if len(selected) == 0 {
+		return nil
+	}
+
+	// query the content nodes already have, so only what is missing is sent
+	existing := make([]map[string]bool, len(selected))
+	fns := []func() error{}
+	for i, node := range selected {
+		i, node := i, node // capture loop variables
+		fns = append(fns, func() error {
+			digests, err := nodeutils.ContentDigests(node)
+			if err != nil {
+				logger.V(1).Infof("Failed to list content of node %s, sending all content: %v", node, err)
+			}
+			existing[i] = digests
+			return nil
+		})
+	}
+	_ = errors.AggregateConcurrent(fns)
+
+	// stream the archive to every node's import concurrently
+	readers := make([]*io.PipeReader, len(selected))
+	outs := make([]*archiveOut, len(selected))
+	for i, node := range selected {
+		pr, pw := io.Pipe()
+		readers[i] = pr
+		outs[i] = newArchiveOut(node.String(), pw, existing[i])
+	}
+	fns = []func() error{}
+	for i, node := range selected {
+		node, pr := node, readers[i] // capture loop variables
+		fns = append(fns, func() error {
+			var err error
+			if platform != "" {
+				err = nodeutils.LoadImageArchiveForPlatform(node, pr, platform)
+			} else {
+				err = nodeutils.LoadImageArchive(node, pr)
+			}
+			if err != nil {
+				// stop sending to this node without failing the others
+				pr.CloseWithError(err)
+				return errors.Wrapf(err, "failed to load image into node %s", node)
+			}
+			// the import may not read trailing padding, drain it so that
+			// the archive is not blocked on this node
+			_, _ = io.Copy(io.Discard, pr)
+			return nil
+		})
+	}
+	fns = append(fns, func() error {
+		pr, pw := io.Pipe()
+		go func() {
+			pw.CloseWithError(write(pw))
+		}()
+		err := fanOut(pr, outs)
+		pr.CloseWithError(err)
+		for _, out := range outs {
+			out.close(err)
+			logger.V(1).Infof("Sent %d bytes to node %s, skipped %d bytes of content it already has", out.sent, out.name, out.skipped)
+		}
+		return err
+	})
+	return errors.AggregateConcurrent(fns)
+}
+
+// archiveOut is one node's copy of a streamed archive
+type archiveOut struct {
+	name    string
+	pw      *io.PipeWriter
+	tw      *tar.Writer
+	skip    map[string]bool
+	err     error
+	sent    int64
+	skipped int64
+}
+
+func newArchiveOut(name string, pw *io.PipeWriter, skip map[string]bool) *archiveOut {
+	return &archiveOut{
+		name: name,
+		pw:   pw,
+		tw:   tar.NewWriter(pw),
+		skip: skip,
+	}
+}
+
+// Write writes to the current archive entry, errors are recorded rather than
+// returned so that one failed node does not fail the rest
+func (o *archiveOut) Write(p []byte) (int, error) {
+	if o.err == nil {
+		_, o.err = o.tw.Write(p)
+	}
+	return len(p), nil
+}
+
+// close finishes the archive, or aborts it with err
+func (o *archiveOut) close(err error) {
+	if err == nil && o.err == nil {
+		err = o.tw.Close()
+	}
+	if err == nil {
+		err = o.err
+	}
+	o.pw.CloseWithError(err)
+}
+
+// fanOut copies the tar archive r into each of outs, leaving out blobs
+// already present on the node of each
+func fanOut(r io.Reader, outs []*archiveOut) error {
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return errors.Wrap(err, "failed to read image archive")
+		}
+		digest := blobDigest(hdr.Name)
+		targets := []io.Writer{}
+		for _, out := range outs {
+			if out.err != nil {
+				continue
+			}
+			if digest != "" && out.skip[digest] {
+				out.skipped += hdr.Size
+				continue
+			}
+			if out.err = out.tw.WriteHeader(hdr); out.err == nil {
+				out.sent += hdr.Size
+				targets = append(targets, out)
+			}
+		}
+		if _, err := io.Copy(io.MultiWriter(targets...), tr); err != nil {
+			return errors.Wrap(err, "failed to read image archive")
+		}
+		if failed(outs) {
+			return outs[0].err
+		}
+	}
+}
+
+// failed returns true if sending to every node failed
+func failed(outs []*archiveOut) bool {
+	for _, out := range outs {
+		if out.err == nil {
+			return false
+		}
+	}
+	return true
+}
+
+// blobDigest returns the digest of the blob at name in an archive, or
+// empty if name is not a blob. Only OCI image layouts address blobs this way,
+// which containerd imports by digest, so present blobs may be left out.
+func blobDigest(name string) string {
+	parts := strings.Split(path.Clean(name), "/")
+	if len(parts) != 3 || parts[0] != "blobs" {
+		return ""
+	}
+	digest := parts[1] + ":" + parts[2]
+	if !digestRegexp.MatchString(digest) {
+		return ""
+	}
+	return digest
+}
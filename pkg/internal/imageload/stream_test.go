@@ -0,0 +1,110 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package imageload
+
+import (
+	"archive/tar"
+	"bytes"
+	"io"
+	"sync"
+	"testing"
+
+	"sigs.k8s.io/kind/pkg/errors"
+	"sigs.k8s.io/kind/pkg/internal/assert"
+)
+
+func TestBlobDigest(t *testing.T) {
+	t.Parallel()
+	for name, expected := range map[string]string{
+		"blobs/sha256/abc123":   "sha256:abc123",
+		"./blobs/sha256/abc123": "sha256:abc123",
+		"blobs/sha256/../x":     "",
+		"index.json":            "",
+		"abc123/layer.tar":      "",
+		"blobs/sha256/ABC":      "",
+	} {
+		assert.StringEqual(t, expected, blobDigest(name))
+	}
+}
+
+func TestFanOut(t *testing.T) {
+	t.Parallel()
+	var archive bytes.Buffer
+	tw := tar.NewWriter(&archive)
+	for _, f := range []struct{ name, content string }{
+		{"blobs/sha256/aa", "layer-a"},
+		{"blobs/sha256/bb", "layer-b"},
+		{"oci-layout", "{}"},
+		{"index.json", "{}"},
+	} {
+		if err := writeTarFile(tw, f.name, []byte(f.content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	skips := []map[string]bool{
+		nil,
+		{"sha256:aa": true},
+		nil,
+	}
+	readers := make([]*io.PipeReader, len(skips))
+	outs := make([]*archiveOut, len(skips))
+	for i, skip := range skips {
+		pr, pw := io.Pipe()
+		readers[i] = pr
+		outs[i] = newArchiveOut("node", pw, skip)
+	}
+	// the last node fails before reading anything
+	readers[2].CloseWithError(errors.New("import failed"))
+
+	results := make([][]byte, 2)
+	var wg sync.WaitGroup
+	for i := range results {
+		i := i // capture loop variable
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], _ = io.ReadAll(readers[i])
+		}()
+	}
+	err := fanOut(&archive, outs)
+	for _, out := range outs {
+		out.close(err)
+	}
+	wg.Wait()
+	assert.ExpectError(t, false, err)
+	assert.ExpectError(t, true, outs[2].err)
+
+	names := func(archive []byte) []string {
+		files := readTar(t, archive)
+		names := []string{}
+		for _, name := range []string{"blobs/sha256/aa", "blobs/sha256/bb", "oci-layout", "index.json"} {
+			if _, ok := files[name]; ok {
+				names = append(names, name)
+			}
+		}
+		return names
+	}
+	assert.DeepEqual(t, []string{"blobs/sha256/aa", "blobs/sha256/bb", "oci-layout", "index.json"}, names(results[0]))
+	assert.DeepEqual(t, []string{"blobs/sha256/bb", "oci-layout", "index.json"}, names(results[1]))
+	if outs[1].skipped != int64(len("layer-a")) {
+		t.Errorf("expected %d skipped bytes, got %d", len("layer-a"), outs[1].skipped)
+	}
+}
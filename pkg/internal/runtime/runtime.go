@@ -19,27 +19,114 @@ package runtime
 
 import (
 	"os"
+	"strconv"
+	"strings"
 
 	"sigs.k8s.io/kind/pkg/cluster"
+	"sigs.k8s.io/kind/pkg/errors"
 	"sigs.k8s.io/kind/pkg/log"
+
+	"sigs.k8s.io/kind/pkg/internal/settings"
 )
 
-// GetDefault selected the default runtime from the environment override
+// ProviderEnv selects the provider, overriding the kind settings
+const ProviderEnv = "KIND_EXPERIMENTAL_PROVIDER"
+
+// ProviderBinaryEnv and ProviderDialectEnv select the binary and dialect of
+// the "cli" provider, overriding the kind settings
+const (
+	ProviderBinaryEnv  = "KIND_EXPERIMENTAL_PROVIDER_BINARY"
+	ProviderDialectEnv = "KIND_EXPERIMENTAL_PROVIDER_DIALECT"
+)
+
+// KubeConfigStrategyEnv selects how KUBECONFIGs are exported without an
+// explicit path, either "isolated" or "merged", overriding the kind settings
+const KubeConfigStrategyEnv = "KIND_KUBECONFIG_STRATEGY"
+
+// GetDefault selected the default runtime from the environment override,
+// or the kind settings file
 func GetDefault(logger log.Logger) cluster.ProviderOption {
-	switch p := os.Getenv("KIND_EXPERIMENTAL_PROVIDER"); p {
+	p, source := os.Getenv(ProviderEnv), ProviderEnv
+	if p == "" {
+		p, source = settings.Get().Provider, "kind settings"
+	}
+	switch p {
 	case "":
 		return nil
 	case "podman":
-		logger.Warn("using podman due to KIND_EXPERIMENTAL_PROVIDER")
+		logger.Warnf("using podman due to %s", source)
 		return cluster.ProviderWithPodman()
 	case "docker":
-		logger.Warn("using docker due to KIND_EXPERIMENTAL_PROVIDER")
+		logger.Warnf("using docker due to %s", source)
 		return cluster.ProviderWithDocker()
 	case "nerdctl", "finch", "nerdctl.lima":
-		logger.Warnf("using %s due to KIND_EXPERIMENTAL_PROVIDER", p)
+		logger.Warnf("using %s due to %s", p, source)
 		return cluster.ProviderWithNerdctl(p)
+	case "cli":
+		s := settings.Get()
+		binary := envOr(ProviderBinaryEnv, s.ProviderBinary)
+		if binary == "" {
+			logger.Warnf("ignoring provider cli from %s, neither %s nor providerBinary in the kind settings is set", source, ProviderBinaryEnv)
+			return nil
+		}
+		dialect, err := cluster.CLIDialectForName(envOr(ProviderDialectEnv, s.ProviderDialect))
+		if err != nil {
+			logger.Warnf("ignoring provider cli from %s: %v", source, err)
+			return nil
+		}
+		if err := overrideDialect(&dialect); err != nil {
+			logger.Warnf("ignoring provider cli from %s: %v", source, err)
+			return nil
+		}
+		logger.Warnf("using %s with the %s dialect due to %s", binary, dialect.Name, source)
+		return cluster.ProviderWithCLI(binary, dialect)
 	default:
-		logger.Warnf("ignoring unknown value %q for KIND_EXPERIMENTAL_PROVIDER", p)
+		logger.Warnf("ignoring unknown value %q for %s", p, source)
 		return nil
 	}
 }
+
+// GetKubeConfigStrategy selects the KUBECONFIG strategy from the environment
+// override, or the kind settings file, or else the isolated strategy
+func GetKubeConfigStrategy(logger log.Logger) cluster.ProviderOption {
+	s, source := os.Getenv(KubeConfigStrategyEnv), KubeConfigStrategyEnv
+	if s == "" {
+		s, source = settings.Get().KubeconfigStrategy, "kind settings"
+	}
+	switch strategy := cluster.KubeConfigStrategy(s); strategy {
+	case "":
+	case cluster.KubeConfigStrategyIsolated, cluster.KubeConfigStrategyMerged:
+		return cluster.ProviderWithKubeConfigStrategy(strategy)
+	default:
+		logger.Warnf("ignoring unknown value %q for %s", s, source)
+	}
+	return cluster.ProviderWithKubeConfigStrategy(cluster.KubeConfigStrategyIsolated)
+}
+
+// envOr returns the value of the environment variable key, or else def
+func envOr(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
+}
+
+// overrideDialect applies the per-field dialect overrides from the
+// environment, so binaries that differ from both known dialects can still be
+// described without a code change
+func overrideDialect(dialect *cluster.CLIDialect) error {
+	if sep, ok := os.LookupEnv("KIND_EXPERIMENTAL_PROVIDER_LABEL_FILTER_SEPARATOR"); ok {
+		dialect.LabelFilterSeparator = sep
+	}
+	if args, ok := os.LookupEnv("KIND_EXPERIMENTAL_PROVIDER_NETWORK_CREATE_ARGS"); ok {
+		dialect.NetworkCreateArgs = strings.Fields(args)
+	}
+	if cgroupns := os.Getenv("KIND_EXPERIMENTAL_PROVIDER_CGROUPNS"); cgroupns != "" {
+		supported, err := strconv.ParseBool(cgroupns)
+		if err != nil {
+			return errors.Wrapf(err, "invalid KIND_EXPERIMENTAL_PROVIDER_CGROUPNS %q", cgroupns)
+		}
+		dialect.SupportsCgroupNS = supported
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package settings loads the user's kind settings file, which provides
+// defaults for kind's flags and environment variables
+package settings
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	yaml "go.yaml.in/yaml/v3"
+
+	"sigs.k8s.io/kind/pkg/errors"
+)
+
+// Settings are the user's kind settings, all fields are optional.
+// They take precedence over built-in defaults, but not over flags or
+// environment variables.
+type Settings struct {
+	// Provider is the node provider, see KIND_EXPERIMENTAL_PROVIDER
+	Provider string `yaml:"provider,omitempty" json:"provider,omitempty"`
+	// ProviderBinary is the binary of the "cli" Provider, see
+	// KIND_EXPERIMENTAL_PROVIDER_BINARY
+	ProviderBinary string `yaml:"providerBinary,omitempty" json:"providerBinary,omitempty"`
+	// ProviderDialect is the dialect of the "cli" Provider, see
+	// KIND_EXPERIMENTAL_PROVIDER_DIALECT
+	ProviderDialect string `yaml:"providerDialect,omitempty" json:"providerDialect,omitempty"`
+	// NodeImage is the node image for nodes without an image in the
+	// cluster config, see `kind create cluster --image`
+	NodeImage string `yaml:"nodeImage,omitempty" json:"nodeImage,omitempty"`
+	// Wait is the default `kind create cluster --wait`, E.G. "5m"
+	Wait string `yaml:"wait,omitempty" json:"wait,omitempty"`
+	// Config is the default `kind create cluster --config` path
+	Config string `yaml:"config,omitempty" json:"config,omitempty"`
+	// Verbosity is the default log --verbosity
+	Verbosity *int32 `yaml:"verbosity,omitempty" json:"verbosity,omitempty"`
+	// KubeconfigStrategy is how kubeconfigs are exported, see
+	// KIND_KUBECONFIG_STRATEGY
+	KubeconfigStrategy string `yaml:"kubeconfigStrategy,omitempty" json:"kubeconfigStrategy,omitempty"`
+
+	// wait is the parsed Wait
+	wait time.Duration
+}
+
+// WaitDuration returns the parsed Wait, 0 if unset
+func (s *Settings) WaitDuration() time.Duration {
+	return s.wait
+}
+
+// Path returns the settings file path, $XDG_CONFIG_HOME/kind/config.yaml,
+// defaulting $XDG_CONFIG_HOME to the user config directory
+func Path() (string, error) {
+	return path(os.Getenv, os.UserConfigDir)
+}
+
+func path(getEnv func(string) string, userConfigDir func() (string, error)) (string, error) {
+	dir := getEnv("XDG_CONFIG_HOME")
+	if dir == "" {
+		var err error
+		dir, err = userConfigDir()
+		if err != nil {
+			return "", errors.Wrap(err, "failed to locate kind settings")
+		}
+	}
+	return filepath.Join(dir, "kind", "config.yaml"), nil
+}
+
+var (
+	loadOnce sync.Once
+	loaded   *Settings
+	loadErr  error
+)
+
+// Load returns the settings from the settings file, empty if there is none.
+// The file is only read once per process.
+func Load() (*Settings, error) {
+	loadOnce.Do(func() {
+		p, err := Path()
+		if err != nil {
+			// without a config directory there are no settings
+			loaded = &Settings{}
+			return
+		}
+		loaded, loadErr = load(p)
+	})
+	return loaded, loadErr
+}
+
+// Get returns the settings like Load, but settings that fail to load are
+// ignored, returning empty settings. The kind command warns about them once
+// on startup, so other callers use Get rather than each failing or warning.
+func Get() *Settings {
+	s, err := Load()
+	if err != nil {
+		return &Settings{}
+	}
+	return s
+}
+
+func load(path string) (*Settings, error) {
+	raw, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return &Settings{}, nil
+	} else if err != nil {
+		return nil, errors.Wrap(err, "failed to read kind settings")
+	}
+	s, err := parse(raw)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid kind settings file %s", path)
+	}
+	return s, nil
+}
+
+func parse(raw []byte) (*Settings, error) {
+	s := &Settings{}
+	d := yaml.NewDecoder(bytes.NewReader(raw))
+	d.KnownFields(true)
+	if err := d.Decode(s); err != nil && err != io.EOF {
+		return nil, err
+	}
+	if s.Wait != "" {
+		wait, err := time.ParseDuration(s.Wait)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid wait")
+		}
+		s.wait = wait
+	}
+	if s.Verbosity != nil && *s.Verbosity < 0 {
+		return nil, errors.Errorf("invalid verbosity %d", *s.Verbosity)
+	}
+	return s, nil
+}
@@ -0,0 +1,127 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package settings
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"sigs.k8s.io/kind/pkg/internal/assert"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+	verbosity := int32(3)
+	cases := []struct {
+		Name         string
+		Raw          string
+		Expected     *Settings
+		ExpectedWait time.Duration
+		ExpectError  bool
+	}{
+		{
+			Name:     "empty",
+			Expected: &Settings{},
+		},
+		{
+			Name: "all",
+			Raw: `provider: podman
+providerBinary: /usr/local/bin/docker-wrapper
+providerDialect: nerdctl
+nodeImage: kindest/node:v1.35.0
+wait: 5m
+config: /home/user/kind.yaml
+verbosity: 3
+kubeconfigStrategy: merged
+`,
+			Expected: &Settings{
+				Provider:           "podman",
+				ProviderBinary:     "/usr/local/bin/docker-wrapper",
+				ProviderDialect:    "nerdctl",
+				NodeImage:          "kindest/node:v1.35.0",
+				Wait:               "5m",
+				Config:             "/home/user/kind.yaml",
+				Verbosity:          &verbosity,
+				KubeconfigStrategy: "merged",
+				wait:               5 * time.Minute,
+			},
+			ExpectedWait: 5 * time.Minute,
+		},
+		{
+			Name:        "unknown field",
+			Raw:         "image: kindest/node:v1.35.0\n",
+			ExpectError: true,
+		},
+		{
+			Name:        "invalid wait",
+			Raw:         "wait: 5\n",
+			ExpectError: true,
+		},
+		{
+			Name:        "invalid verbosity",
+			Raw:         "verbosity: -1\n",
+			ExpectError: true,
+		},
+	}
+	for _, tc := range cases {
+		tc := tc // capture range variable
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+			s, err := parse([]byte(tc.Raw))
+			assert.ExpectError(t, tc.ExpectError, err)
+			if !tc.ExpectError {
+				assert.DeepEqual(t, tc.Expected, s)
+				if s.WaitDuration() != tc.ExpectedWait {
+					t.Errorf("expected wait %v but got %v", tc.ExpectedWait, s.WaitDuration())
+				}
+			}
+		})
+	}
+}
+
+func TestPath(t *testing.T) {
+	t.Parallel()
+	userConfigDir := func() (string, error) { return "/home/user/.config", nil }
+	p, err := path(func(string) string { return "/xdg" }, userConfigDir)
+	assert.ExpectError(t, false, err)
+	assert.StringEqual(t, filepath.Join("/xdg", "kind", "config.yaml"), p)
+
+	p, err = path(func(string) string { return "" }, userConfigDir)
+	assert.ExpectError(t, false, err)
+	assert.StringEqual(t, filepath.Join("/home/user/.config", "kind", "config.yaml"), p)
+
+	_, err = path(func(string) string { return "" }, func() (string, error) { return "", errors.New("no home") })
+	assert.ExpectError(t, true, err)
+}
+
+func TestLoad(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	s, err := load(filepath.Join(dir, "missing.yaml"))
+	assert.ExpectError(t, false, err)
+	assert.DeepEqual(t, &Settings{}, s)
+
+	p := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(p, []byte("provider: [\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	_, err = load(p)
+	assert.ExpectError(t, true, err)
+}
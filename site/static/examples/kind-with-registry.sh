@@ -43,6 +43,9 @@ containerdConfigPatches:
     config_path = "/etc/containerd/certs.d"
 EOF
 
+# Point kubectl at the cluster's kubeconfig, which kind writes to its own file
+eval "$(kind env)"
+
 # 3. Add the registry config to the nodes
 #
 # This is necessary because localhost resolves to loopback addresses that are